@@ -0,0 +1,167 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// The parts staged by the abort reclaim test, each as large as a part other than the last must be.
+const (
+	reclaimPartCount = 3
+	reclaimPartSize  = 5 * 1024 * 1024
+)
+
+// listParts - list the parts uploaded so far to uploadID.
+func listParts(config ServerConfig, bucketName, objectName, uploadID string) ([]objectPart, error) {
+	req, err := newListPartsReq(bucketName, objectName, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(res)
+	if err := verifyStatusListParts(res.StatusCode, http.StatusOK); err != nil {
+		return nil, err
+	}
+	if err := verifyHeaderListParts(res.Header); err != nil {
+		return nil, err
+	}
+	result := listObjectPartsResult{}
+	if err := xmlDecoder(res.Body, &result); err != nil {
+		return nil, err
+	}
+	return result.ObjectParts, nil
+}
+
+// isUploadListed - report whether ListMultipartUploads of bucketName still shows uploadID.
+func isUploadListed(config ServerConfig, bucketName, uploadID string) (bool, error) {
+	req, err := newListMultipartUploadsReq(bucketName, "")
+	if err != nil {
+		return false, err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return false, err
+	}
+	defer drainAndClose(res)
+	if err := verifyStatusListMultipartUploads(res.StatusCode, http.StatusOK); err != nil {
+		return false, err
+	}
+	result := listMultipartUploadsResult{}
+	if err := xmlDecoder(res.Body, &result); err != nil {
+		return false, err
+	}
+	for _, upload := range result.Uploads {
+		if upload.UploadID == uploadID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// mainAbortMultipartUploadReclaimed - Test that aborting an upload with staged parts discards them: the upload
+// is no longer listed and its parts can no longer be listed either.
+func mainAbortMultipartUploadReclaimed(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Multipart (Abort Reclaims Parts):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	objectName := "s3verify/multipart/abort-reclaim"
+	uploadID, err := initiateMultipartUpload(config, bucketName, objectName)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	seed := time.Now().UnixNano()
+	eTags := make([]string, reclaimPartCount)
+	for i := range eTags {
+		// Spin scanBar
+		scanBar(message)
+		eTags[i], err = uploadPart(config, bucketName, objectName, uploadID, i+1, generatedBody(seed+int64(i), reclaimPartSize))
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Every staged part must be listed before the abort.
+	parts, err := listParts(config, bucketName, objectName, uploadID)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if len(parts) != reclaimPartCount {
+		err := fmt.Errorf("Unexpected Number of Parts Listed: wanted %d, got %d", reclaimPartCount, len(parts))
+		printMessage(message, err)
+		return false
+	}
+	for i, part := range parts {
+		if part.PartNumber != i+1 || part.Size != reclaimPartSize || strings.Trim(part.ETag, "\"") != eTags[i] {
+			err := fmt.Errorf("Unexpected Part Listed: wanted number %d of %d bytes with ETag %s, got number %d of %d bytes with ETag %s",
+				i+1, reclaimPartSize, eTags[i], part.PartNumber, part.Size, part.ETag)
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := abortMultipartUpload(config, bucketName, objectName, uploadID); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// The upload must be gone from the listing of uploads in progress.
+	listed, err := isUploadListed(config, bucketName, uploadID)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if listed {
+		err := fmt.Errorf("Unexpected Upload Listed: %s was still in progress after it was aborted", uploadID)
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// And its parts must no longer be reachable.
+	req, err := newListPartsReq(bucketName, objectName, uploadID)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if err := verifyError(res, http.StatusNotFound, "NoSuchUpload"); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -100,6 +100,20 @@ func verifyStatusAbortMultipartUpload(respStatusCode, expectedStatusCode int) er
 	return nil
 }
 
+// abortMultipartUpload - abort an upload and verify that it was removed.
+func abortMultipartUpload(config ServerConfig, bucketName, objectName, uploadID string) error {
+	req, err := newAbortMultipartUploadReq(bucketName, objectName, uploadID)
+	if err != nil {
+		return err
+	}
+	res, err := config.execRequest("DELETE", req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	return abortMultipartUploadVerify(res, http.StatusNoContent, ErrorResponse{})
+}
+
 // TODO: This test does not yet test for tests that should fail. Until there is a workaround for the way
 // AWS maintains the uploadIDs for several hours there is no sure way to test for the right error messages.  // As of now though it is known there is a bug within the Minio Server that returns a shortened form of the
 // error AWS is said to return.
@@ -127,7 +141,7 @@ func mainAbortMultipartUpload(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(res)
+	defer drainAndClose(res)
 	// Spin scanBar
 	scanBar(message)
 	// Verify that the response went through.
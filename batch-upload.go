@@ -0,0 +1,106 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// errUploadCancelled - the result of an object that was skipped because an earlier upload of its batch failed.
+var errUploadCancelled = errors.New("Upload Cancelled: an earlier upload of the batch failed")
+
+// uploadResult - the outcome of uploading one object of a batch.
+type uploadResult struct {
+	object *ObjectInfo
+	err    error // Nil once the object was uploaded.
+}
+
+// uploadBatch - upload every object with upload across config.workers() goroutines.
+// Each object is uploaded once, transient failures are already retried by execRequest and
+// retrying here as well would multiply the attempts. The first failure stops the batch,
+// objects that were not started yet are skipped with errUploadCancelled. One result is
+// returned per object in the order of objects.
+func uploadBatch(config ServerConfig, objects []*ObjectInfo, upload func(*ObjectInfo) error, message string) []uploadResult {
+	results := make([]uploadResult, len(objects))
+	jobs := make(chan int, len(objects))
+	for i := range objects {
+		jobs <- i
+	}
+	close(jobs)
+	// scanBar is not safe for concurrent use so workers report progress to this goroutine.
+	progress := make(chan struct{})
+	// done is closed by the first failed upload so the other workers stop taking jobs.
+	done := make(chan struct{})
+	var cancel sync.Once
+	var wg sync.WaitGroup
+	for worker := 0; worker < config.workers(); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				select {
+				case <-done:
+					results[i] = uploadResult{object: objects[i], err: errUploadCancelled}
+					continue
+				default:
+				}
+				err := upload(objects[i])
+				results[i] = uploadResult{object: objects[i], err: err}
+				if err != nil {
+					cancel.Do(func() { close(done) })
+				}
+				progress <- struct{}{}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(progress)
+	}()
+	for range progress {
+		// Spin scanBar
+		scanBar(message)
+	}
+	return results
+}
+
+// uploadBatchError - an error describing every object of results that could not be uploaded, nil if all were.
+// The reported cause is the first upload that actually failed rather than one cancelled after it.
+func uploadBatchError(results []uploadResult) error {
+	failed := []uploadResult{}
+	var cause *uploadResult
+	for i, result := range results {
+		if result.err == nil {
+			continue
+		}
+		failed = append(failed, result)
+		if cause == nil && result.err != errUploadCancelled {
+			cause = &results[i]
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	if cause == nil {
+		cause = &failed[0]
+	}
+	err := fmt.Errorf("Upload Failed: %d of %d objects could not be uploaded, %s failed: %v",
+		len(failed), len(results), cause.object.Key, cause.err)
+	return err
+}
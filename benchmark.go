@@ -0,0 +1,232 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptrace"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/console"
+)
+
+// benchmarkConfig - what --benchmark drives and for how long.
+type benchmarkConfig struct {
+	mode        string // One of put, get or mixed.
+	duration    time.Duration
+	concurrency int
+	size        int64
+}
+
+// benchmarkResult - the outcome of the operations of one kind made during a benchmark.
+type benchmarkResult struct {
+	op        string
+	latencies []time.Duration
+	bytes     int64
+	errors    int
+}
+
+// add - merge the results of another worker into r.
+func (r *benchmarkResult) add(other benchmarkResult) {
+	r.latencies = append(r.latencies, other.latencies...)
+	r.bytes += other.bytes
+	r.errors += other.errors
+}
+
+// percentile - the latency below which the fraction p of operations completed.
+func (r *benchmarkResult) percentile(p float64) time.Duration {
+	if len(r.latencies) == 0 {
+		return 0
+	}
+	return r.latencies[int(p*float64(len(r.latencies)-1))]
+}
+
+// String - summary of throughput and latency over elapsed.
+func (r *benchmarkResult) String(elapsed time.Duration) string {
+	sort.Sort(durations(r.latencies))
+	seconds := elapsed.Seconds()
+	return fmt.Sprintf("%-4s %8d ops %9.1f ops/s %9.2f MB/s  p50 %v  p90 %v  p99 %v  max %v  errors %d",
+		r.op, len(r.latencies), float64(len(r.latencies))/seconds, float64(r.bytes)/seconds/1e6,
+		r.percentile(0.5), r.percentile(0.9), r.percentile(0.99), r.percentile(1), r.errors)
+}
+
+// durations - sort.Interface for latencies.
+type durations []time.Duration
+
+func (d durations) Len() int           { return len(d) }
+func (d durations) Less(i, j int) bool { return d[i] < d[j] }
+func (d durations) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+
+// benchmarkBucket - the bucket the benchmark objects are written to, removed once it is done.
+func benchmarkBucket() string {
+	return "s3verify-" + globalSuffix + "-benchmark"
+}
+
+// benchmarkKey - the object a worker reads and writes.
+func benchmarkKey(worker int) string {
+	return "s3verify/benchmark/" + strconv.Itoa(worker)
+}
+
+// startTimer - a trace setting start whenever the request asks for a connection. That is after any
+// rate limit wait or retry backoff, so only the attempt that was answered is timed.
+func startTimer(start *time.Time) *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GetConn: func(string) {
+			*start = time.Now()
+		},
+	}
+}
+
+// benchmarkPut - upload data as the object of worker, returning how long it took.
+func benchmarkPut(config ServerConfig, worker int, data []byte) (time.Duration, error) {
+	req, err := newPutObjectStreamReq(benchmarkBucket(), benchmarkKey(worker), bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	var start time.Time
+	req.trace = startTimer(&start)
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		return 0, err
+	}
+	defer drainAndClose(res)
+	if err := verifyStatusPutObject(res.StatusCode, http.StatusOK); err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
+// benchmarkGet - download the object of worker, returning how long it took and its size.
+func benchmarkGet(config ServerConfig, worker int) (time.Duration, int64, error) {
+	req, err := newGetObjectReq(benchmarkBucket(), benchmarkKey(worker), nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	var start time.Time
+	req.trace = startTimer(&start)
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer drainAndClose(res)
+	if err := verifyStatusGetObject(res.StatusCode, http.StatusOK); err != nil {
+		return 0, 0, err
+	}
+	n, err := io.Copy(ioutil.Discard, res.Body)
+	if err != nil {
+		return 0, 0, err
+	}
+	return time.Since(start), n, nil
+}
+
+// benchmarkWorker - run operations until the deadline or until the upload budget is used up.
+func benchmarkWorker(config ServerConfig, bench benchmarkConfig, worker int, data []byte, deadline time.Time) (put, get benchmarkResult) {
+	random := rand.New(rand.NewSource(time.Now().UnixNano() + int64(worker)))
+	for time.Now().Before(deadline) && !globalUploadBudget.isExceeded() {
+		doPut := bench.mode == "put" || (bench.mode == "mixed" && random.Intn(2) == 0)
+		if doPut {
+			latency, err := benchmarkPut(config, worker, data)
+			if err != nil {
+				put.errors++
+				continue
+			}
+			put.latencies = append(put.latencies, latency)
+			put.bytes += int64(len(data))
+			continue
+		}
+		latency, n, err := benchmarkGet(config, worker)
+		if err != nil {
+			get.errors++
+			continue
+		}
+		get.latencies = append(get.latencies, latency)
+		get.bytes += n
+	}
+	return put, get
+}
+
+// mainBenchmark - drive sustained PUT, GET or mixed load and report throughput and latency.
+// Every worker owns one object, uploaded beforehand for GETs, and the bucket is removed afterwards.
+func mainBenchmark(config ServerConfig, bench benchmarkConfig) error {
+	if bench.mode != "put" && bench.mode != "get" && bench.mode != "mixed" {
+		err := fmt.Errorf("Invalid Benchmark: %q must be one of put, get or mixed", bench.mode)
+		return err
+	}
+	if bench.concurrency < 1 || bench.size < 0 || bench.duration <= 0 {
+		err := fmt.Errorf("Invalid Benchmark: --concurrency and --duration must be positive and --size not negative")
+		return err
+	}
+	data := make([]byte, bench.size)
+	if _, err := io.ReadFull(crand.Reader, data); err != nil {
+		return err
+	}
+	if err := putBucket(config, benchmarkBucket()); err != nil {
+		return err
+	}
+	defer func() {
+		if err := removeBucketWithContents(config, benchmarkBucket()); err != nil {
+			console.Errorln(err)
+		}
+	}()
+	// Workers read their own object so GETs never race a missing key.
+	if bench.mode != "put" {
+		for worker := 0; worker < bench.concurrency; worker++ {
+			if _, err := benchmarkPut(config, worker, data); err != nil {
+				return err
+			}
+		}
+	}
+	globalReporter.info(fmt.Sprintf("Benchmarking %s of %d byte objects with %d workers for %v.", bench.mode, bench.size, bench.concurrency, bench.duration))
+	put := benchmarkResult{op: "PUT"}
+	get := benchmarkResult{op: "GET"}
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	start := time.Now()
+	deadline := start.Add(bench.duration)
+	for worker := 0; worker < bench.concurrency; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			workerPut, workerGet := benchmarkWorker(config, bench, worker, data, deadline)
+			mutex.Lock()
+			put.add(workerPut)
+			get.add(workerGet)
+			mutex.Unlock()
+		}(worker)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	if bench.mode != "get" {
+		globalReporter.info(put.String(elapsed))
+	}
+	if bench.mode != "put" {
+		globalReporter.info(get.String(elapsed))
+	}
+	if globalUploadBudget.isExceeded() {
+		console.Errorln("Benchmark stopped early: the --max-upload-bytes budget has been used up.")
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// Test that the time a benchmark request waits for the rate limiter or a retry backoff is not
+// counted as its latency.
+func TestBenchmarkTiming(t *testing.T) {
+	server, _ := newFlakyServer(1)
+	defer server.Close()
+
+	config := newTestConfig(server.URL)
+	config.RetryDelay = 500 * time.Millisecond
+	globalRateLimiter = newRateLimiter(2)
+	defer func() { globalRateLimiter = nil }()
+	globalSuffix = "test"
+
+	data := []byte("s3verify")
+	for i := 0; i < 3; i++ {
+		// The first request is retried after a backoff, the others wait for the rate limiter.
+		started := time.Now()
+		elapsed, err := benchmarkPut(config, 0, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i > 0 && time.Since(started) < 400*time.Millisecond {
+			t.Fatalf("Request %d: Expected the rate limiter to delay the request", i+1)
+		}
+		if elapsed > 250*time.Millisecond {
+			t.Errorf("Request %d: Expected only the request to be timed, got %v", i+1, elapsed)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+)
+
+// Number of bytes shown on either side of the first difference between two bodies.
+const bodyDiffContext = 16
+
+// verifyBodyEqual - verify received matches expected, describing where they first differ if not.
+func verifyBodyEqual(expected, received []byte) error {
+	return verifyBodyEqualAt(expected, received, 0)
+}
+
+// verifyBodyEmpty - verify nothing was received, for responses that must not carry a body.
+func verifyBodyEmpty(received []byte) error {
+	return verifyBodyEqual([]byte{}, received)
+}
+
+// verifyBodyEqualAt - verify received matches expected where both start at baseOffset of a larger body.
+//
+// Rather than printing both bodies, which is useless for large objects, the error gives the offset of
+// the first differing byte and a short hex dump of both bodies around it. The offset makes it possible
+// to tell corruption at a multipart part boundary from corruption at an arbitrary position.
+func verifyBodyEqualAt(expected, received []byte, baseOffset int64) error {
+	length := len(expected)
+	if len(received) < length {
+		length = len(received)
+	}
+	offset := 0
+	for offset < length && expected[offset] == received[offset] {
+		offset++
+	}
+	if offset == len(expected) && offset == len(received) {
+		return nil
+	}
+	start := offset - bodyDiffContext
+	if start < 0 {
+		start = 0
+	}
+	err := BodyMismatchError{
+		Offset:         baseOffset + int64(offset),
+		ExpectedLength: baseOffset + int64(len(expected)),
+		GotLength:      baseOffset + int64(len(received)),
+		Expected:       hexWindow(expected, start, offset+bodyDiffContext, baseOffset),
+		Got:            hexWindow(received, start, offset+bodyDiffContext, baseOffset),
+	}
+	return err
+}
+
+// hexWindow - hex dump body[start:end], clipped to the body, labelled with its absolute offsets.
+func hexWindow(body []byte, start, end int, baseOffset int64) string {
+	if end > len(body) {
+		end = len(body)
+	}
+	if start > end {
+		start = end
+	}
+	return fmt.Sprintf("[%d:%d] % x", baseOffset+int64(start), baseOffset+int64(end), body[start:end])
+}
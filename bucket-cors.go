@@ -0,0 +1,346 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The origins the CORS test allows and refuses.
+const (
+	corsAllowedOrigin    = "https://s3verify.example.com"
+	corsDisallowedOrigin = "https://other.example.com"
+	corsMaxAgeSeconds    = 3000
+)
+
+// newPutBucketCorsReq - Create a new HTTP request for the PutBucketCors API.
+func newPutBucketCorsReq(bucketName string, corsConfig corsConfiguration) (Request, error) {
+	// putBucketCorsReq - a new HTTP request for PutBucketCors.
+	var putBucketCorsReq = Request{
+		customHeader: http.Header{},
+	}
+
+	// Set the bucketName.
+	putBucketCorsReq.bucketName = bucketName
+
+	// Set the query values.
+	urlValues := make(url.Values)
+	urlValues.Set("cors", "")
+	putBucketCorsReq.queryValues = urlValues
+
+	corsConfigBytes, err := xml.Marshal(corsConfig)
+	if err != nil {
+		return Request{}, err
+	}
+	reader := bytes.NewReader(corsConfigBytes)
+	md5Sum, sha256Sum, contentLength, err := computeHash(reader)
+	if err != nil {
+		return Request{}, err
+	}
+
+	// Set the body, header and content length.
+	putBucketCorsReq.contentBody = reader
+	putBucketCorsReq.contentLength = contentLength
+	putBucketCorsReq.customHeader.Set("Content-MD5", base64.StdEncoding.EncodeToString(md5Sum))
+	putBucketCorsReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
+	putBucketCorsReq.customHeader.Set("User-Agent", appUserAgent)
+
+	return putBucketCorsReq, nil
+}
+
+// newGetBucketCorsReq - Create a new HTTP request for the GetBucketCors API.
+func newGetBucketCorsReq(bucketName string) (Request, error) {
+	return newBucketCorsReq(bucketName)
+}
+
+// newDeleteBucketCorsReq - Create a new HTTP request for the DeleteBucketCors API.
+func newDeleteBucketCorsReq(bucketName string) (Request, error) {
+	return newBucketCorsReq(bucketName)
+}
+
+// newBucketCorsReq - a request without a body on the cors subresource of bucketName.
+func newBucketCorsReq(bucketName string) (Request, error) {
+	// bucketCorsReq - a new HTTP request on the cors subresource.
+	var bucketCorsReq = Request{
+		customHeader: http.Header{},
+	}
+
+	// Set the bucketName.
+	bucketCorsReq.bucketName = bucketName
+
+	// Set the query values.
+	urlValues := make(url.Values)
+	urlValues.Set("cors", "")
+	bucketCorsReq.queryValues = urlValues
+
+	// No body is sent with GET and DELETE requests.
+	reader := bytes.NewReader([]byte{})
+	_, sha256Sum, _, err := computeHash(reader)
+	if err != nil {
+		return Request{}, err
+	}
+
+	// Set the headers.
+	bucketCorsReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
+	bucketCorsReq.customHeader.Set("User-Agent", appUserAgent)
+
+	return bucketCorsReq, nil
+}
+
+// getBucketCors - read back the CORS configuration of bucketName.
+func getBucketCors(config ServerConfig, bucketName string) (corsConfiguration, error) {
+	corsConfig := corsConfiguration{}
+	req, err := newGetBucketCorsReq(bucketName)
+	if err != nil {
+		return corsConfig, err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return corsConfig, err
+	}
+	defer drainAndClose(res)
+	if res.StatusCode != http.StatusOK {
+		err := StatusMismatchError{Expected: http.StatusOK, Got: res.StatusCode}
+		return corsConfig, err
+	}
+	if err := verifyStandardHeaders(res.Header); err != nil {
+		return corsConfig, err
+	}
+	if err := xmlDecoder(res.Body, &corsConfig); err != nil {
+		return corsConfig, err
+	}
+	return corsConfig, nil
+}
+
+// bucketCorsVerify - Verify the response to a PutBucketCors or DeleteBucketCors request.
+func bucketCorsVerify(res *http.Response, expectedStatusCode int) error {
+	if res.StatusCode != expectedStatusCode {
+		err := StatusMismatchError{Expected: expectedStatusCode, Got: res.StatusCode}
+		return err
+	}
+	if err := verifyStandardHeaders(res.Header); err != nil {
+		return err
+	}
+	return nil
+}
+
+// verifyCorsRules - verify the CORS rules read back are the ones that were set.
+func verifyCorsRules(received, expected []corsRule) error {
+	if len(received) != len(expected) {
+		err := fmt.Errorf("Unexpected Number of CORS Rules Received: wanted %v, got %v", len(expected), len(received))
+		return err
+	}
+	for i, rule := range expected {
+		got := received[i]
+		if strings.Join(got.AllowedOrigins, ",") != strings.Join(rule.AllowedOrigins, ",") {
+			err := fmt.Errorf("Unexpected AllowedOrigin Received: wanted %v, got %v", rule.AllowedOrigins, got.AllowedOrigins)
+			return err
+		}
+		if strings.Join(got.AllowedMethods, ",") != strings.Join(rule.AllowedMethods, ",") {
+			err := fmt.Errorf("Unexpected AllowedMethod Received: wanted %v, got %v", rule.AllowedMethods, got.AllowedMethods)
+			return err
+		}
+		if got.MaxAgeSeconds != rule.MaxAgeSeconds {
+			err := fmt.Errorf("Unexpected MaxAgeSeconds Received: wanted %v, got %v", rule.MaxAgeSeconds, got.MaxAgeSeconds)
+			return err
+		}
+	}
+	return nil
+}
+
+// sendCorsPreflight - send the OPTIONS request a browser sends before a cross-origin GET of objectName
+// from origin. Browsers do not sign preflight requests, so neither does this.
+func sendCorsPreflight(config ServerConfig, bucketName, objectName, origin string) (*http.Response, error) {
+	targetURL, err := config.targetURL(bucketName, objectName, nil)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("OPTIONS", targetURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	req.Header.Set("User-Agent", appUserAgent)
+	return config.Client.Do(req)
+}
+
+// verifyCorsPreflightAllowed - verify a preflight from an allowed origin is answered with the rule's headers.
+func verifyCorsPreflightAllowed(res *http.Response, origin string, maxAgeSeconds int) error {
+	if res.StatusCode != http.StatusOK {
+		err := StatusMismatchError{Expected: http.StatusOK, Got: res.StatusCode}
+		return err
+	}
+	if allowOrigin := res.Header.Get("Access-Control-Allow-Origin"); allowOrigin != origin && allowOrigin != "*" {
+		err := HeaderMismatchError{Header: "Access-Control-Allow-Origin", Expected: origin, Got: allowOrigin}
+		return err
+	}
+	if maxAge := res.Header.Get("Access-Control-Max-Age"); maxAge != strconv.Itoa(maxAgeSeconds) {
+		err := HeaderMismatchError{Header: "Access-Control-Max-Age", Expected: strconv.Itoa(maxAgeSeconds), Got: maxAge}
+		return err
+	}
+	return nil
+}
+
+// verifyCorsPreflightRejected - verify a preflight from an origin no rule allows is refused.
+func verifyCorsPreflightRejected(res *http.Response) error {
+	if res.StatusCode != http.StatusForbidden {
+		err := StatusMismatchError{Expected: http.StatusForbidden, Got: res.StatusCode}
+		return err
+	}
+	if allowOrigin := res.Header.Get("Access-Control-Allow-Origin"); allowOrigin != "" {
+		err := HeaderMismatchError{Header: "Access-Control-Allow-Origin", Expected: "", Got: allowOrigin}
+		return err
+	}
+	return nil
+}
+
+// mainBucketCors - Test setting and reading back a CORS rule, the preflight responses it causes
+// and deleting it.
+func mainBucketCors(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] BucketCors:", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/cors/object",
+		Body: generatedBody(time.Now().UnixNano(), 60),
+	}
+	if _, err := putObject(config, bucketName, object); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	rule := corsRule{
+		AllowedOrigins: []string{corsAllowedOrigin},
+		AllowedMethods: []string{"GET"},
+		MaxAgeSeconds:  corsMaxAgeSeconds,
+	}
+	// Spin scanBar
+	scanBar(message)
+	req, err := newPutBucketCorsReq(bucketName, corsConfiguration{CORSRules: []corsRule{rule}})
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if res.StatusCode != http.StatusOK && isNotImplemented(res) {
+		if err := removeObject(config, bucketName, object.Key); err != nil {
+			printMessage(message, err)
+			return false
+		}
+		printMessage(message, nil)
+		printDetail("Skipped: server does not support bucket CORS")
+		return true
+	}
+	if err := bucketCorsVerify(res, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	corsConfig, err := getBucketCors(config, bucketName)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyCorsRules(corsConfig.CORSRules, []corsRule{rule}); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// A preflight from the allowed origin is answered with the rule's headers.
+	preflightRes, err := sendCorsPreflight(config, bucketName, object.Key, corsAllowedOrigin)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(preflightRes)
+	if err := verifyCorsPreflightAllowed(preflightRes, corsAllowedOrigin, corsMaxAgeSeconds); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// A preflight from any other origin is refused.
+	rejectedRes, err := sendCorsPreflight(config, bucketName, object.Key, corsDisallowedOrigin)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(rejectedRes)
+	if err := verifyCorsPreflightRejected(rejectedRes); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	deleteReq, err := newDeleteBucketCorsReq(bucketName)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	deleteRes, err := config.execRequest("DELETE", deleteReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(deleteRes)
+	if err := bucketCorsVerify(deleteRes, http.StatusNoContent); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// The configuration is gone once deleted.
+	getReq, err := newGetBucketCorsReq(bucketName)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	getRes, err := config.execRequest("GET", getReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(getRes)
+	if err := verifyError(getRes, http.StatusNotFound, "NoSuchCORSConfiguration"); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
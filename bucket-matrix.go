@@ -0,0 +1,184 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// bucketCapability - a set of configurations a bucket was created with.
+type bucketCapability uint
+
+const (
+	capVersioned  bucketCapability = 1 << iota // Versioning is enabled.
+	capObjectLock                              // Created with object lock enabled, which implies versioning.
+	capEncrypted                               // Objects are encrypted with SSE-S3 by default.
+)
+
+// bucketVariant - a bucket configuration created up front by the bucket matrix.
+type bucketVariant struct {
+	Tag          string
+	Capabilities bucketCapability
+	// Create and configure the bucket, returns false if the server does not support the configuration.
+	Create func(config ServerConfig, bucketName string) (bool, error)
+}
+
+// bucketVariants - every configuration in the bucket matrix. The plain bucket is s3verifyBuckets[0].
+var bucketVariants = []bucketVariant{
+	bucketVariant{Tag: "versioned", Capabilities: capVersioned, Create: createVersionedBucket},
+	bucketVariant{Tag: "locked", Capabilities: capVersioned | capObjectLock, Create: createObjectLockBucket},
+	bucketVariant{Tag: "encrypted", Capabilities: capEncrypted, Create: createEncryptedBucket},
+}
+
+// isNotImplemented - report whether the server rejected a request as not supported. The body is
+// buffered and put back, so the response can still be verified afterwards.
+func isNotImplemented(res *http.Response) bool {
+	if res.StatusCode == http.StatusNotImplemented {
+		return true
+	}
+	resBody, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+	if err != nil {
+		return false
+	}
+	errResponse := ErrorResponse{}
+	if err := xmlDecoder(bytes.NewReader(resBody), &errResponse); err != nil {
+		return false
+	}
+	return errResponse.Code == "NotImplemented"
+}
+
+// createVersionedBucket - create a bucket with versioning enabled.
+func createVersionedBucket(config ServerConfig, bucketName string) (bool, error) {
+	if err := putBucket(config, bucketName); err != nil {
+		return false, err
+	}
+	req, err := newPutBucketVersioningReq(bucketName, "Enabled")
+	if err != nil {
+		return false, err
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		return false, err
+	}
+	defer drainAndClose(res)
+	if res.StatusCode != http.StatusOK && isNotImplemented(res) {
+		return false, removeBucket(config, bucketName)
+	}
+	return true, putBucketVersioningVerify(res, http.StatusOK)
+}
+
+// createObjectLockBucket - create a bucket with object lock enabled.
+func createObjectLockBucket(config ServerConfig, bucketName string) (bool, error) {
+	req, err := newPutBucketReq(config.Region, bucketName)
+	if err != nil {
+		return false, err
+	}
+	req.customHeader.Set("X-Amz-Bucket-Object-Lock-Enabled", "true")
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		return false, err
+	}
+	defer drainAndClose(res)
+	if res.StatusCode != http.StatusOK && isNotImplemented(res) {
+		return false, nil
+	}
+	if err := putBucketVerify(res, bucketName, http.StatusOK, ErrorResponse{}); err != nil {
+		return false, err
+	}
+	// A server that ignores the header creates a plain bucket.
+	if !bucketHasObjectLock(config, bucketName) {
+		return false, removeBucket(config, bucketName)
+	}
+	return true, nil
+}
+
+// createEncryptedBucket - create a bucket that encrypts objects with SSE-S3 by default.
+func createEncryptedBucket(config ServerConfig, bucketName string) (bool, error) {
+	if err := putBucket(config, bucketName); err != nil {
+		return false, err
+	}
+	encryptionConfig := sseConfiguration{
+		Rules: []sseRule{
+			sseRule{
+				ApplyServerSideEncryptionByDefault: applySSEByDefault{
+					SSEAlgorithm: "AES256",
+				},
+			},
+		},
+	}
+	req, err := newPutBucketEncryptionReq(bucketName, encryptionConfig)
+	if err != nil {
+		return false, err
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		return false, err
+	}
+	defer drainAndClose(res)
+	if res.StatusCode != http.StatusOK && isNotImplemented(res) {
+		return false, removeBucket(config, bucketName)
+	}
+	return true, putBucketEncryptionVerify(res, http.StatusOK)
+}
+
+// findBucket - find an s3verify created bucket with all of the given capabilities.
+func findBucket(capabilities bucketCapability) (BucketInfo, error) {
+	for _, bucket := range s3verifyBuckets {
+		if bucket.Capabilities&capabilities == capabilities {
+			return bucket, nil
+		}
+	}
+	err := fmt.Errorf("No Bucket Available: the server did not support the configuration needed by this test")
+	return BucketInfo{}, err
+}
+
+// mainPutBucketMatrix - create a bucket for each configuration in the bucket matrix, so tests of
+// configuration dependent behavior do not have to reconfigure a shared bucket mid-run.
+func mainPutBucketMatrix(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] PutBucket (Configuration Matrix):", curTest, globalTotalNumTest)
+	unsupported := []string{}
+	for _, variant := range bucketVariants {
+		// Spin scanBar
+		scanBar(message)
+		bucket := BucketInfo{
+			Name:         "s3verify-" + globalSuffix + "-" + variant.Tag,
+			Capabilities: variant.Capabilities,
+		}
+		supported, err := variant.Create(config, bucket.Name)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		if !supported {
+			unsupported = append(unsupported, variant.Tag)
+			continue
+		}
+		// Save the bucket for tests to find by capability.
+		s3verifyBuckets = append(s3verifyBuckets, bucket)
+	}
+	printMessage(message, nil)
+	if len(unsupported) > 0 {
+		printDetail("Not supported by the server: " + strings.Join(unsupported, ", "))
+	}
+	return true
+}
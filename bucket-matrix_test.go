@@ -0,0 +1,97 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Test that checking for NotImplemented leaves the error body for the checks that follow.
+func TestIsNotImplementedKeepsBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeS3Error(w, http.StatusBadRequest, "InvalidRequest", "")
+	}))
+	defer server.Close()
+
+	res, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer drainAndClose(res)
+	if isNotImplemented(res) {
+		t.Fatal("Expected InvalidRequest not to be reported as NotImplemented")
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), "<Code>InvalidRequest</Code>") {
+		t.Errorf("Expected the error body to be readable again, got %q", body)
+	}
+}
+
+// Test that a bucket created with object lock only counts as locked if GetObjectLockConfiguration
+// says so, and that a server ignoring the header has its plain bucket removed again.
+func TestCreateObjectLockBucket(t *testing.T) {
+	testCases := []struct {
+		lockEnabled bool
+		supported   bool
+		removed     bool
+	}{
+		{true, true, false},
+		// The header was ignored and a plain bucket created.
+		{false, false, true},
+	}
+	for i, testCase := range testCases {
+		removed := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, lockQuery := r.URL.Query()["object-lock"]
+			switch {
+			case r.Method == "PUT":
+				writeS3Headers(w)
+				w.Header().Set("Location", strings.TrimSuffix(r.URL.Path, "/"))
+			case r.Method == "GET" && lockQuery && testCase.lockEnabled:
+				writeS3Headers(w)
+				w.Write([]byte(`<ObjectLockConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><ObjectLockEnabled>Enabled</ObjectLockEnabled></ObjectLockConfiguration>`))
+			case r.Method == "GET" && lockQuery:
+				writeS3Error(w, http.StatusNotFound, "ObjectLockConfigurationNotFoundError", "")
+			case r.Method == "DELETE":
+				removed = true
+				writeS3Headers(w)
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				writeS3Error(w, http.StatusBadRequest, "InvalidRequest", "")
+			}
+		}))
+		config := newTestConfig(server.URL)
+		supported, err := createObjectLockBucket(config, "s3verify-test-locked")
+		server.Close()
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if supported != testCase.supported {
+			t.Errorf("Test %d: Expected supported to be %v, got %v", i+1, testCase.supported, supported)
+		}
+		if removed != testCase.removed {
+			t.Errorf("Test %d: Expected removed to be %v, got %v", i+1, testCase.removed, removed)
+		}
+	}
+}
@@ -22,38 +22,15 @@ import (
 	"github.com/minio/minio-go"
 )
 
-// cleanObjects - use minio-go to remove any s3verify created objects.
+// cleanObjects - remove every object, version and incomplete upload in an s3verify created bucket.
 func cleanObjects(config ServerConfig, bucketName string) error {
 	message := "CleanUp (Removing Objects):"
 	// Spin scanBar
 	scanBar(message)
-	// Create new minio-go host from config.
-	hostURL, err := url.Parse(config.Endpoint)
-	if err != nil {
+	if err := emptyBucket(config, bucketName); err != nil {
+		printMessage(message, err)
 		return err
 	}
-	secure := false
-	if hostURL.Scheme == "https" {
-		secure = true
-	}
-	client, err := minio.New(hostURL.Host, config.Access, config.Secret, secure)
-	if err != nil {
-		return err
-	}
-	doneCh := make(chan struct{})
-	defer close(doneCh)
-
-	// Only remove s3verify created objects.
-	objectCh := client.ListObjects(bucketName, "s3verify/", true, doneCh)
-	for object := range objectCh {
-		// Spin scanBar
-		scanBar(message)
-		err := client.RemoveObject(bucketName, object.Key)
-		if err != nil {
-			// Do not stop on errors.
-			continue
-		}
-	}
 	printMessage(message, nil)
 	return nil
 }
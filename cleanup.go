@@ -0,0 +1,225 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// runBucketTags - the tags after s3verify-<suffix>- of the buckets a run may create outside the bucket matrix.
+var runBucketTags = []string{"batch", "benchmark", "bucket-key", "copy-versioned", "empty", "probe", "selfcheck", "website"}
+
+// runBucketNames - the name of every bucket a run with suffix may create. Only these exact names are
+// swept, the buckets of other runs whose ids start with suffix are left alone.
+func runBucketNames(suffix string) []string {
+	bucketName := "s3verify-" + suffix
+	// PutBucket numbers its buckets without a separator.
+	names := []string{bucketName, bucketName + "0", bucketName + "1"}
+	for _, variant := range bucketVariants {
+		names = append(names, bucketName+"-"+variant.Tag)
+	}
+	for _, tag := range runBucketTags {
+		names = append(names, bucketName+"-"+tag)
+	}
+	return names
+}
+
+// cleanupSweep - what a cleanup removed and every removal that failed.
+type cleanupSweep struct {
+	objects  int
+	buckets  int
+	failures []error
+}
+
+// listBucketNames - the names of every bucket the credentials can list.
+func listBucketNames(config ServerConfig) ([]string, error) {
+	req, err := newListBucketsReq()
+	if err != nil {
+		return nil, err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(res)
+	if err := verifyStatusListBuckets(res.StatusCode, http.StatusOK); err != nil {
+		return nil, err
+	}
+	result := listAllMyBucketsResult{}
+	if err := xmlDecoder(res.Body, &result); err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, bucket := range result.Buckets.Bucket {
+		names = append(names, bucket.Name)
+	}
+	return names, nil
+}
+
+// cleanupObject - remove objectName and verify a HEAD on it now answers 404.
+func cleanupObject(config ServerConfig, bucketName, objectName string) error {
+	if err := removeObject(config, bucketName, objectName); err != nil {
+		return err
+	}
+	return verifyObjectRemoved(config, bucketName, objectName)
+}
+
+// verifyObjectRemoved - verify a HEAD on objectName answers 404.
+func verifyObjectRemoved(config ServerConfig, bucketName, objectName string) error {
+	req, err := newHeadObjectReq(bucketName, objectName)
+	if err != nil {
+		return err
+	}
+	res, err := config.execRequest("HEAD", req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	if res.StatusCode != http.StatusNotFound {
+		err := fmt.Errorf("Object Not Removed: HEAD on %s/%s wanted %v, got %v", bucketName, objectName, http.StatusNotFound, res.StatusCode)
+		return err
+	}
+	return nil
+}
+
+// sweepBucket - remove knownKeys, then everything else in bucketName including versions, delete markers,
+// incomplete uploads and locked versions, then the bucket. Failures are recorded in sweep and do not stop
+// the remaining buckets from being swept.
+func sweepBucket(config ServerConfig, bucketName string, knownKeys []string, sweep *cleanupSweep, message string) {
+	removed := make(map[string]bool)
+	for _, objectName := range knownKeys {
+		if removed[objectName] {
+			continue
+		}
+		removed[objectName] = true
+		// Spin scanBar
+		scanBar(message)
+		if err := cleanupObject(config, bucketName, objectName); err != nil {
+			sweep.failures = append(sweep.failures, err)
+			continue
+		}
+		sweep.objects++
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Objects a failed test never recorded are still found by listing.
+	if err := emptyBucket(config, bucketName); err != nil {
+		sweep.failures = append(sweep.failures, fmt.Errorf("Unable to empty %s: %v", bucketName, err))
+		return
+	}
+	if err := removeBucket(config, bucketName); err != nil {
+		sweep.failures = append(sweep.failures, fmt.Errorf("Unable to remove %s: %v", bucketName, err))
+		return
+	}
+	sweep.buckets++
+}
+
+// reportCleanup - print the outcome of a sweep, listing every failure.
+func reportCleanup(message string, sweep *cleanupSweep) bool {
+	if len(sweep.failures) > 0 {
+		err := fmt.Errorf("%d removals failed, %d objects and %d buckets were removed", len(sweep.failures), sweep.objects, sweep.buckets)
+		printMessage(message, err)
+		for _, failure := range sweep.failures {
+			printDetail(failure.Error())
+		}
+		return false
+	}
+	printMessage(message, nil)
+	printDetail(fmt.Sprintf("Removed %d objects and %d buckets.", sweep.objects, sweep.buckets))
+	return true
+}
+
+// sweepBuckets - sweep every bucket in bucketNames that still exists, removing knownKeys first.
+// Buckets a test already removed are skipped.
+func sweepBuckets(config ServerConfig, bucketNames []string, knownKeys map[string][]string, sweep *cleanupSweep, message string) error {
+	existing, err := listBucketNames(config)
+	if err != nil {
+		return err
+	}
+	exists := make(map[string]bool)
+	for _, bucketName := range existing {
+		exists[bucketName] = true
+	}
+	for _, bucketName := range bucketNames {
+		if !exists[bucketName] {
+			continue
+		}
+		// A bucket named twice is only swept once.
+		exists[bucketName] = false
+		sweepBucket(config, bucketName, knownKeys[bucketName], sweep, message)
+	}
+	return nil
+}
+
+// mainCleanup - remove every object and bucket this run created. The objects recorded by the tests are
+// removed first, then anything left under cleanupPrefixes, then the buckets.
+func mainCleanup(config ServerConfig) bool {
+	message := "CleanUp (Run):"
+	// Spin scanBar
+	scanBar(message)
+	sweep := &cleanupSweep{}
+	// Objects are stored with the bucket tests put them in.
+	knownKeys := make(map[string][]string)
+	addKeys := func(buckets []BucketInfo, index int, store *objectStore) {
+		if len(buckets) <= index {
+			return
+		}
+		for _, object := range store.snapshot() {
+			knownKeys[buckets[index].Name] = append(knownKeys[buckets[index].Name], object.Key)
+		}
+	}
+	addKeys(s3verifyBuckets, 0, s3verifyObjects)
+	addKeys(s3verifyBuckets, 1, copyObjects)
+	addKeys(preparedBuckets, 0, preparedObjects)
+	bucketNames := []string{}
+	for _, bucket := range append(append([]BucketInfo{}, s3verifyBuckets...), preparedBuckets...) {
+		bucketNames = append(bucketNames, bucket.Name)
+	}
+	if err := sweepBuckets(config, bucketNames, knownKeys, sweep, message); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	return reportCleanup(message, sweep)
+}
+
+// mainCleanupOnly - remove what an earlier run left behind, found by listing rather than from a run.
+// The buckets and objects recorded in manifestPath are swept if it is given, otherwise every bucket
+// a run with suffix may have created.
+func mainCleanupOnly(config ServerConfig, suffix, manifestPath string) bool {
+	message := "CleanUp (Previous Run):"
+	// Spin scanBar
+	scanBar(message)
+	sweep := &cleanupSweep{}
+	if manifestPath != "" {
+		bucketNames, knownKeys, err := readManifest(manifestPath)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		if err := sweepBuckets(config, bucketNames, knownKeys, sweep, message); err != nil {
+			printMessage(message, err)
+			return false
+		}
+		return reportCleanup(message, sweep)
+	}
+	if err := sweepBuckets(config, runBucketNames(suffix), nil, sweep, message); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	return reportCleanup(message, sweep)
+}
@@ -0,0 +1,126 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// verifyBodyCompleteMultipartUploadError - verify the body returned is one of the acceptable errors.
+func verifyBodyCompleteMultipartUploadError(resBody io.Reader, expectedCodes []string) error {
+	errResponse := ErrorResponse{}
+	if err := xmlDecoder(resBody, &errResponse); err != nil {
+		return err
+	}
+	for _, code := range expectedCodes {
+		if errResponse.Code == code {
+			return nil
+		}
+	}
+	err := fmt.Errorf("Unexpected Error Response: wanted one of %v, got %v", strings.Join(expectedCodes, ", "), errResponse.Code)
+	return err
+}
+
+// completeMultipartUploadErrorVerify - verify that a malformed completion was rejected as expected.
+func completeMultipartUploadErrorVerify(res *http.Response, expectedStatusCode int, expectedCodes []string) error {
+	if err := verifyStatusCompleteMultipartUpload(res.StatusCode, expectedStatusCode); err != nil {
+		return err
+	}
+	if err := verifyHeaderCompleteMultipartUpload(res.Header); err != nil {
+		return err
+	}
+	if err := verifyBodyCompleteMultipartUploadError(res.Body, expectedCodes); err != nil {
+		return err
+	}
+	return nil
+}
+
+// completeMultipartUploadInvalid - initiate a new upload, attempt to complete it with the given parts
+// and verify the completion is rejected. The upload is aborted however the check ends.
+func completeMultipartUploadInvalid(config ServerConfig, bucketName, objectName string, complete *completeMultipartUpload, expectedCodes []string) error {
+	uploadID, err := initiateMultipartUpload(config, bucketName, objectName)
+	if err != nil {
+		return err
+	}
+	// Set once the abort below has been attempted and verified.
+	aborted := false
+	defer func() {
+		if !aborted {
+			abortMultipartUpload(config, bucketName, objectName, uploadID)
+		}
+	}()
+	req, err := newCompleteMultipartUploadReq(bucketName, objectName, uploadID, complete)
+	if err != nil {
+		return err
+	}
+	res, err := config.execRequest("POST", req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	if err := completeMultipartUploadErrorVerify(res, http.StatusBadRequest, expectedCodes); err != nil {
+		return err
+	}
+	// The failed completion must leave the upload in place to be aborted.
+	aborted = true
+	return abortMultipartUpload(config, bucketName, objectName, uploadID)
+}
+
+// mainCompleteMultipartUploadInvalid - Complete Multipart Upload API test with malformed part lists.
+func mainCompleteMultipartUploadInvalid(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Multipart (Complete-Upload Invalid Parts):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	// All multipart operations take place in the s3verify created buckets.
+	bucketName := s3verifyBuckets[0].Name
+	objectName := "s3verify/multipart/invalid-complete"
+	// Completing with an empty parts list.
+	if err := completeMultipartUploadInvalid(config, bucketName, objectName, &completeMultipartUpload{},
+		[]string{"MalformedXML", "InvalidPart"}); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Completing with a parts list when no parts were ever uploaded.
+	complete := &completeMultipartUpload{
+		Parts: []completePart{
+			completePart{
+				PartNumber: 1,
+				ETag:       "d41d8cd98f00b204e9800998ecf8427e",
+			},
+		},
+	}
+	if err := completeMultipartUploadInvalid(config, bucketName, objectName, complete,
+		[]string{"InvalidPart"}); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Neither failed completion may have created the object.
+	if err := listObjectVisibility(config, bucketName, objectName, false); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -24,6 +24,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 )
 
 // newCompleteMultipartUploadReq - Create a new Request for complete-multipart API.
@@ -65,11 +67,12 @@ func newCompleteMultipartUploadReq(bucketName, objectName, uploadID string, comp
 // TODO: So far only valid multipart requests are used. Implement tests that SHOULD fail.
 //
 // completeMultipartUploadVerify - verify tthat the response returned matches what is expected.
-func completeMultipartUploadVerify(res *http.Response, expectedStatusCode int) error {
+// The ETag of the completed object is only checked if expectedETag is set.
+func completeMultipartUploadVerify(res *http.Response, expectedStatusCode int, expectedETag string) error {
 	if err := verifyStatusCompleteMultipartUpload(res.StatusCode, expectedStatusCode); err != nil {
 		return err
 	}
-	if err := verifyBodyCompleteMultipartUpload(res.Body); err != nil {
+	if err := verifyBodyCompleteMultipartUpload(res.Body, expectedETag); err != nil {
 		return err
 	}
 	if err := verifyHeaderCompleteMultipartUpload(res.Header); err != nil {
@@ -88,14 +91,32 @@ func verifyStatusCompleteMultipartUpload(respStatusCode, expectedStatusCode int)
 }
 
 // verifyBodyCompleteMultipartUpload - verify the body returned matches what is expected.
-func verifyBodyCompleteMultipartUpload(resBody io.Reader) error {
+func verifyBodyCompleteMultipartUpload(resBody io.Reader, expectedETag string) error {
 	resCompleteMultipartUploadResult := completeMultipartUploadResult{}
 	if err := xmlDecoder(resBody, &resCompleteMultipartUploadResult); err != nil {
 		return err
 	}
+	if eTag := strings.Trim(resCompleteMultipartUploadResult.ETag, "\""); expectedETag != "" && eTag != expectedETag {
+		err := fmt.Errorf("Unexpected ETag Received: wanted %v, got %v", expectedETag, eTag)
+		return err
+	}
 	return nil
 }
 
+// completedETag - the ETag S3 gives an object completed from the given parts: the MD5 of the
+// concatenated MD5s of the parts followed by "-" and the number of parts.
+func completedETag(complete *completeMultipartUpload) (string, error) {
+	partMD5s := [][]byte{}
+	for _, part := range complete.Parts {
+		partMD5, err := hex.DecodeString(strings.Trim(part.ETag, "\""))
+		if err != nil {
+			return "", err
+		}
+		partMD5s = append(partMD5s, partMD5)
+	}
+	return multipartETag(partMD5s), nil
+}
+
 // verifyHeaderCompleteMultipartUpload - verify the header returned matches what is expected.
 func verifyHeaderCompleteMultipartUpload(header http.Header) error {
 	if err := verifyStandardHeaders(header); err != nil {
@@ -104,6 +125,30 @@ func verifyHeaderCompleteMultipartUpload(header http.Header) error {
 	return nil
 }
 
+// completeMultipart - complete an upload with the given parts, verify the response and return the decoded result.
+func completeMultipart(config ServerConfig, bucketName, objectName, uploadID string, complete *completeMultipartUpload) (completeMultipartUploadResult, error) {
+	result := completeMultipartUploadResult{}
+	req, err := newCompleteMultipartUploadReq(bucketName, objectName, uploadID, complete)
+	if err != nil {
+		return result, err
+	}
+	res, err := config.execRequest("POST", req)
+	if err != nil {
+		return result, err
+	}
+	defer drainAndClose(res)
+	if err := verifyStatusCompleteMultipartUpload(res.StatusCode, http.StatusOK); err != nil {
+		return result, err
+	}
+	if err := verifyHeaderCompleteMultipartUpload(res.Header); err != nil {
+		return result, err
+	}
+	if err := xmlDecoder(res.Body, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
 // mainCompleteMultipartUpload - Complete Multipart Upload API test.
 func mainCompleteMultipartUpload(config ServerConfig, curTest int) bool {
 	message := fmt.Sprintf("[%02d/%d] Multipart (Complete-Upload):", curTest, globalTotalNumTest)
@@ -111,6 +156,11 @@ func mainCompleteMultipartUpload(config ServerConfig, curTest int) bool {
 	scanBar(message)
 	bucketName := s3verifyBuckets[0].Name
 	object := multipartObjects[0]
+	expectedETag, err := completedETag(complMultipartUploads[0])
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
 	// Create a new completeMultipartUpload request.
 	req, err := newCompleteMultipartUploadReq(bucketName, object.Key, object.UploadID, complMultipartUploads[0])
 	if err != nil {
@@ -125,16 +175,32 @@ func mainCompleteMultipartUpload(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(res)
+	defer drainAndClose(res)
 	// Spin scanBar
 	scanBar(message)
 	// Verify the response.
-	if err := completeMultipartUploadVerify(res, http.StatusOK); err != nil {
+	if err := completeMultipartUploadVerify(res, http.StatusOK, expectedETag); err != nil {
+		// Do not leave the parts of a failed upload behind.
+		abortMultipartUpload(config, bucketName, object.Key, object.UploadID)
 		printMessage(message, err)
 		return false
 	}
 	// Spin scanBar
 	scanBar(message)
+	// Record the completed object so it is read by the GetObject tests and removed with the others.
+	header, err := headObject(config, bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	object.ETag = header.Get("ETag")
+	object.Size = int64(len(object.Body))
+	object.LastModified, err = time.Parse(http.TimeFormat, header.Get("Last-Modified"))
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	s3verifyObjects.add(object)
 	printMessage(message, nil)
 	return true
 }
@@ -0,0 +1,166 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// configContentTypes - the Content-Type variants config bodies are sent with, by description.
+// An empty Content-Type leaves the header off.
+var configContentTypes = []struct {
+	description string
+	contentType string
+}{
+	{"missing", ""},
+	{"text/plain", "text/plain"},
+}
+
+// configPut - a PUT of an XML configuration and how to undo it once it was accepted.
+type configPut struct {
+	api     string
+	newReq  func() (Request, error)
+	cleanup func() error
+}
+
+// configContentTypeBehavior - send the request built by newReq with contentType and describe how the
+// server handled it. Accepting the body and rejecting it with a well-formed error are both valid,
+// anything else is an error.
+func configContentTypeBehavior(config ServerConfig, newReq func() (Request, error), contentType string) (string, error) {
+	req, err := newReq()
+	if err != nil {
+		return "", err
+	}
+	if contentType == "" {
+		req.customHeader.Del("Content-Type")
+	} else {
+		req.customHeader.Set("Content-Type", contentType)
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		return "", err
+	}
+	defer drainAndClose(res)
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return "accepted", nil
+	}
+	if res.StatusCode == http.StatusNotImplemented {
+		return "not implemented", nil
+	}
+	if res.StatusCode >= 500 {
+		err := fmt.Errorf("Unexpected Status Received: wanted 2xx or a 4xx error, got %v", res.StatusCode)
+		return "", err
+	}
+	errResponse, err := parseErrorResponse(res.Body)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("rejected with %v %v", res.StatusCode, errResponse.Code), nil
+}
+
+// mainConfigContentType - Test how configuration PUTs with a missing or non-XML Content-Type are
+// handled, and report the behavior of every API so client compatibility can be judged.
+func mainConfigContentType(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Config PUT (Content-Type):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/config-content-type/object",
+		Body: generatedBody(time.Now().UnixNano(), 60),
+	}
+	if _, err := putObject(config, bucketName, object); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	puts := []configPut{
+		{
+			api: "PutBucketCors",
+			newReq: func() (Request, error) {
+				rule := corsRule{
+					AllowedOrigins: []string{corsAllowedOrigin},
+					AllowedMethods: []string{"GET"},
+				}
+				return newPutBucketCorsReq(bucketName, corsConfiguration{CORSRules: []corsRule{rule}})
+			},
+			cleanup: func() error {
+				req, err := newDeleteBucketCorsReq(bucketName)
+				if err != nil {
+					return err
+				}
+				res, err := config.execRequest("DELETE", req)
+				if err != nil {
+					return err
+				}
+				defer drainAndClose(res)
+				return bucketCorsVerify(res, http.StatusNoContent)
+			},
+		},
+		{
+			api: "PutObjectTagging",
+			newReq: func() (Request, error) {
+				return newPutObjectTaggingReq(bucketName, object.Key, map[string]string{"s3verify": "content-type"})
+			},
+			cleanup: func() error { return nil }, // The tags are removed with the object.
+		},
+	}
+	// Enabling versioning again changes nothing on a bucket that already has it enabled.
+	if versioned, err := findBucket(capVersioned); err == nil {
+		puts = append(puts, configPut{
+			api: "PutBucketVersioning",
+			newReq: func() (Request, error) {
+				return newPutBucketVersioningReq(versioned.Name, "Enabled")
+			},
+			cleanup: func() error { return nil },
+		})
+	}
+	details := []string{}
+	for _, put := range puts {
+		accepted := false
+		for _, variant := range configContentTypes {
+			// Spin scanBar
+			scanBar(message)
+			behavior, err := configContentTypeBehavior(config, put.newReq, variant.contentType)
+			if err != nil {
+				err = fmt.Errorf("%v with %v Content-Type: %v", put.api, variant.description, err)
+				printMessage(message, err)
+				return false
+			}
+			details = append(details, fmt.Sprintf("%v with %v Content-Type: %v", put.api, variant.description, behavior))
+			accepted = accepted || behavior == "accepted"
+		}
+		if !accepted {
+			continue
+		}
+		if err := put.cleanup(); err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	for _, detail := range details {
+		printDetail(detail)
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Number of early-returning requests sent by the connection reuse test.
+const connectionReuseRequests = 20
+
+// connectionReuse - counts the connections handed out to traced requests and how many of them were reused.
+type connectionReuse struct {
+	mutex  sync.Mutex
+	total  int
+	reused int
+}
+
+// newConnectionReuse - create a new connectionReuse and the trace that fills it in.
+// The trace must be set on every Request that is to be counted.
+func newConnectionReuse() (*connectionReuse, *httptrace.ClientTrace) {
+	reuse := &connectionReuse{}
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			reuse.mutex.Lock()
+			defer reuse.mutex.Unlock()
+			reuse.total++
+			if info.Reused {
+				reuse.reused++
+			}
+		},
+	}
+	return reuse, trace
+}
+
+// String - a one line summary of the connections used.
+func (c *connectionReuse) String() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return fmt.Sprintf("%d of %d connections reused", c.reused, c.total)
+}
+
+// mainConnectionReuse - Test that responses closed without reading their body still leave the connection to be reused.
+func mainConnectionReuse(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Connection Reuse:", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/connection/reuse",
+		Body: generatedBody(time.Now().UnixNano(), 64*1024),
+	}
+	if _, err := putObject(config, bucketName, object); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	reuse, trace := newConnectionReuse()
+	for i := 0; i < connectionReuseRequests; i++ {
+		// Spin scanBar
+		scanBar(message)
+		req, err := newGetObjectReq(bucketName, object.Key, nil)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		req.trace = trace
+		res, err := config.execRequest("GET", req)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		// Return early as a failed header check would, leaving the body unread.
+		status, closed := res.StatusCode, res.Close
+		drainAndClose(res)
+		if status != http.StatusOK {
+			err := StatusMismatchError{Expected: http.StatusOK, Got: status}
+			printMessage(message, err)
+			return false
+		}
+		if closed {
+			if err := removeObject(config, bucketName, object.Key); err != nil {
+				printMessage(message, err)
+				return false
+			}
+			printMessage(message, nil)
+			printDetail("Skipped: the server closes connections after every response")
+			return true
+		}
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Only the first request may have needed a new connection.
+	if reuse.reused < reuse.total-1 {
+		err := fmt.Errorf("Unexpected Connection Reuse: wanted at least %d of %d connections reused, got %d", reuse.total-1, reuse.total, reuse.reused)
+		printMessage(message, err)
+		return false
+	}
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	printDetail(reuse.String())
+	return true
+}
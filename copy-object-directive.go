@@ -0,0 +1,153 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// copyObjectDirective - copy sourceKey in sourceBucketName to destKey in destBucketName with the given
+// metadata-directive. With REPLACE contentType and metadata are sent for the copy.
+func copyObjectDirective(config ServerConfig, sourceBucketName, sourceKey, destBucketName, destKey, directive, contentType string, metadata map[string]string) error {
+	req, err := newCopyObjectReq(sourceBucketName, sourceKey, destBucketName, destKey)
+	if err != nil {
+		return err
+	}
+	req.customHeader.Set("x-amz-metadata-directive", directive)
+	if directive == "REPLACE" {
+		req.customHeader.Set("Content-Type", contentType)
+		for key, value := range metadata {
+			req.customHeader.Set("x-amz-meta-"+key, value)
+		}
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	return copyObjectVerify(res, http.StatusOK)
+}
+
+// verifyCopiedObject - verify a GET of objectName returns body along with contentType and metadata,
+// and that none of the keys in droppedMetadata are returned.
+func verifyCopiedObject(config ServerConfig, bucketName, objectName string, body []byte, contentType string, metadata map[string]string, droppedMetadata map[string]string) error {
+	req, err := newGetObjectReq(bucketName, objectName, nil)
+	if err != nil {
+		return err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	if err := verifyStatusGetObject(res.StatusCode, http.StatusOK); err != nil {
+		return err
+	}
+	if err := verifyHeaderObjectMetadata(res.Header, contentType, metadata); err != nil {
+		return err
+	}
+	for key := range droppedMetadata {
+		if value := res.Header.Get("x-amz-meta-" + key); value != "" {
+			err := fmt.Errorf("Unexpected Metadata Received: x-amz-meta-%s should have been replaced, got %q", key, value)
+			return err
+		}
+	}
+	received, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	return verifyBodyEqual(body, received)
+}
+
+// mainCopyObjectMetadataDirective - Test CopyObject keeps the source's Content-Type and metadata with
+// metadata-directive COPY and replaces them with the ones sent with REPLACE, copying the bytes either way.
+func mainCopyObjectMetadataDirective(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] CopyObject (Metadata Directive):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	sourceBucketName := s3verifyBuckets[0].Name
+	destBucketName := s3verifyBuckets[1].Name
+	sourceObject := &ObjectInfo{
+		Key:  "s3verify/copy/directive/source",
+		Body: generatedBody(time.Now().UnixNano(), 1024),
+	}
+	sourceContentType := "application/x-s3verify-source"
+	sourceMetadata := map[string]string{"s3verify-origin": "source"}
+	req, err := newPutObjectMetadataReq(sourceBucketName, sourceObject.Key, sourceObject.Body, sourceContentType, sourceMetadata)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if err := putObjectVerify(res, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// With COPY the copy inherits everything from the source.
+	copiedObject := &ObjectInfo{
+		Key:  "s3verify/copy/directive/copied",
+		Body: sourceObject.Body,
+	}
+	if err := copyObjectDirective(config, sourceBucketName, sourceObject.Key, destBucketName, copiedObject.Key, "COPY", "", nil); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	copyObjects.add(copiedObject)
+	if err := verifyCopiedObject(config, destBucketName, copiedObject.Key, sourceObject.Body, sourceContentType, sourceMetadata, nil); err != nil {
+		err = fmt.Errorf("metadata-directive COPY: %v", err)
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// With REPLACE only what is sent with the copy is kept.
+	replacedObject := &ObjectInfo{
+		Key:  "s3verify/copy/directive/replaced",
+		Body: sourceObject.Body,
+	}
+	replacedContentType := "application/x-s3verify-replaced"
+	replacedMetadata := map[string]string{"s3verify-copy": "replaced"}
+	if err := copyObjectDirective(config, sourceBucketName, sourceObject.Key, destBucketName, replacedObject.Key, "REPLACE", replacedContentType, replacedMetadata); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	copyObjects.add(replacedObject)
+	if err := verifyCopiedObject(config, destBucketName, replacedObject.Key, sourceObject.Body, replacedContentType, replacedMetadata, sourceMetadata); err != nil {
+		err = fmt.Errorf("metadata-directive REPLACE: %v", err)
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := removeObject(config, sourceBucketName, sourceObject.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,106 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// mainCopyObjectEncodedSource - Test a PUT object copy request whose source key needs percent-encoding.
+func mainCopyObjectEncodedSource(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] CopyObject (Encoded Source):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	sourceBucketName := s3verifyBuckets[0].Name
+	destBucketName := s3verifyBuckets[1].Name
+	// A source key with spaces and unicode characters.
+	sourceObject := &ObjectInfo{
+		Key:  "s3verify/copy/encoded source ☃ 世界",
+		Body: []byte(randString(60, rand.NewSource(time.Now().UnixNano()), "")),
+	}
+	// A decoy whose key is what a server would find if it decoded '%20' or '+' incorrectly.
+	decoyObject := &ObjectInfo{
+		Key:  "s3verify/copy/encoded+source+☃+世界",
+		Body: []byte(randString(60, rand.NewSource(time.Now().UnixNano()+1), "")),
+	}
+	destObject := &ObjectInfo{
+		Key: "s3verify/copy/encoded-dest",
+	}
+	for _, object := range []*ObjectInfo{sourceObject, decoyObject} {
+		if _, err := putObject(config, sourceBucketName, object); err != nil {
+			printMessage(message, err)
+			return false
+		}
+		// Spin scanBar
+		scanBar(message)
+	}
+	// Create a new copy request.
+	req, err := newCopyObjectReq(sourceBucketName, sourceObject.Key, destBucketName, destObject.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Execute the request.
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	// Verify the response.
+	if err := copyObjectVerify(res, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// The destination must hold the body of the intended source.
+	getReq, err := newGetObjectReq(destBucketName, destObject.Key, nil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	getRes, err := config.execRequest("GET", getReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(getRes)
+	if err := getObjectVerify(getRes, sourceObject.Body, http.StatusOK, nil); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Remove the objects created by this test.
+	if err := removeObject(config, destBucketName, destObject.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	for _, object := range []*ObjectInfo{sourceObject, decoyObject} {
+		if err := removeObject(config, sourceBucketName, object.Key); err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
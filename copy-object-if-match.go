@@ -23,7 +23,6 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
-	"net/url"
 )
 
 // newCopyObjectIfMatchReq - Create a new HTTP request for a PUT copy object.
@@ -45,7 +44,7 @@ func newCopyObjectIfMatchReq(sourceBucketName, sourceObjectName, destBucketName,
 	copyObjectIfMatchReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
 	// Content-MD5 should not be set for CopyObject request.
 	// Content-Length should not be set for CopyObject request.
-	copyObjectIfMatchReq.customHeader.Set("x-amz-copy-source", url.QueryEscape(sourceBucketName+"/"+sourceObjectName))
+	copyObjectIfMatchReq.customHeader.Set("x-amz-copy-source", encodeCopySource(sourceBucketName, sourceObjectName))
 	copyObjectIfMatchReq.customHeader.Set("x-amz-copy-source-if-match", ETag)
 	copyObjectIfMatchReq.customHeader.Set("User-Agent", appUserAgent)
 
@@ -123,7 +122,7 @@ func mainCopyObjectIfMatch(config ServerConfig, curTest int) bool {
 	// s3verify created buckets on s3verify created objects.
 	sourceBucketName := s3verifyBuckets[0].Name
 	destBucketName := s3verifyBuckets[1].Name
-	sourceObject := s3verifyObjects[0]
+	sourceObject := s3verifyObjects.snapshot()[0]
 
 	// Create bad ETag.
 	badETag := "1234567890"
@@ -149,7 +148,7 @@ func mainCopyObjectIfMatch(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(res)
+	defer drainAndClose(res)
 	// Verify the response.
 	if err := copyObjectIfMatchVerify(res, http.StatusOK, ErrorResponse{}); err != nil {
 		printMessage(message, err)
@@ -168,14 +167,14 @@ func mainCopyObjectIfMatch(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(badRes)
+	defer drainAndClose(badRes)
 	// Verify the request failed as expected.
 	if err := copyObjectIfMatchVerify(badRes, http.StatusPreconditionFailed, expectedError); err != nil {
 		printMessage(message, err)
 		return false
 	}
 	// Save the copied object.
-	copyObjects = append(copyObjects, destObject)
+	copyObjects.add(destObject)
 	// Test passed.
 	printMessage(message, nil)
 	return true
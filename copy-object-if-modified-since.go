@@ -23,7 +23,6 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
-	"net/url"
 	"time"
 )
 
@@ -46,7 +45,7 @@ func newCopyObjectIfModifiedSinceReq(sourceBucketName, sourceObjectName, destBuc
 	}
 	// Set the header.
 	copyObjectIfModifiedSinceReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
-	copyObjectIfModifiedSinceReq.customHeader.Set("x-amz-copy-source", url.QueryEscape(sourceBucketName+"/"+sourceObjectName))
+	copyObjectIfModifiedSinceReq.customHeader.Set("x-amz-copy-source", encodeCopySource(sourceBucketName, sourceObjectName))
 	copyObjectIfModifiedSinceReq.customHeader.Set("x-amz-copy-source-if-modified-since", lastModified.Format(http.TimeFormat))
 	copyObjectIfModifiedSinceReq.customHeader.Set("User-Agent", appUserAgent)
 
@@ -121,7 +120,7 @@ func mainCopyObjectIfModifiedSince(config ServerConfig, curTest int) bool {
 	// on s3verify created objects.
 	sourceBucketName := s3verifyBuckets[0].Name
 	destBucketName := s3verifyBuckets[1].Name
-	sourceObject := s3verifyObjects[0]
+	sourceObject := s3verifyObjects.snapshot()[0]
 
 	// Set a date in the past.
 	pastDate, err := time.Parse(http.TimeFormat, "Thu, 01 Jan 1970 00:00:00 GMT")
@@ -132,7 +131,7 @@ func mainCopyObjectIfModifiedSince(config ServerConfig, curTest int) bool {
 	destObject := &ObjectInfo{
 		Key: sourceObject.Key + "if-modified-since",
 	}
-	copyObjects = append(copyObjects, destObject)
+	copyObjects.add(destObject)
 	expectedError := ErrorResponse{
 		Code:    "PreconditionFailed",
 		Message: "At least one of the pre-conditions you specified did not hold",
@@ -153,7 +152,7 @@ func mainCopyObjectIfModifiedSince(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(res)
+	defer drainAndClose(res)
 	// Spin scanBar
 	scanBar(message)
 	// Verify the response is valid.
@@ -177,7 +176,7 @@ func mainCopyObjectIfModifiedSince(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(badRes)
+	defer drainAndClose(badRes)
 	// Spin scanBar
 	scanBar(message)
 	// Verify the bad request fails the right way.
@@ -22,7 +22,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"net/url"
 )
 
 // newPutObjectCopyIfNoneMatchReq - Create a new HTTP request for a CopyObject with the if-none-match header set.
@@ -43,7 +42,7 @@ func newCopyObjectIfNoneMatchReq(sourceBucketName, sourceObjectName, destBucketN
 	}
 	// Fill in the request header.
 	copyObjectIfNoneMatchReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
-	copyObjectIfNoneMatchReq.customHeader.Set("x-amz-copy-source", url.QueryEscape(sourceBucketName+"/"+sourceObjectName))
+	copyObjectIfNoneMatchReq.customHeader.Set("x-amz-copy-source", encodeCopySource(sourceBucketName, sourceObjectName))
 	copyObjectIfNoneMatchReq.customHeader.Set("x-amz-copy-source-if-none-match", ETag)
 	copyObjectIfNoneMatchReq.customHeader.Set("User-Agent", appUserAgent)
 
@@ -112,7 +111,7 @@ func mainCopyObjectIfNoneMatch(config ServerConfig, curTest int) bool {
 	// on s3verify created objects.
 	sourceBucketName := s3verifyBuckets[0].Name
 	destBucketName := s3verifyBuckets[1].Name
-	sourceObject := s3verifyObjects[0]
+	sourceObject := s3verifyObjects.snapshot()[0]
 
 	// Create unmatchable ETag.
 	goodETag := "1234567890"
@@ -120,7 +119,7 @@ func mainCopyObjectIfNoneMatch(config ServerConfig, curTest int) bool {
 	destObject := &ObjectInfo{
 		Key: sourceObject.Key + "if-none-match",
 	}
-	copyObjects = append(copyObjects, destObject)
+	copyObjects.add(destObject)
 	// Create an error for the case that is expected to fail.
 	expectedError := ErrorResponse{
 		Code:    "PreconditionFailed",
@@ -138,7 +137,7 @@ func mainCopyObjectIfNoneMatch(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(res)
+	defer drainAndClose(res)
 	// Verify the response.
 	if err = copyObjectIfNoneMatchVerify(res, http.StatusOK, ErrorResponse{}); err != nil {
 		printMessage(message, err)
@@ -156,7 +155,7 @@ func mainCopyObjectIfNoneMatch(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(badRes)
+	defer drainAndClose(badRes)
 	// Verify the response errors out as it should.
 	if err = copyObjectIfNoneMatchVerify(badRes, http.StatusPreconditionFailed, expectedError); err != nil {
 		printMessage(message, err)
@@ -23,7 +23,6 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
-	"net/url"
 	"time"
 )
 
@@ -45,7 +44,7 @@ func newCopyObjectIfUnModifiedSinceReq(sourceBucketName, sourceObjectName, destB
 		return Request{}, err
 	}
 	copyObjectIfUnModifiedSinceReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
-	copyObjectIfUnModifiedSinceReq.customHeader.Set("x-amz-copy-source", url.QueryEscape(sourceBucketName+"/"+sourceObjectName))
+	copyObjectIfUnModifiedSinceReq.customHeader.Set("x-amz-copy-source", encodeCopySource(sourceBucketName, sourceObjectName))
 	copyObjectIfUnModifiedSinceReq.customHeader.Set("x-amz-copy-if-unmodified-since", lastModified.Format(http.TimeFormat))
 	copyObjectIfUnModifiedSinceReq.customHeader.Set("User-Agent", appUserAgent)
 
@@ -121,7 +120,7 @@ func mainCopyObjectIfUnModifiedSince(config ServerConfig, curTest int) bool {
 	// on s3verify created objects.
 	sourceBucketName := s3verifyBuckets[0].Name
 	destBucketName := s3verifyBuckets[1].Name
-	sourceObject := s3verifyObjects[0]
+	sourceObject := s3verifyObjects.snapshot()[0]
 
 	// Set a date in the past.
 	pastDate, err := time.Parse(http.TimeFormat, "Thu, 01 Jan 1970 00:00:00 GMT")
@@ -151,7 +150,7 @@ func mainCopyObjectIfUnModifiedSince(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(res)
+	defer drainAndClose(res)
 	// Spin scanBar
 	scanBar(message)
 	// Verify the response.
@@ -159,8 +158,8 @@ func mainCopyObjectIfUnModifiedSince(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	// Add the copied object to the copyObjects slice.
-	copyObjects = append(copyObjects, destObject)
+	// Add the copied object to the copyObjects store.
+	copyObjects.add(destObject)
 	// Spin scanBar
 	scanBar(message)
 	// Create a new invalid request.
@@ -177,7 +176,7 @@ func mainCopyObjectIfUnModifiedSince(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(badRes)
+	defer drainAndClose(badRes)
 	// Spin scanBar
 	scanBar(message)
 	// Verify the bad request fails with the proper error.
@@ -0,0 +1,126 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// newCopyObjectSelfReq - Create a request copying objectName onto itself with the given metadata-directive.
+func newCopyObjectSelfReq(bucketName, objectName, directive string) (Request, error) {
+	req, err := newCopyObjectReq(bucketName, objectName, bucketName, objectName)
+	if err != nil {
+		return Request{}, err
+	}
+	req.customHeader.Set("x-amz-metadata-directive", directive)
+	return req, nil
+}
+
+// copyObjectSelfRejectedVerify - verify a copy onto itself that changes nothing was rejected with InvalidRequest.
+func copyObjectSelfRejectedVerify(res *http.Response) error {
+	if err := verifyStatusCopyObject(res.StatusCode, http.StatusBadRequest); err != nil {
+		return err
+	}
+	if err := verifyStandardHeaders(res.Header); err != nil {
+		return err
+	}
+	errResponse := ErrorResponse{}
+	if err := xmlDecoder(res.Body, &errResponse); err != nil {
+		return err
+	}
+	if errResponse.Code != "InvalidRequest" {
+		err := fmt.Errorf("Unexpected Error Response: wanted InvalidRequest, got %v", errResponse.Code)
+		return err
+	}
+	return nil
+}
+
+// mainCopyObjectSelf - Test that copying an object onto itself is rejected when nothing changes
+// (metadata-directive COPY) and succeeds when it replaces the metadata (metadata-directive REPLACE).
+func mainCopyObjectSelf(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] CopyObject (Onto Itself):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/copy/self",
+		Body: []byte(randString(60, rand.NewSource(time.Now().UnixNano()), "")),
+	}
+	if _, err := putObject(config, bucketName, object); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// A copy onto itself that keeps the metadata would change nothing and must be rejected.
+	copyReq, err := newCopyObjectSelfReq(bucketName, object.Key, "COPY")
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	copyRes, err := config.execRequest("PUT", copyReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(copyRes)
+	if err := copyObjectSelfRejectedVerify(copyRes); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Replacing the metadata is the legal way to change an object in place.
+	replaceReq, err := newCopyObjectSelfReq(bucketName, object.Key, "REPLACE")
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	replaceReq.customHeader.Set("x-amz-meta-s3verify-copy", "replaced")
+	replaceRes, err := config.execRequest("PUT", replaceReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(replaceRes)
+	if err := copyObjectVerify(replaceRes, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	header, err := headObject(config, bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if value := header.Get("x-amz-meta-s3verify-copy"); value != "replaced" {
+		err := fmt.Errorf("Unexpected x-amz-meta-s3verify-copy Received: wanted replaced, got %q", value)
+		printMessage(message, err)
+		return false
+	}
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,161 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// copyStorageClass - the non-STANDARD storage class used for the copy source.
+const copyStorageClass = "STANDARD_IA"
+
+// putObjectStorageClass - upload object in the given storage class.
+// Returns false without an error if the server does not support the storage class.
+func putObjectStorageClass(config ServerConfig, bucketName string, object *ObjectInfo, storageClass string) (bool, error) {
+	req, err := newPutObjectStorageClassReq(bucketName, object.Key, object.Body, storageClass)
+	if err != nil {
+		return false, err
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		return false, err
+	}
+	defer drainAndClose(res)
+	if res.StatusCode == http.StatusNotImplemented {
+		return false, nil
+	}
+	if res.StatusCode != http.StatusOK {
+		errResponse := ErrorResponse{}
+		if err := xmlDecoder(res.Body, &errResponse); err != nil {
+			return false, err
+		}
+		if errResponse.Code == "InvalidStorageClass" || errResponse.Code == "NotImplemented" {
+			return false, nil
+		}
+		err := fmt.Errorf("Unexpected Response Status Code: wanted %v, got %v (%v)", http.StatusOK, res.StatusCode, errResponse.Code)
+		return false, err
+	}
+	return true, nil
+}
+
+// copyObjectStorageClass - copy sourceKey to destKey with metadata-directive COPY.
+// An empty storageClass leaves the x-amz-storage-class header off the copy.
+func copyObjectStorageClass(config ServerConfig, bucketName, sourceKey, destKey, storageClass string) error {
+	req, err := newCopyObjectReq(bucketName, sourceKey, bucketName, destKey)
+	if err != nil {
+		return err
+	}
+	req.customHeader.Set("x-amz-metadata-directive", "COPY")
+	if storageClass != "" {
+		req.customHeader.Set("x-amz-storage-class", storageClass)
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	return copyObjectVerify(res, http.StatusOK)
+}
+
+// verifyStorageClass - verify that HEAD reports the expected storage class for objectName.
+// STANDARD objects may omit the x-amz-storage-class header entirely.
+func verifyStorageClass(config ServerConfig, bucketName, objectName, expectedStorageClass string) error {
+	header, err := headObject(config, bucketName, objectName)
+	if err != nil {
+		return err
+	}
+	storageClass := header.Get("x-amz-storage-class")
+	if storageClass == "" {
+		storageClass = "STANDARD"
+	}
+	if storageClass != expectedStorageClass {
+		err := fmt.Errorf("Unexpected Storage Class for %v Received: wanted %v, got %v", objectName, expectedStorageClass, storageClass)
+		return err
+	}
+	return nil
+}
+
+// mainCopyObjectStorageClass - Test that a copy keeps the source's storage class unless the request overrides it.
+func mainCopyObjectStorageClass(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] CopyObject (Storage Class):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	sourceObject := &ObjectInfo{
+		Key:  "s3verify/copy/storage-class/source",
+		Body: []byte(randString(60, rand.NewSource(time.Now().UnixNano()), "")),
+	}
+	inheritedKey := "s3verify/copy/storage-class/inherited"
+	overriddenKey := "s3verify/copy/storage-class/overridden"
+	supported, err := putObjectStorageClass(config, bucketName, sourceObject, copyStorageClass)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if !supported {
+		printMessage(message, nil)
+		printDetail("Skipped: server does not support the " + copyStorageClass + " storage class")
+		return true
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Servers that accept the header but store everything as STANDARD have nothing to preserve.
+	if err := verifyStorageClass(config, bucketName, sourceObject.Key, copyStorageClass); err != nil {
+		if err := removeObject(config, bucketName, sourceObject.Key); err != nil {
+			printMessage(message, err)
+			return false
+		}
+		printMessage(message, nil)
+		printDetail("Skipped: server does not report the " + copyStorageClass + " storage class on HEAD")
+		return true
+	}
+	// A copy without a storage-class header must inherit the source's class.
+	if err := copyObjectStorageClass(config, bucketName, sourceObject.Key, inheritedKey, ""); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyStorageClass(config, bucketName, inheritedKey, copyStorageClass); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// An explicit storage-class header on the copy must override the source's class.
+	if err := copyObjectStorageClass(config, bucketName, sourceObject.Key, overriddenKey, "STANDARD"); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyStorageClass(config, bucketName, overriddenKey, "STANDARD"); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Remove the objects created by this test.
+	for _, objectName := range []string{overriddenKey, inheritedKey, sourceObject.Key} {
+		if err := removeObject(config, bucketName, objectName); err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,172 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// verifyBodyCopyObjectError - verify the body returned is the expected error.
+func verifyBodyCopyObjectError(resBody io.Reader, expectedError ErrorResponse) error {
+	errResponse := ErrorResponse{}
+	if err := xmlDecoder(resBody, &errResponse); err != nil {
+		return err
+	}
+	if errResponse.Code != expectedError.Code {
+		err := fmt.Errorf("Unexpected Error Response: wanted %v, got %v", expectedError.Code, errResponse.Code)
+		return err
+	}
+	return nil
+}
+
+// copyObjectErrorVerify - verify that a copy request failed as expected.
+func copyObjectErrorVerify(res *http.Response, expectedStatusCode int, expectedError ErrorResponse) error {
+	if err := verifyStatusCopyObject(res.StatusCode, expectedStatusCode); err != nil {
+		return err
+	}
+	if err := verifyHeaderCopyObject(res.Header); err != nil {
+		return err
+	}
+	if err := verifyBodyCopyObjectError(res.Body, expectedError); err != nil {
+		return err
+	}
+	return nil
+}
+
+// putVersionedObject - upload object to a versioned bucket and record the version created.
+func putVersionedObject(config ServerConfig, bucketName string, object *ObjectInfo) error {
+	header, err := putObject(config, bucketName, object)
+	if err != nil {
+		return err
+	}
+	object.VersionID = header.Get("x-amz-version-id")
+	if object.VersionID == "" {
+		err := fmt.Errorf("Missing Header: x-amz-version-id was not returned for an object in a versioned bucket")
+		return err
+	}
+	return nil
+}
+
+// copyVersionBucket - the versioned bucket used by mainCopyObjectVersion.
+func copyVersionBucket() string {
+	return "s3verify-" + globalSuffix + "-copy-versioned"
+}
+
+// setupCopyObjectVersion - version aware copies need their own versioned bucket.
+func setupCopyObjectVersion(config ServerConfig) error {
+	if err := putBucket(config, copyVersionBucket()); err != nil {
+		return err
+	}
+	return putBucketVersioning(config, copyVersionBucket(), "Enabled")
+}
+
+// teardownCopyObjectVersion - remove every version and the versioned bucket.
+func teardownCopyObjectVersion(config ServerConfig) error {
+	return removeBucketWithContents(config, copyVersionBucket())
+}
+
+// mainCopyObjectVersion - Test a PUT object copy request from a specific source version.
+func mainCopyObjectVersion(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] CopyObject (Source VersionId):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	// The versioned bucket is created by setupCopyObjectVersion.
+	bucketName := copyVersionBucket()
+	// Upload two versions of the same object.
+	oldVersion := &ObjectInfo{
+		Key:  "s3verify/copy/versioned",
+		Body: []byte(randString(60, rand.NewSource(time.Now().UnixNano()), "")),
+	}
+	newVersion := &ObjectInfo{
+		Key:  oldVersion.Key,
+		Body: []byte(randString(60, rand.NewSource(time.Now().UnixNano()+1), "")),
+	}
+	for _, version := range []*ObjectInfo{oldVersion, newVersion} {
+		if err := putVersionedObject(config, bucketName, version); err != nil {
+			printMessage(message, err)
+			return false
+		}
+		// Spin scanBar
+		scanBar(message)
+	}
+	// Copy the historical version to a new key.
+	destObject := &ObjectInfo{
+		Key: "s3verify/copy/versioned-dest",
+	}
+	req, err := newCopyObjectVersionReq(bucketName, oldVersion.Key, oldVersion.VersionID, bucketName, destObject.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if err := copyObjectVerify(res, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	destObject.VersionID = res.Header.Get("x-amz-version-id")
+	// Spin scanBar
+	scanBar(message)
+	// The destination must hold the historical body, not the latest.
+	getReq, err := newGetObjectVersionReq(bucketName, destObject.Key, destObject.VersionID)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	getRes, err := config.execRequest("GET", getReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(getRes)
+	if err := getObjectVerify(getRes, oldVersion.Body, http.StatusOK, nil); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Copying from a version that does not exist must fail.
+	badReq, err := newCopyObjectVersionReq(bucketName, oldVersion.Key, "s3verify-"+randString(30, rand.NewSource(time.Now().UnixNano()), ""), bucketName, destObject.Key+"-bad")
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	badRes, err := config.execRequest("PUT", badReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(badRes)
+	expectedError := ErrorResponse{
+		Code: "NoSuchVersion",
+	}
+	if err := copyObjectErrorVerify(badRes, http.StatusNotFound, expectedError); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
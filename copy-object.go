@@ -23,11 +23,17 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"net/url"
+	"time"
 )
 
 // newCopyObjectReq - Create a new HTTP request for PUT object with copy-
 func newCopyObjectReq(sourceBucketName, sourceObjectName, destBucketName, destObjectName string) (Request, error) {
+	return newCopyObjectVersionReq(sourceBucketName, sourceObjectName, "", destBucketName, destObjectName)
+}
+
+// newCopyObjectVersionReq - Create a new HTTP request for PUT object with copy-
+// from a specific version of the source object. An empty sourceVersionID copies the latest version.
+func newCopyObjectVersionReq(sourceBucketName, sourceObjectName, sourceVersionID, destBucketName, destObjectName string) (Request, error) {
 	var copyObjectReq = Request{
 		customHeader: http.Header{},
 	}
@@ -45,7 +51,12 @@ func newCopyObjectReq(sourceBucketName, sourceObjectName, destBucketName, destOb
 	// Fill request headers.
 	// Content-MD5 should never be set for CopyObject API.
 	copyObjectReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
-	copyObjectReq.customHeader.Set("x-amz-copy-source", url.QueryEscape(sourceBucketName+"/"+sourceObjectName))
+	copySource := encodeCopySource(sourceBucketName, sourceObjectName)
+	if sourceVersionID != "" {
+		// The versionId is part of the copy source and must not be escaped with it.
+		copySource += "?versionId=" + sourceVersionID
+	}
+	copyObjectReq.customHeader.Set("x-amz-copy-source", copySource)
 	copyObjectReq.customHeader.Set("User-Agent", appUserAgent)
 
 	return copyObjectReq, nil
@@ -73,7 +84,8 @@ func verifyHeaderCopyObject(header http.Header) error {
 	return nil
 }
 
-// verifyBodycopyObject - verify that the body returned is a valid CopyObject Result.
+// verifyBodycopyObject - verify that the body returned is a valid CopyObject Result
+// holding the new object's ETag and LastModified.
 func verifyBodyCopyObject(resBody io.Reader) error {
 	copyObjRes := copyObjectResult{}
 	decoder := xml.NewDecoder(resBody)
@@ -81,6 +93,14 @@ func verifyBodyCopyObject(resBody io.Reader) error {
 	if err != nil {
 		return err
 	}
+	if copyObjRes.ETag == "" {
+		err := fmt.Errorf("Missing ETag: the CopyObjectResult did not hold an ETag")
+		return err
+	}
+	if _, err := time.Parse(time.RFC3339, copyObjRes.LastModified); err != nil {
+		err = fmt.Errorf("Invalid LastModified Received: wanted an ISO 8601 time, got %q", copyObjRes.LastModified)
+		return err
+	}
 	return nil
 }
 
@@ -102,13 +122,13 @@ func mainCopyObject(config ServerConfig, curTest int) bool {
 	// on s3verify created objects.
 	sourceBucketName := s3verifyBuckets[0].Name
 	destBucketName := s3verifyBuckets[1].Name
-	sourceObject := s3verifyObjects[0]
+	sourceObject := s3verifyObjects.snapshot()[0]
 
 	// TODO: create tests designed to fail.
 	destObject := &ObjectInfo{
 		Key: sourceObject.Key,
 	}
-	copyObjects = append(copyObjects, destObject)
+	copyObjects.add(destObject)
 	// Spin scanBar
 	scanBar(message)
 	// Create a new request.
@@ -125,7 +145,7 @@ func mainCopyObject(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(res)
+	defer drainAndClose(res)
 	// Spin scanBar
 	scanBar(message)
 	// Verify the response.
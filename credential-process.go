@@ -0,0 +1,214 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// credentialExpiryWindow - how long before they expire credentials from a credential process are
+// refreshed, so a request signed with them does not reach the server after they expired.
+const credentialExpiryWindow = 5 * time.Second
+
+// processCredentials - the JSON a credential process prints, in the format the AWS CLI uses.
+type processCredentials struct {
+	Version         int
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string
+	SessionToken    string
+	Expiration      *time.Time // Credentials without an Expiration never expire.
+}
+
+// credentialProcess - runs an external command for the credentials to sign requests with and
+// caches them until they are about to expire.
+type credentialProcess struct {
+	command string
+	mutex   *sync.Mutex
+	creds   *processCredentials
+	runs    int // Number of times the command was run.
+}
+
+// newCredentialProcess - returns a credentialProcess running command, nil if command is empty.
+func newCredentialProcess(command string) *credentialProcess {
+	if command == "" {
+		return nil
+	}
+	return &credentialProcess{
+		command: command,
+		mutex:   &sync.Mutex{},
+	}
+}
+
+// expired - report whether the cached credentials must be refreshed.
+func (p *credentialProcess) expired() bool {
+	if p.creds == nil {
+		return true
+	}
+	if p.creds.Expiration == nil {
+		return false
+	}
+	return time.Now().Add(credentialExpiryWindow).After(*p.creds.Expiration)
+}
+
+// get - returns the cached credentials, running the command first if there are none or they are
+// about to expire.
+func (p *credentialProcess) get() (processCredentials, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.expired() {
+		creds, err := p.run()
+		if err != nil {
+			return processCredentials{}, err
+		}
+		p.creds = &creds
+	}
+	return *p.creds, nil
+}
+
+// run - runs the command through the shell and parses the credentials it prints.
+func (p *credentialProcess) run() (processCredentials, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", p.command)
+	} else {
+		cmd = exec.Command("sh", "-c", p.command)
+	}
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	output, err := cmd.Output()
+	p.runs++
+	if err != nil {
+		err = fmt.Errorf("Credential process %q failed: %v %s", p.command, err, bytes.TrimSpace(stderr.Bytes()))
+		return processCredentials{}, err
+	}
+	creds := processCredentials{}
+	if err := json.Unmarshal(output, &creds); err != nil {
+		err = fmt.Errorf("Credential process %q printed invalid JSON: %v", p.command, err)
+		return processCredentials{}, err
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		err := fmt.Errorf("Credential process %q printed no AccessKeyId or SecretAccessKey", p.command)
+		return processCredentials{}, err
+	}
+	return creds, nil
+}
+
+// credentials - the access key, secret key and session token to sign the next request with.
+func (c ServerConfig) credentials() (access, secret, token string, err error) {
+	if c.CredentialProcess == nil {
+		return c.Access, c.Secret, "", nil
+	}
+	creds, err := c.CredentialProcess.get()
+	if err != nil {
+		return "", "", "", err
+	}
+	return creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken, nil
+}
+
+// writeCredentialStub - writes a shell script printing access and secret as credentials that
+// expire after firstExpiry the first time it is run, and an hour later on every further run.
+func writeCredentialStub(dir, access, secret string, firstExpiry time.Duration) (string, error) {
+	script := fmt.Sprintf(`#!/bin/sh
+marker="%s"
+if [ -f "$marker" ]; then
+	expiration="%s"
+else
+	: > "$marker"
+	expiration="%s"
+fi
+printf '{"Version":1,"AccessKeyId":"%%s","SecretAccessKey":"%%s","Expiration":"%%s"}' '%s' '%s' "$expiration"
+`, filepath.Join(dir, "ran"),
+		time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		time.Now().Add(firstExpiry).UTC().Format(time.RFC3339),
+		access, secret)
+	path := filepath.Join(dir, "credential-process.sh")
+	if err := ioutil.WriteFile(path, []byte(script), 0700); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// mainCredentialProcess - Verify credentials from a credential process are used to sign requests
+// and refreshed once they are about to expire, using a stub process printing the configured keys.
+func mainCredentialProcess(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Credential Process:", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	if config.CredentialProcess != nil || config.Access == "" || config.Secret == "" || runtime.GOOS == "windows" {
+		// The stub needs static keys to print and a POSIX shell to run.
+		printMessage(message, nil)
+		printDetail("Skipped: needs --access, --secret and a POSIX shell")
+		return true
+	}
+	dir, err := ioutil.TempDir("", "s3verify-credential-process")
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer os.RemoveAll(dir)
+	// Expire just outside the expiry window, so the credentials are used once and then refreshed.
+	firstExpiry := credentialExpiryWindow + 2*time.Second
+	stub, err := writeCredentialStub(dir, config.Access, config.Secret, firstExpiry)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	process := newCredentialProcess(stub)
+	config.CredentialProcess = process
+	config.Access, config.Secret = "", ""
+	bucketName := s3verifyBuckets[0].Name
+	for i := 0; i < 2; i++ {
+		if i == 1 {
+			// Wait until the first credentials are within the expiry window.
+			time.Sleep(firstExpiry - credentialExpiryWindow + time.Second)
+		}
+		req, err := newHeadBucketReq(bucketName)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		res, err := config.execRequest("HEAD", req)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		err = headBucketVerify(res, http.StatusOK)
+		drainAndClose(res)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		if process.runs != i+1 {
+			err := fmt.Errorf("Unexpected Credential Process Runs: wanted %v, got %v", i+1, process.runs)
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
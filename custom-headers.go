@@ -0,0 +1,190 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// extraHeaders - headers given on the command line to inject into every request.
+// Signed headers are added before the request is signed so they appear in its
+// SignedHeaders, unsigned headers are added afterwards and so are never signed.
+type extraHeaders struct {
+	signed   http.Header
+	unsigned http.Header
+}
+
+// newExtraHeaders - parse the "Name: Value" strings given with --signed-header and --header.
+func newExtraHeaders(signed, unsigned []string) (*extraHeaders, error) {
+	headers := &extraHeaders{
+		signed:   http.Header{},
+		unsigned: http.Header{},
+	}
+	if err := parseCustomHeaders(signed, headers.signed); err != nil {
+		return nil, err
+	}
+	if err := parseCustomHeaders(unsigned, headers.unsigned); err != nil {
+		return nil, err
+	}
+	for name := range headers.signed {
+		if _, ok := headers.unsigned[name]; ok {
+			err := fmt.Errorf("Invalid Header: %s is given both signed and unsigned", name)
+			return nil, err
+		}
+	}
+	return headers, nil
+}
+
+// parseCustomHeaders - add each "Name: Value" string to header.
+func parseCustomHeaders(values []string, header http.Header) error {
+	for _, value := range values {
+		parts := strings.SplitN(value, ":", 2)
+		name := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || name == "" || strings.ContainsAny(name, " \t") {
+			err := fmt.Errorf("Invalid Header: %q is not of the form \"Name: Value\"", value)
+			return err
+		}
+		header.Add(name, strings.TrimSpace(parts[1]))
+	}
+	return nil
+}
+
+// isEmpty - whether no headers were given at all.
+func (h *extraHeaders) isEmpty() bool {
+	return h == nil || (len(h.signed) == 0 && len(h.unsigned) == 0)
+}
+
+// setSigned - add the signed headers to a request that is about to be signed.
+// Headers a test sets itself take precedence so tests keep control of what they check.
+func (h *extraHeaders) setSigned(req *http.Request) {
+	if h == nil {
+		return
+	}
+	setMissingHeaders(req.Header, h.signed)
+}
+
+// setUnsigned - add the unsigned headers to a request that has already been signed.
+func (h *extraHeaders) setUnsigned(req *http.Request) {
+	if h == nil {
+		return
+	}
+	setMissingHeaders(req.Header, h.unsigned)
+}
+
+// setMissingHeaders - copy the headers in from into to, skipping any already set.
+func setMissingHeaders(to, from http.Header) {
+	for name, values := range from {
+		if _, ok := to[name]; ok {
+			continue
+		}
+		to[name] = values
+	}
+}
+
+// signedHeaderNames - the SignedHeaders listed in the Authorization header of a signed request.
+func signedHeaderNames(req *http.Request) []string {
+	const field = "SignedHeaders="
+	auth := req.Header.Get("Authorization")
+	start := strings.Index(auth, field)
+	if start < 0 {
+		return nil
+	}
+	signed := auth[start+len(field):]
+	if end := strings.Index(signed, ","); end >= 0 {
+		signed = signed[:end]
+	}
+	return strings.Split(signed, ";")
+}
+
+// verifyCustomHeaders - verify every custom header was set on req, and if checkSigned is set
+// that it was signed only if it was asked to be.
+func verifyCustomHeaders(req *http.Request, headers *extraHeaders, checkSigned bool) error {
+	signedNames := map[string]bool{}
+	for _, name := range signedHeaderNames(req) {
+		signedNames[name] = true
+	}
+	for _, set := range []struct {
+		header http.Header
+		signed bool
+	}{
+		{headers.signed, true},
+		{headers.unsigned, false},
+	} {
+		for name, values := range set.header {
+			if got := req.Header[name]; strings.Join(got, ",") != strings.Join(values, ",") {
+				err := fmt.Errorf("Unexpected Header Sent: wanted %s: %v, got %v", name, values, got)
+				return err
+			}
+			if !checkSigned {
+				continue
+			}
+			if signed := signedNames[strings.ToLower(name)]; signed != set.signed {
+				err := fmt.Errorf("Unexpected SignedHeaders Sent: wanted %s signed %v, got signed %v", name, set.signed, signed)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mainCustomHeaders - verify the headers given on the command line are sent and signed as asked,
+// and that the server accepts a request carrying them.
+func mainCustomHeaders(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Custom Headers:", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	if globalCustomHeaders.isEmpty() {
+		printMessage(message, nil)
+		printDetail("Skipped: use --header or --signed-header to add headers to every request")
+		return true
+	}
+	// HEAD the first s3verify bucket, a request with no headers of its own to collide with.
+	bucketName := s3verifyBuckets[0].Name
+	req, err := newHeadBucketReq(bucketName)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Inspect the request exactly as it would be sent.
+	httpReq, err := config.newRequest("HEAD", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Signature Version 2 has no SignedHeaders, it always signs the x-amz- headers.
+	if err := verifyCustomHeaders(httpReq, globalCustomHeaders, !config.isSignatureV2()); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	res, err := config.execRequest("HEAD", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if err := headBucketVerify(res, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
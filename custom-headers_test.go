@@ -0,0 +1,154 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Test parsing the headers given with --signed-header and --header.
+func TestNewExtraHeaders(t *testing.T) {
+	testCases := []struct {
+		signed   []string
+		unsigned []string
+		expected *extraHeaders
+		err      string // The exact error expected, empty if the headers are valid.
+	}{
+		{
+			[]string{"X-Gateway-Tenant: s3verify"},
+			[]string{"X-Trace:  abc:def ", "X-Trace: ghi"},
+			&extraHeaders{
+				signed:   http.Header{"X-Gateway-Tenant": {"s3verify"}},
+				unsigned: http.Header{"X-Trace": {"abc:def", "ghi"}},
+			},
+			"",
+		},
+		{nil, nil, &extraHeaders{signed: http.Header{}, unsigned: http.Header{}}, ""},
+		// A missing colon.
+		{nil, []string{"X-Trace abc"}, nil, `Invalid Header: "X-Trace abc" is not of the form "Name: Value"`},
+		// A name containing a space.
+		{[]string{"X Trace: abc"}, nil, nil, `Invalid Header: "X Trace: abc" is not of the form "Name: Value"`},
+		// No name at all.
+		{[]string{": abc"}, nil, nil, `Invalid Header: ": abc" is not of the form "Name: Value"`},
+		// The same header, in different cases, given both signed and unsigned.
+		{[]string{"X-Trace: abc"}, []string{"x-trace: def"}, nil, "Invalid Header: X-Trace is given both signed and unsigned"},
+	}
+	for i, testCase := range testCases {
+		headers, err := newExtraHeaders(testCase.signed, testCase.unsigned)
+		if testCase.err != "" {
+			if err == nil || err.Error() != testCase.err {
+				t.Errorf("Test %d: Expected error %q, got %v", i+1, testCase.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: Expected no error, got %q", i+1, err)
+			continue
+		}
+		for _, set := range []struct {
+			name     string
+			got      http.Header
+			expected http.Header
+		}{
+			{"signed", headers.signed, testCase.expected.signed},
+			{"unsigned", headers.unsigned, testCase.expected.unsigned},
+		} {
+			if len(set.got) != len(set.expected) {
+				t.Errorf("Test %d: Expected %s headers %v, got %v", i+1, set.name, set.expected, set.got)
+				continue
+			}
+			for name, values := range set.expected {
+				if strings.Join(set.got[name], ",") != strings.Join(values, ",") {
+					t.Errorf("Test %d: Expected %s header %s: %v, got %v", i+1, set.name, name, values, set.got[name])
+				}
+			}
+		}
+	}
+}
+
+// Test that the headers given on the command line reach the server, signed or not as asked, and that
+// a header a test sets itself is not overridden.
+func TestCustomHeadersSent(t *testing.T) {
+	var received *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		writeS3Headers(w)
+	}))
+	defer server.Close()
+
+	config := newTestConfig(server.URL)
+	headers, err := newExtraHeaders([]string{"X-Gateway-Tenant: s3verify", "User-Agent: overridden"}, []string{"X-Trace: abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalCustomHeaders = headers
+	defer func() { globalCustomHeaders = nil }()
+
+	req, err := newGetObjectReq("s3verify-test", "object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.customHeader.Set("User-Agent", appUserAgent)
+	httpReq, err := config.newRequest("GET", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := config.Client.Do(httpReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainAndClose(res)
+
+	signed := map[string]bool{}
+	for _, name := range signedHeaderNames(received) {
+		signed[name] = true
+	}
+	testCases := []struct {
+		name   string
+		value  string
+		signed bool
+	}{
+		{"X-Gateway-Tenant", "s3verify", true},
+		{"X-Trace", "abc", false},
+		{"User-Agent", appUserAgent, false},
+	}
+	for _, testCase := range testCases {
+		if value := received.Header.Get(testCase.name); value != testCase.value {
+			t.Errorf("Expected %s: %s to be sent, got %q", testCase.name, testCase.value, value)
+		}
+		if signed[strings.ToLower(testCase.name)] != testCase.signed {
+			t.Errorf("Expected %s to be signed %v, SignedHeaders are %v", testCase.name, testCase.signed, signedHeaderNames(received))
+		}
+	}
+	if err := verifyCustomHeaders(received, headersWithout(headers, "User-Agent"), true); err != nil {
+		t.Error(err)
+	}
+}
+
+// headersWithout - headers with name removed from the signed ones.
+func headersWithout(headers *extraHeaders, name string) *extraHeaders {
+	signed := http.Header{}
+	for k, v := range headers.signed {
+		if k != name {
+			signed[k] = v
+		}
+	}
+	return &extraHeaders{signed: signed, unsigned: headers.unsigned}
+}
@@ -24,6 +24,8 @@ type BucketInfo struct {
 	Name string `json:"name"`
 	// Date the bucket was created.
 	CreationDate time.Time `json:"creationDate"`
+	// The configuration s3verify gave the bucket.
+	Capabilities bucketCapability `json:"-"`
 }
 
 // ObjectInfo container for object metadata.
@@ -50,8 +52,13 @@ type ObjectInfo struct {
 	// Error
 	Err error `json:"-"`
 
-	Body     []byte // Data held by the object.
-	UploadID string // To be set only for multipart uploaded objects.
+	Body      []byte // Data held by the object.
+	UploadID  string // To be set only for multipart uploaded objects.
+	VersionID string // To be set only for objects stored in versioned buckets.
+
+	// The server-side encryption the object was uploaded with, empty if none. The ETag of an
+	// SSE-C or SSE-KMS object is not the MD5 of its body.
+	Encryption string
 }
 
 // ObjectInfos - A container for ObjectInfo structs to allow sorting.
@@ -0,0 +1,65 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// maxDateSkew - the largest difference between the server's Date and the local clock not warned about.
+// Signatures are rejected beyond 15 minutes, a server drifting towards that is worth knowing about.
+const maxDateSkew = 5 * time.Minute
+
+// dateSkew - tracks the largest difference between a response Date and the local clock.
+type dateSkew struct {
+	mutex   sync.Mutex
+	largest time.Duration
+	date    string // The Date header the largest skew was seen in.
+}
+
+// observe - account for a response Date parsed from header received now.
+func (d *dateSkew) observe(date time.Time, header string) {
+	if d == nil {
+		return
+	}
+	skew := time.Since(date)
+	if skew < 0 {
+		skew = -skew
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if skew > d.largest {
+		d.largest = skew
+		d.date = header
+	}
+}
+
+// warning - a warning if any response Date was off by more than maxDateSkew, otherwise empty.
+func (d *dateSkew) warning() string {
+	if d == nil {
+		return ""
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.largest <= maxDateSkew {
+		return ""
+	}
+	return fmt.Sprintf("Warning: the server's Date differed from the local clock by up to %v (Date: %s), signatures and conditional requests may fail.",
+		d.largest.Truncate(time.Second), d.date)
+}
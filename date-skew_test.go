@@ -0,0 +1,63 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test that only a Date further off the local clock than maxDateSkew is warned about.
+func TestDateSkewWarning(t *testing.T) {
+	testCases := []struct {
+		skew    time.Duration
+		warning bool
+	}{
+		{0, false},
+		{maxDateSkew - time.Minute, false},
+		{maxDateSkew + time.Minute, true},
+		{-maxDateSkew - time.Minute, true},
+	}
+	for i, testCase := range testCases {
+		skew := &dateSkew{}
+		date := time.Now().Add(testCase.skew).UTC()
+		skew.observe(date, date.Format(http.TimeFormat))
+		warning := skew.warning()
+		if testCase.warning && !strings.HasPrefix(warning, "Warning: the server's Date differed") {
+			t.Errorf("Test %d: Expected a warning for a skew of %v, got %q", i+1, testCase.skew, warning)
+		}
+		if !testCase.warning && warning != "" {
+			t.Errorf("Test %d: Expected no warning for a skew of %v, got %q", i+1, testCase.skew, warning)
+		}
+	}
+}
+
+// Test that the finalizers of a run, e.g. the date skew warning, run once and last registered first.
+func TestFinishRun(t *testing.T) {
+	order := []int{}
+	runFinalizers = []func(){
+		func() { order = append(order, 1) },
+		func() { order = append(order, 2) },
+	}
+	finishRun()
+	finishRun()
+	if len(order) != 2 || order[0] != 2 || order[1] != 1 {
+		t.Fatalf("Expected the finalizers to run once in reverse order, got %v", order)
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// newRemoveObjectIfMatchReq - Create a new DELETE object HTTP request that only succeeds if the object has the given ETag.
+func newRemoveObjectIfMatchReq(config ServerConfig, bucketName, objectName, ETag string) (Request, error) {
+	removeObjectIfMatchReq, err := newRemoveObjectReq(config, bucketName, objectName)
+	if err != nil {
+		return Request{}, err
+	}
+	removeObjectIfMatchReq.customHeader.Set("If-Match", ETag)
+
+	return removeObjectIfMatchReq, nil
+}
+
+// mainRemoveObjectIfMatch - Test a conditional DELETE object that must only remove the object while its ETag matches.
+func mainRemoveObjectIfMatch(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] RemoveObject (If-Match):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/delete/if-match",
+		Body: generatedBody(time.Now().UnixNano(), 1024),
+	}
+	header, err := putObject(config, bucketName, object)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	ETag := header.Get("ETag")
+	// Spin scanBar
+	scanBar(message)
+	// Delete with a stale ETag, which must leave the object in place.
+	staleReq, err := newRemoveObjectIfMatchReq(config, bucketName, object.Key, "\"00000000000000000000000000000000\"")
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	staleRes, err := config.execRequest("DELETE", staleReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(staleRes)
+	if staleRes.StatusCode != http.StatusPreconditionFailed && isNotImplemented(staleRes) {
+		if err := removeObject(config, bucketName, object.Key); err != nil {
+			printMessage(message, err)
+			return false
+		}
+		printMessage(message, nil)
+		printDetail("Skipped: conditional deletes are not implemented")
+		return true
+	}
+	if err := verifyError(staleRes, http.StatusPreconditionFailed, "PreconditionFailed"); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// The object must have survived the failed delete.
+	if _, err := headObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Delete with the current ETag.
+	req, err := newRemoveObjectIfMatchReq(config, bucketName, object.Key, ETag)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	res, err := config.execRequest("DELETE", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if err := removeObjectVerify(res, http.StatusNoContent); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := verifyObjectRemoved(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,95 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// verifyDuplicateDeleted - verify the result of deleting key listed twice reports it as deleted at least
+// once, at most once per listing, with only NoSuchKey errors beside. It describes what was reported.
+func verifyDuplicateDeleted(result deleteObjectsResult, key string) (string, error) {
+	deleted, notFound := 0, 0
+	for _, entry := range result.Deleted {
+		if entry.Key != key {
+			err := fmt.Errorf("Unexpected Key Deleted: %s was not in the request", entry.Key)
+			return "", err
+		}
+		deleted++
+	}
+	for _, deleteErr := range result.Errors {
+		if deleteErr.Key != key || deleteErr.Code != "NoSuchKey" {
+			err := fmt.Errorf("Unexpected Error Received for %s: %s %s", deleteErr.Key, deleteErr.Code, deleteErr.Message)
+			return "", err
+		}
+		notFound++
+	}
+	if deleted == 0 {
+		err := fmt.Errorf("Missing Deleted Entry: %s was listed twice and not reported as deleted", key)
+		return "", err
+	}
+	if deleted+notFound > 2 {
+		err := fmt.Errorf("Unexpected Number of Results: %s was listed twice, got %d deleted and %d NoSuchKey entries", key, deleted, notFound)
+		return "", err
+	}
+	behavior := []string{fmt.Sprintf("reported deleted %d time(s)", deleted)}
+	if notFound > 0 {
+		behavior = append(behavior, fmt.Sprintf("NoSuchKey %d time(s)", notFound))
+	}
+	return "A key listed twice was " + strings.Join(behavior, " and "), nil
+}
+
+// mainDeleteObjectsDuplicateKeys - Test a DeleteObjects request listing the same key twice removes the object
+// rather than failing the request, as deleting is idempotent.
+func mainDeleteObjectsDuplicateKeys(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] DeleteObjects (Duplicate Keys):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/delete/duplicate",
+		Body: generatedBody(time.Now().UnixNano(), 60),
+	}
+	if _, err := putObject(config, bucketName, object); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	result, err := deleteObjectsRequest(config, bucketName, []string{object.Key, object.Key}, false)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	behavior, err := verifyDuplicateDeleted(result, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := verifyObjectRemoved(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	printDetail(behavior)
+	return true
+}
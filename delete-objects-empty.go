@@ -0,0 +1,78 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// deleteObjectsEmptyCodes - the error codes servers use to reject a DeleteObjects request with no keys.
+var deleteObjectsEmptyCodes = []string{"MalformedXML", "InvalidRequest"}
+
+// deleteObjectsEmptyVerify - verify a DeleteObjects request with no keys was rejected.
+func deleteObjectsEmptyVerify(res *http.Response) error {
+	if err := verifyStatusDeleteObjects(res.StatusCode, http.StatusBadRequest); err != nil {
+		return err
+	}
+	if err := verifyStandardHeaders(res.Header); err != nil {
+		return err
+	}
+	errResponse := ErrorResponse{}
+	if err := xmlDecoder(res.Body, &errResponse); err != nil {
+		return err
+	}
+	for _, code := range deleteObjectsEmptyCodes {
+		if errResponse.Code == code {
+			return nil
+		}
+	}
+	err := fmt.Errorf("Unexpected Error Response: wanted one of %v, got %v", strings.Join(deleteObjectsEmptyCodes, ", "), errResponse.Code)
+	return err
+}
+
+// mainDeleteObjectsEmpty - send a DeleteObjects request whose <Delete> element holds no <Object> entries
+// and verify it is rejected rather than answered with an empty DeleteResult.
+func mainDeleteObjectsEmpty(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] DeleteObjects (No Keys):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	req, err := newDeleteObjectsReq(bucketName, nil, false)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	res, err := config.execRequest("POST", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	// Spin scanBar
+	scanBar(message)
+	if err := deleteObjectsEmptyVerify(res); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,260 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// maxDeleteKeys - the largest number of keys a single DeleteObjects request may hold.
+const maxDeleteKeys = 1000
+
+// newDeleteObjectsReq - Create a new HTTP request for the DeleteObjects API.
+func newDeleteObjectsReq(bucketName string, objects []deleteObject, quiet bool) (Request, error) {
+	// deleteObjectsReq - a new HTTP request for DeleteObjects.
+	var deleteObjectsReq = Request{
+		customHeader: http.Header{},
+	}
+
+	// Set the bucketName.
+	deleteObjectsReq.bucketName = bucketName
+
+	// Set the query values.
+	urlValues := make(url.Values)
+	urlValues.Set("delete", "")
+	deleteObjectsReq.queryValues = urlValues
+
+	if len(objects) > maxDeleteKeys {
+		err := fmt.Errorf("Too Many Keys: a DeleteObjects request holds at most %d keys, got %d", maxDeleteKeys, len(objects))
+		return Request{}, err
+	}
+	deleteBytes, err := xml.Marshal(deleteObjects{
+		Quiet:   quiet,
+		Objects: objects,
+	})
+	if err != nil {
+		return Request{}, err
+	}
+	reader := bytes.NewReader(deleteBytes)
+	md5Sum, sha256Sum, contentLength, err := computeHash(reader)
+	if err != nil {
+		return Request{}, err
+	}
+
+	// Set the body, header and content length. Content-MD5 is required for DeleteObjects.
+	deleteObjectsReq.contentBody = reader
+	deleteObjectsReq.contentLength = contentLength
+	deleteObjectsReq.customHeader.Set("Content-MD5", base64.StdEncoding.EncodeToString(md5Sum))
+	deleteObjectsReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
+	deleteObjectsReq.customHeader.Set("User-Agent", appUserAgent)
+
+	return deleteObjectsReq, nil
+}
+
+// deleteObjectsVerify - verify the response returned matches what is expected and return the result.
+func deleteObjectsVerify(res *http.Response, expectedStatusCode int) (deleteObjectsResult, error) {
+	if err := verifyStatusDeleteObjects(res.StatusCode, expectedStatusCode); err != nil {
+		return deleteObjectsResult{}, err
+	}
+	if err := verifyHeaderDeleteObjects(res.Header); err != nil {
+		return deleteObjectsResult{}, err
+	}
+	return verifyBodyDeleteObjects(res.Body)
+}
+
+// verifyStatusDeleteObjects - verify the status returned matches what is expected.
+func verifyStatusDeleteObjects(respStatusCode, expectedStatusCode int) error {
+	if respStatusCode != expectedStatusCode {
+		err := fmt.Errorf("Unexpected Status Received: wanted %v, got %v", expectedStatusCode, respStatusCode)
+		return err
+	}
+	return nil
+}
+
+// verifyHeaderDeleteObjects - verify the header returned matches what is expected.
+func verifyHeaderDeleteObjects(header http.Header) error {
+	if err := verifyStandardHeaders(header); err != nil {
+		return err
+	}
+	return nil
+}
+
+// verifyBodyDeleteObjects - verify the body returned is a valid DeleteResult.
+func verifyBodyDeleteObjects(resBody io.Reader) (deleteObjectsResult, error) {
+	result := deleteObjectsResult{}
+	if err := xmlDecoder(resBody, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// deleteObjectsBatch - remove objects with a single DeleteObjects request and fail on any key not removed.
+// Governance retention is bypassed if bypassGovernance is set.
+func deleteObjectsBatch(config ServerConfig, bucketName string, objects []deleteObject, bypassGovernance bool) error {
+	req, err := newDeleteObjectsReq(bucketName, objects, true)
+	if err != nil {
+		return err
+	}
+	if bypassGovernance {
+		req.customHeader.Set("x-amz-bypass-governance-retention", "true")
+	}
+	res, err := config.execRequest("POST", req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	result, err := deleteObjectsVerify(res, http.StatusOK)
+	if err != nil {
+		return err
+	}
+	if len(result.Errors) > 0 {
+		deleteErr := result.Errors[0]
+		err := fmt.Errorf("Unable to remove %s (versionId %q): %s %s", deleteErr.Key, deleteErr.VersionID, deleteErr.Code, deleteErr.Message)
+		return err
+	}
+	return nil
+}
+
+// deleteObjectsRequest - send a DeleteObjects request for keys and return the verified result.
+func deleteObjectsRequest(config ServerConfig, bucketName string, keys []string, quiet bool) (deleteObjectsResult, error) {
+	objects := []deleteObject{}
+	for _, key := range keys {
+		objects = append(objects, deleteObject{Key: key})
+	}
+	req, err := newDeleteObjectsReq(bucketName, objects, quiet)
+	if err != nil {
+		return deleteObjectsResult{}, err
+	}
+	res, err := config.execRequest("POST", req)
+	if err != nil {
+		return deleteObjectsResult{}, err
+	}
+	defer drainAndClose(res)
+	return deleteObjectsVerify(res, http.StatusOK)
+}
+
+// verifyDeletedKeys - verify the result reports every key in keys as deleted and nothing else.
+// A key in missingKeys never existed and may be reported as deleted or as a NoSuchKey error.
+func verifyDeletedKeys(result deleteObjectsResult, keys []string, missingKeys []string) error {
+	expected := make(map[string]bool)
+	for _, key := range keys {
+		expected[key] = true
+	}
+	missing := make(map[string]bool)
+	for _, key := range missingKeys {
+		missing[key] = true
+	}
+	for _, deleteErr := range result.Errors {
+		if missing[deleteErr.Key] && deleteErr.Code == "NoSuchKey" {
+			delete(missing, deleteErr.Key)
+			continue
+		}
+		err := fmt.Errorf("Unexpected Error Received for %s: %s %s", deleteErr.Key, deleteErr.Code, deleteErr.Message)
+		return err
+	}
+	for _, deleted := range result.Deleted {
+		switch {
+		case expected[deleted.Key]:
+			delete(expected, deleted.Key)
+		case missing[deleted.Key]:
+			delete(missing, deleted.Key)
+		default:
+			err := fmt.Errorf("Unexpected Key Deleted: %s was not in the request or was reported twice", deleted.Key)
+			return err
+		}
+	}
+	for key := range expected {
+		err := fmt.Errorf("Missing Deleted Entry: %s was not reported as deleted", key)
+		return err
+	}
+	for key := range missing {
+		err := fmt.Errorf("Missing Result for %s: a key that does not exist must be reported as deleted or as NoSuchKey", key)
+		return err
+	}
+	return nil
+}
+
+// mainDeleteObjects - Test DeleteObjects on a batch of objects, first verbose with a key that does not exist
+// mixed in, then in quiet mode where only errors are returned.
+func mainDeleteObjects(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] DeleteObjects:", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	seed := time.Now().UnixNano()
+	keys := []string{}
+	for i := 0; i < 10; i++ {
+		// Spin scanBar
+		scanBar(message)
+		object := &ObjectInfo{
+			Key:  "s3verify/delete/objects/" + strconv.Itoa(i),
+			Body: generatedBody(seed+int64(i), 60),
+		}
+		if _, err := putObject(config, bucketName, object); err != nil {
+			printMessage(message, err)
+			return false
+		}
+		keys = append(keys, object.Key)
+	}
+	verboseKeys, quietKeys := keys[:5], keys[5:]
+	// Spin scanBar
+	scanBar(message)
+	// A key that does not exist must not fail the whole request.
+	missingKey := "s3verify/delete/objects/missing"
+	result, err := deleteObjectsRequest(config, bucketName, append(append([]string{}, verboseKeys...), missingKey), false)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyDeletedKeys(result, verboseKeys, []string{missingKey}); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Quiet mode only reports errors, and there are none.
+	result, err = deleteObjectsRequest(config, bucketName, quietKeys, true)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if len(result.Deleted) != 0 || len(result.Errors) != 0 {
+		err := fmt.Errorf("Unexpected Quiet Result Received: wanted no entries, got %d deleted and %d errors", len(result.Deleted), len(result.Errors))
+		printMessage(message, err)
+		return false
+	}
+	for _, key := range keys {
+		// Spin scanBar
+		scanBar(message)
+		if err := verifyObjectRemoved(config, bucketName, key); err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,133 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// infoReporter - a silentReporter that keeps what it is given to print.
+type infoReporter struct {
+	silentReporter
+	infos []string
+}
+
+func (r *infoReporter) info(info string) { r.infos = append(r.infos, info) }
+
+// Test that a dry run answers a signed request with a synthetic response and prints it, without
+// ever connecting to the endpoint.
+func TestDryRunTransport(t *testing.T) {
+	// The endpoint closes every connection without answering, so a request sent for real would fail.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	var connections int32
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			atomic.AddInt32(&connections, 1)
+			conn.Close()
+		}
+	}()
+
+	config := newTestConfig("http://" + listener.Addr().String())
+	reporter := &infoReporter{}
+	globalReporter = reporter
+	config.DryRun = true
+	config.Client = &http.Client{Transport: dryRunTransport{}, Timeout: 5 * time.Second}
+	body := []byte("s3verify dry run")
+	req, err := newPutObjectReq("s3verify-dry-run", "s3verify/object", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainAndClose(res)
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", res.StatusCode)
+	}
+	md5Sum := md5.Sum(body)
+	if etag := res.Header.Get("ETag"); etag != "\""+hex.EncodeToString(md5Sum[:])+"\"" {
+		t.Errorf("Expected the MD5 of the body as ETag, got %s", etag)
+	}
+	if requestID := res.Header.Get("X-Amz-Request-Id"); requestID != "s3verify-dry-run" {
+		t.Errorf("Expected a synthetic request id, got %s", requestID)
+	}
+	if err := verifyStandardHeaders(res.Header); err != nil {
+		t.Errorf("Expected the synthetic response to pass the standard checks, got %v", err)
+	}
+	if n := atomic.LoadInt32(&connections); n != 0 {
+		t.Errorf("Expected no connection to the endpoint, got %d", n)
+	}
+
+	if len(reporter.infos) != 1 {
+		t.Fatalf("Expected one request printed, got %d", len(reporter.infos))
+	}
+	lines := strings.Split(strings.TrimSuffix(reporter.infos[0], "\n"), "\n")
+	if want := "PUT " + config.Endpoint + "/s3verify-dry-run/s3verify/object"; lines[0] != want {
+		t.Errorf("Expected the request line %q, got %q", want, lines[0])
+	}
+	sha256Sum := sha256.Sum256(body)
+	headers := map[string]string{}
+	names := []string{}
+	for _, line := range lines[1:] {
+		parts := strings.SplitN(line, ": ", 2)
+		if len(parts) != 2 {
+			t.Fatalf("Expected a header line, got %q", line)
+		}
+		headers[parts[0]] = parts[1]
+		names = append(names, parts[0])
+	}
+	for i := 1; i < len(names); i++ {
+		if names[i-1] > names[i] {
+			t.Errorf("Expected the headers sorted by name, got %v", names)
+			break
+		}
+	}
+	if value := headers["Host"]; value != listener.Addr().String() {
+		t.Errorf("Expected Host %s, got %q", listener.Addr(), value)
+	}
+	if value := headers["Content-Length"]; value != "16" {
+		t.Errorf("Expected Content-Length 16, got %q", value)
+	}
+	if value := headers["X-Amz-Content-Sha256"]; value != hex.EncodeToString(sha256Sum[:]) {
+		t.Errorf("Expected the SHA-256 of the body as X-Amz-Content-Sha256, got %q", value)
+	}
+	if value := headers["Authorization"]; !strings.HasPrefix(value, "AWS4-HMAC-SHA256 Credential=s3verify-access/") ||
+		!strings.Contains(value, "SignedHeaders=") || !strings.Contains(value, "Signature=") {
+		t.Errorf("Expected a signature V4 Authorization, got %q", value)
+	}
+	if value := headers["X-Amz-Date"]; value == "" {
+		t.Error("Expected X-Amz-Date to be printed")
+	}
+}
@@ -0,0 +1,232 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// listDeletablePage - list a page of everything that must be removed to empty a bucket.
+// Versioned listings return every version and delete marker, otherwise plain keys are listed.
+func listDeletablePage(config ServerConfig, bucketName, keyMarker, versionIDMarker string, useVersions bool) (objects []deleteObject, nextKeyMarker, nextVersionIDMarker string, isTruncated bool, err error) {
+	if useVersions {
+		parameters := map[string]string{}
+		if keyMarker != "" {
+			parameters["key-marker"] = keyMarker
+			parameters["version-id-marker"] = versionIDMarker
+		}
+		receivedList, err := listObjectVersionsPage(config, bucketName, parameters)
+		if err != nil {
+			return nil, "", "", false, err
+		}
+		for _, version := range append(receivedList.Versions, receivedList.DeleteMarkers...) {
+			objects = append(objects, deleteObject{
+				Key:       version.Key,
+				VersionID: version.VersionID,
+			})
+		}
+		return objects, receivedList.NextKeyMarker, receivedList.NextVersionIDMarker, receivedList.IsTruncated, nil
+	}
+	parameters := map[string]string{}
+	if keyMarker != "" {
+		parameters["marker"] = keyMarker
+	}
+	receivedList, err := listObjectsPage(config, bucketName, parameters)
+	if err != nil {
+		return nil, "", "", false, err
+	}
+	for _, object := range receivedList.Contents {
+		objects = append(objects, deleteObject{
+			Key: object.Key,
+		})
+	}
+	if len(objects) > 0 {
+		nextKeyMarker = objects[len(objects)-1].Key
+	}
+	return objects, nextKeyMarker, "", receivedList.IsTruncated, nil
+}
+
+// abortIncompleteUploads - abort every multipart upload still in progress in bucketName.
+func abortIncompleteUploads(config ServerConfig, bucketName string) error {
+	for {
+		req, err := newListMultipartUploadsReq(bucketName, "")
+		if err != nil {
+			return err
+		}
+		res, err := config.execRequest("GET", req)
+		if err != nil {
+			return err
+		}
+		receivedList := listMultipartUploadsResult{}
+		err = verifyStatusListMultipartUploads(res.StatusCode, http.StatusOK)
+		if err == nil {
+			err = xmlDecoder(res.Body, &receivedList)
+		}
+		drainAndClose(res)
+		if err != nil {
+			return err
+		}
+		for _, upload := range receivedList.Uploads {
+			abortReq, err := newAbortMultipartUploadReq(bucketName, upload.Key, upload.UploadID)
+			if err != nil {
+				return err
+			}
+			abortRes, err := config.execRequest("DELETE", abortReq)
+			if err != nil {
+				return err
+			}
+			err = verifyStatusAbortMultipartUpload(abortRes.StatusCode, http.StatusNoContent)
+			drainAndClose(abortRes)
+			if err != nil {
+				return err
+			}
+		}
+		// Aborted uploads are no longer listed so the next listing starts over.
+		if !receivedList.IsTruncated || len(receivedList.Uploads) == 0 {
+			return nil
+		}
+	}
+}
+
+// emptyBucketBatches - remove every object, version, delete marker and incomplete upload in bucketName
+// and return the number of DeleteObjects batches it took. Legal holds are released and governance
+// retention is bypassed, versions under compliance retention can not be removed.
+func emptyBucketBatches(config ServerConfig, bucketName string) (int, error) {
+	// Servers without versioning support reject version listings, fall back to plain listings there.
+	_, err := listObjectVersionsPage(config, bucketName, map[string]string{"max-keys": "1"})
+	useVersions := err == nil
+	// Versions in a bucket with object lock enabled may be held or retained.
+	locked := useVersions && bucketHasObjectLock(config, bucketName)
+
+	batches := 0
+	keyMarker, versionIDMarker := "", ""
+	for {
+		objects, nextKeyMarker, nextVersionIDMarker, isTruncated, err := listDeletablePage(config, bucketName, keyMarker, versionIDMarker, useVersions)
+		if err != nil {
+			return batches, err
+		}
+		// A page never lists more than 1000 entries, but servers may ignore that.
+		for len(objects) > 0 {
+			batch := objects
+			if len(batch) > maxDeleteKeys {
+				batch = objects[:maxDeleteKeys]
+			}
+			if locked {
+				releaseLegalHolds(config, bucketName, batch)
+			}
+			if err := deleteObjectsBatch(config, bucketName, batch, locked); err != nil {
+				return batches, err
+			}
+			objects = objects[len(batch):]
+			batches++
+		}
+		if !isTruncated {
+			break
+		}
+		keyMarker, versionIDMarker = nextKeyMarker, nextVersionIDMarker
+	}
+	if err := abortIncompleteUploads(config, bucketName); err != nil {
+		return batches, err
+	}
+	// Only return once the bucket is confirmed to be empty.
+	remaining, _, _, _, err := listDeletablePage(config, bucketName, "", "", useVersions)
+	if err != nil {
+		return batches, err
+	}
+	if len(remaining) != 0 {
+		err := fmt.Errorf("Unable to empty bucket %s: %s is still listed", bucketName, remaining[0].Key)
+		return batches, err
+	}
+	return batches, nil
+}
+
+// emptyBucket - remove every object, version, delete marker and incomplete upload in bucketName.
+func emptyBucket(config ServerConfig, bucketName string) error {
+	_, err := emptyBucketBatches(config, bucketName)
+	return err
+}
+
+// removeBucketWithContents - empty bucketName and then remove it.
+func removeBucketWithContents(config ServerConfig, bucketName string) error {
+	if err := emptyBucket(config, bucketName); err != nil {
+		return err
+	}
+	return removeBucket(config, bucketName)
+}
+
+// mainEmptyBucket - verify that a bucket holding more objects than fit in one DeleteObjects request is fully emptied.
+func mainEmptyBucket(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] DeleteObjects (Empty Bucket):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := "s3verify-" + globalSuffix + "-batch"
+	putBucketReq, err := newPutBucketReq(config.Region, bucketName)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	putBucketRes, err := config.execRequest("PUT", putBucketReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(putBucketRes)
+	if err := putBucketVerify(putBucketRes, bucketName, http.StatusOK, ErrorResponse{}); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Upload 1500 objects, which needs two DeleteObjects batches to remove.
+	numObjects := maxDeleteKeys + maxDeleteKeys/2
+	for i := 0; i < numObjects; i++ {
+		// Spin scanBar
+		scanBar(message)
+		object := &ObjectInfo{
+			Key:  "s3verify/batch/" + strconv.Itoa(i),
+			Body: []byte(randString(60, rand.NewSource(time.Now().UnixNano()), "")),
+		}
+		if _, err := putObject(config, bucketName, object); err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Spin scanBar
+	scanBar(message)
+	batches, err := emptyBucketBatches(config, bucketName)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if batches != 2 {
+		err := fmt.Errorf("Unexpected Number of DeleteObjects Batches: wanted 2, got %d", batches)
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// The now empty bucket must be removable.
+	if err := removeBucketWithContents(config, bucketName); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,106 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// EndpointResolver - choose the endpoint a request on bucket is sent to, for setups where the
+// endpoint varies per bucket such as bucket specific gateways or FIPS endpoints.
+// The bucket is empty for requests on the service itself. The returned URL is used as is,
+// only its path and query are replaced, and its host is the one the request is signed for.
+type EndpointResolver func(bucket, region string) (url.URL, error)
+
+// targetURL - the URL of a request on the bucket and object, using the EndpointResolver if one is set.
+func (c ServerConfig) targetURL(bucketName, objectName string, queryValues url.Values) (*url.URL, error) {
+	if c.EndpointResolver == nil {
+		return makeTargetURL(c.Endpoint, bucketName, objectName, c.Region, queryValues)
+	}
+	targetURL, err := c.EndpointResolver(bucketName, c.Region)
+	if err != nil {
+		return nil, err
+	}
+	if targetURL.Scheme == "" || targetURL.Host == "" {
+		err := fmt.Errorf("Invalid Endpoint Resolved: %q for bucket %q must be an absolute URL", targetURL.String(), bucketName)
+		return nil, err
+	}
+	setTargetPath(&targetURL, bucketName, objectName, queryValues)
+	return &targetURL, nil
+}
+
+// mainEndpointResolver - verify a custom EndpointResolver is consulted for each request and
+// that the URL it returns is both where the request is sent and the host it is signed for.
+func mainEndpointResolver(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Endpoint Resolver:", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	// Resolve to the endpoint the default would use, so the server can still be reached,
+	// but record what the resolver was asked and what it answered.
+	defaultURL, err := makeTargetURL(config.Endpoint, "", "", config.Region, nil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	var resolvedBuckets []string
+	var resolvedRegions []string
+	config.EndpointResolver = func(bucket, region string) (url.URL, error) {
+		resolvedBuckets = append(resolvedBuckets, bucket)
+		resolvedRegions = append(resolvedRegions, region)
+		return url.URL{Scheme: defaultURL.Scheme, Host: defaultURL.Host}, nil
+	}
+	req, err := newHeadBucketReq(bucketName)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Inspect the request exactly as it would be sent.
+	httpReq, err := config.newRequest("HEAD", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if len(resolvedBuckets) != 1 || resolvedBuckets[0] != bucketName || resolvedRegions[0] != config.Region {
+		err := fmt.Errorf("Unexpected Resolver Calls: wanted one for bucket %v in %v, got buckets %v in %v", bucketName, config.Region, resolvedBuckets, resolvedRegions)
+		printMessage(message, err)
+		return false
+	}
+	if httpReq.URL.Host != defaultURL.Host || httpReq.Host != defaultURL.Host {
+		err := fmt.Errorf("Unexpected Request Host: wanted %v from the resolver, got URL host %v and Host %v", defaultURL.Host, httpReq.URL.Host, httpReq.Host)
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// The signature is over the resolved host, so the server only accepts it if both agree.
+	res, err := config.execRequest("HEAD", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if err := headBucketVerify(res, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,123 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/minio/s3verify/signv4"
+)
+
+// resign - sign a copy of req again for host, as SigV4 would sign it had it been sent there.
+func resign(req *http.Request, host string, config ServerConfig) *http.Request {
+	resigned := *req
+	resignedURL := *req.URL
+	resignedURL.Host = host
+	resigned.URL = &resignedURL
+	resigned.Header = http.Header{}
+	for name, values := range req.Header {
+		if name != "Authorization" && name != "X-Amz-Date" {
+			resigned.Header[name] = values
+		}
+	}
+	return signv4.SignV4(resigned, config.Access, config.Secret, config.signingRegion())
+}
+
+// Test that requests are sent to the host the EndpointResolver returns, not the configured endpoint,
+// and that SigV4 signs that host.
+func TestEndpointResolver(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		writeS3Headers(w)
+	}))
+	defer server.Close()
+	resolvedURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The configured endpoint can not be reached, only the resolved one can.
+	config := newTestConfig("http://s3verify.invalid")
+	var resolvedBuckets []string
+	config.EndpointResolver = func(bucket, region string) (url.URL, error) {
+		resolvedBuckets = append(resolvedBuckets, bucket)
+		return url.URL{Scheme: resolvedURL.Scheme, Host: resolvedURL.Host}, nil
+	}
+	req, err := newHeadBucketReq("s3verify-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Sign again right away, the signatures only compare within the same second of X-Amz-Date.
+	var httpReq, resolved *http.Request
+	for attempt := 0; attempt < 3; attempt++ {
+		resolvedBuckets = nil
+		httpReq, err = config.newRequest("HEAD", req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resolved = resign(httpReq, resolvedURL.Host, config)
+		if resolved.Header.Get("X-Amz-Date") == httpReq.Header.Get("X-Amz-Date") {
+			break
+		}
+	}
+	if httpReq.URL.Host != resolvedURL.Host {
+		t.Errorf("Expected the request to target %s, got %s", resolvedURL.Host, httpReq.URL.Host)
+	}
+	if !strings.HasPrefix(httpReq.URL.Path, "/s3verify-test") {
+		t.Errorf("Expected the bucket in the path, got %s", httpReq.URL.Path)
+	}
+	if len(resolvedBuckets) != 1 || resolvedBuckets[0] != "s3verify-test" {
+		t.Errorf("Expected the resolver to be asked for s3verify-test once, got %v", resolvedBuckets)
+	}
+	// The signature must be the one for the resolved host, and differ from the one for the configured endpoint.
+	if resolved.Header.Get("Authorization") != httpReq.Header.Get("Authorization") {
+		t.Errorf("Expected the request to be signed for %s, got %q", resolvedURL.Host, httpReq.Header.Get("Authorization"))
+	}
+	configured := resign(httpReq, "s3verify.invalid", config)
+	if configured.Header.Get("Authorization") == httpReq.Header.Get("Authorization") {
+		t.Error("Expected the signature to depend on the host signed for")
+	}
+	res, err := config.execRequest("HEAD", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainAndClose(res)
+	if requests != 1 {
+		t.Errorf("Expected the resolved server to get the request, it got %d", requests)
+	}
+}
+
+// Test that a resolver returning anything but an absolute URL is refused.
+func TestEndpointResolverRelative(t *testing.T) {
+	config := newTestConfig("http://s3verify.invalid")
+	config.EndpointResolver = func(bucket, region string) (url.URL, error) {
+		return url.URL{Path: "/gateway"}, nil
+	}
+	req, err := newHeadBucketReq("s3verify-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `Invalid Endpoint Resolved: "/gateway" for bucket "s3verify-test" must be an absolute URL`
+	if _, err := config.newRequest("HEAD", req); err == nil || err.Error() != expected {
+		t.Errorf("Expected error %q, got %v", expected, err)
+	}
+}
@@ -0,0 +1,141 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// mainGetObjectNoSuchKey - Test that a GET of a key that does not exist fails with a well-formed NoSuchKey error.
+func mainGetObjectNoSuchKey(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] GetObject (NoSuchKey):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	objectName := "s3verify/missing/" + randString(60, rand.NewSource(time.Now().UnixNano()), "")
+	req, err := newGetObjectReq(bucketName, objectName, nil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if err := verifyError(res, http.StatusNotFound, "NoSuchKey"); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
+
+// mainPutObjectNoSuchBucket - Test that a PUT into a bucket that does not exist fails with a well-formed NoSuchBucket error.
+func mainPutObjectNoSuchBucket(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] PutObject (NoSuchBucket):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	// Generate a random bucketName.
+	bucketName := randString(60, rand.NewSource(time.Now().UnixNano()), "")
+	req, err := newPutObjectReq(bucketName, "s3verify/missing-bucket", generatedBody(time.Now().UnixNano(), 1024))
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if err := verifyError(res, http.StatusNotFound, "NoSuchBucket"); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
+
+// unsupportedMethod - an HTTP method S3 does not implement on buckets or objects.
+const unsupportedMethod = "PATCH"
+
+// verifyMethodNotAllowed - verify a request with unsupportedMethod fails with a well-formed
+// MethodNotAllowed error, and that an Allow header, if sent, does not list the method.
+func verifyMethodNotAllowed(config ServerConfig, req Request) error {
+	res, err := config.execRequest(unsupportedMethod, req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	allow := res.Header.Get("Allow")
+	if err := verifyError(res, http.StatusMethodNotAllowed, "MethodNotAllowed"); err != nil {
+		return err
+	}
+	for _, method := range strings.Split(allow, ",") {
+		if strings.TrimSpace(method) == unsupportedMethod {
+			err := fmt.Errorf("Unexpected Allow Header Received: %v lists the method that was not allowed", allow)
+			return err
+		}
+	}
+	return nil
+}
+
+// mainMethodNotAllowed - Test that an unsupported method on a bucket and on an object fails with
+// 405 MethodNotAllowed rather than a server error or a generic 400.
+func mainMethodNotAllowed(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Unsupported Method (MethodNotAllowed):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	bucketReq, err := newHeadBucketReq(bucketName)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyMethodNotAllowed(config, bucketReq); err != nil {
+		err = fmt.Errorf("%v on bucket: %v", unsupportedMethod, err)
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	objectReq, err := newGetObjectReq(bucketName, "s3verify/method-not-allowed", nil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyMethodNotAllowed(config, objectReq); err != nil {
+		err = fmt.Errorf("%v on object: %v", unsupportedMethod, err)
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -18,6 +18,8 @@ package main
 
 import (
 	"encoding/xml"
+	"fmt"
+	"io"
 	"net/http"
 )
 
@@ -28,6 +30,7 @@ import (
    <Message>Access Denied</Message>
    <BucketName>bucketName</BucketName>
    <Key>objectName</Key>
+   <Resource>/bucketName/objectName</Resource>
    <RequestId>F19772218238A85A</RequestId>
    <HostId>GuWkjyviSiGHizehqpmsD1ndz5NClSP19DOT+s2mv7gXGQ8/X1lhbDGiIJEXpGFD</HostId>
 </Error>
@@ -40,6 +43,7 @@ type ErrorResponse struct {
 	Message    string
 	BucketName string
 	Key        string
+	Resource   string
 	RequestID  string `xml:"RequestId"`
 	HostID     string `xml:"HostId"`
 
@@ -75,6 +79,37 @@ func (e ErrorResponse) Error() string {
 	return e.Message
 }
 
+// parseErrorResponse - Decode an S3 error XML body, which must at least carry a Code and a Message.
+func parseErrorResponse(body io.Reader) (ErrorResponse, error) {
+	errResponse := ErrorResponse{}
+	if err := xmlDecoder(body, &errResponse); err != nil {
+		err = fmt.Errorf("Malformed Error Response: %v", err)
+		return ErrorResponse{}, err
+	}
+	if errResponse.Code == "" || errResponse.Message == "" {
+		err := fmt.Errorf("Malformed Error Response: Code and Message are required, got %q and %q", errResponse.Code, errResponse.Message)
+		return ErrorResponse{}, err
+	}
+	return errResponse, nil
+}
+
+// verifyError - Verify that a request failed with the expected status and a well-formed error carrying the expected Code.
+func verifyError(res *http.Response, expectedStatusCode int, expectedCode string) error {
+	if res.StatusCode != expectedStatusCode {
+		err := StatusMismatchError{Expected: expectedStatusCode, Got: res.StatusCode}
+		return err
+	}
+	errResponse, err := parseErrorResponse(res.Body)
+	if err != nil {
+		return err
+	}
+	if errResponse.Code != expectedCode {
+		err := fmt.Errorf("Unexpected Error Code Received: wanted %v, got %v (%v)", expectedCode, errResponse.Code, errResponse.Message)
+		return err
+	}
+	return nil
+}
+
 // Common string for errors to report issue location in unexpected
 // cases.
 const (
@@ -0,0 +1,62 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// How long a request sent with Expect: 100-continue waits for the server's go-ahead before
+// sending its body anyway, for servers that ignore the header.
+const expectContinueTimeout = time.Second
+
+// Size of the body uploaded by the Expect: 100-continue test, large enough that waiting for the
+// go-ahead matters.
+const expectContinueBodySize = 8 * 1024 * 1024
+
+// mainPutObjectExpectContinue - Test the server accepts an upload with Expect: 100-continue that waits for its go-ahead.
+func mainPutObjectExpectContinue(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] PutObject (Expect: 100-continue):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	// The server must answer the Expect header with 100 Continue, or at least still accept the body.
+	expectConfig := config
+	expectConfig.ExpectContinue = true
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/put/expect-continue",
+		Body: generatedBody(time.Now().UnixNano(), expectContinueBodySize),
+	}
+	if _, err := putObject(expectConfig, bucketName, object); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := getVersionVerify(config, bucketName, object.Key, "", object.Body, ""); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
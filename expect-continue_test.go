@@ -0,0 +1,116 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingListener - a listener whose connections count the bytes read from them.
+type countingListener struct {
+	net.Listener
+	read *int64
+}
+
+// Accept - accept a connection that adds what is read from it to the count.
+func (l countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return countingConn{Conn: conn, read: l.read}, nil
+}
+
+// countingConn - a connection that counts the bytes read from it.
+type countingConn struct {
+	net.Conn
+	read *int64
+}
+
+// Read - read and count.
+func (c countingConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	atomic.AddInt64(c.read, int64(n))
+	return n, err
+}
+
+// newCountingServer - start a server with handler counting the bytes read from its connections in read.
+func newCountingServer(handler http.HandlerFunc, read *int64) *httptest.Server {
+	server := httptest.NewUnstartedServer(handler)
+	server.Listener = countingListener{Listener: server.Listener, read: read}
+	server.Start()
+	return server
+}
+
+// Test that a PUT with Expect: 100-continue a server rejects before asking for the body is answered
+// without the body being sent, and that one the server accepts sends the whole body.
+func TestExpectContinue(t *testing.T) {
+	testCases := []struct {
+		name           string
+		handler        http.HandlerFunc
+		expectedStatus int
+		bodySent       bool
+	}{
+		// Rejected without reading the body, so no 100 Continue is sent.
+		{"rejected", func(w http.ResponseWriter, r *http.Request) {
+			writeS3Error(w, http.StatusForbidden, "AccessDenied", "")
+		}, http.StatusForbidden, false},
+		// Reading the body sends 100 Continue.
+		{"accepted", func(w http.ResponseWriter, r *http.Request) {
+			ioutil.ReadAll(r.Body)
+			writeS3Headers(w)
+			w.WriteHeader(http.StatusOK)
+		}, http.StatusOK, true},
+	}
+	for _, testCase := range testCases {
+		var read int64
+		server := newCountingServer(testCase.handler, &read)
+		config := newTestConfig(server.URL)
+		config.ExpectContinue = true
+		config.Client = &http.Client{
+			Transport: &http.Transport{
+				ExpectContinueTimeout: expectContinueTimeout,
+			},
+		}
+		req, err := newPutObjectReq("s3verify-expect-continue", "object", generatedBody(time.Now().UnixNano(), expectContinueBodySize))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := config.execRequestOnce("PUT", req)
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.name, err)
+		}
+		drainAndClose(res)
+		server.Close()
+		if err := verifyStatusPutObject(res.StatusCode, testCase.expectedStatus); err != nil {
+			t.Errorf("%s: %v", testCase.name, err)
+		}
+		received := atomic.LoadInt64(&read)
+		if !testCase.bodySent && received >= expectContinueBodySize {
+			t.Errorf("%s: Expected the body not to be sent, %d bytes were received", testCase.name, received)
+		}
+		if testCase.bodySent && received < expectContinueBodySize {
+			t.Errorf("%s: Expected the %d byte body to be sent, %d bytes were received", testCase.name, expectContinueBodySize, received)
+		}
+	}
+}
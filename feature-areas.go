@@ -0,0 +1,75 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// The feature areas tests are registered with, to summarize the time spent per API surface.
+const (
+	areaBucket    = "bucket"
+	areaListing   = "listing"
+	areaMultipart = "multipart"
+	areaObject    = "object"
+	areaOther     = "other" // Anything that is not about one API surface, e.g. signing or connections.
+)
+
+// areaTiming - the number of tests in a feature area and the time they took together.
+type areaTiming struct {
+	area    string
+	tests   int
+	totalMs int64
+}
+
+// slowestAreaFirst - sorts area timings by descending total time.
+type slowestAreaFirst []areaTiming
+
+func (s slowestAreaFirst) Len() int           { return len(s) }
+func (s slowestAreaFirst) Less(i, j int) bool { return s[i].totalMs > s[j].totalMs }
+func (s slowestAreaFirst) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// areaSummary - the total and average time of the tests in every feature area, slowest first.
+func areaSummary(records []testRecord) string {
+	index := make(map[string]int)
+	timings := []areaTiming{}
+	for _, record := range records {
+		area := record.Area
+		if area == "" {
+			area = areaOther
+		}
+		i, ok := index[area]
+		if !ok {
+			i = len(timings)
+			index[area] = i
+			timings = append(timings, areaTiming{area: area})
+		}
+		timings[i].tests++
+		timings[i].totalMs += record.DurationMs
+	}
+	sort.Stable(slowestAreaFirst(timings))
+	summary := ""
+	for i, timing := range timings {
+		if i == 0 {
+			summary += "By area:"
+		}
+		summary += fmt.Sprintf("\n\t%-10s %3d tests %8s total %8s average", timing.area, timing.tests,
+			formatDurationMs(timing.totalMs), formatDurationMs(timing.totalMs/int64(timing.tests)))
+	}
+	return summary
+}
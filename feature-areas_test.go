@@ -0,0 +1,56 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test that every registered test is given one of the feature areas.
+func TestTestAreas(t *testing.T) {
+	areas := map[string]bool{areaBucket: true, areaListing: true, areaMultipart: true, areaObject: true, areaOther: true}
+	for name, tests := range map[string][]APItest{"preparedTests": preparedTests, "unpreparedTests": unpreparedTests, "readOnlyTests": readOnlyTests} {
+		for i, test := range tests {
+			if !areas[test.Area] {
+				t.Errorf("%s[%d]: Expected one of the feature areas, got %q", name, i, test.Area)
+			}
+		}
+	}
+}
+
+// Test that test times are summed up by the area the tests were registered with, slowest area first.
+func TestAreaSummary(t *testing.T) {
+	records := []testRecord{
+		{TestName: "GetObject", Area: areaObject, DurationMs: 100},
+		{TestName: "ListObjects (Bucket Root)", Area: areaListing, DurationMs: 400},
+		{TestName: "PutObject", Area: areaObject, DurationMs: 200},
+		{TestName: "Cleanup", DurationMs: 50},
+	}
+	lines := strings.Split(areaSummary(records), "\n")
+	if len(lines) != 4 || lines[0] != "By area:" {
+		t.Fatalf("Expected a heading and 3 areas, got %q", lines)
+	}
+	for i, area := range []string{areaListing, areaObject, areaOther} {
+		if fields := strings.Fields(lines[i+1]); fields[0] != area {
+			t.Errorf("Expected area %s in line %d, got %q", area, i+2, lines[i+1])
+		}
+	}
+	if fields := strings.Fields(lines[2]); fields[1] != "2" {
+		t.Errorf("Expected 2 object tests, got %q", lines[2])
+	}
+}
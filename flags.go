@@ -16,7 +16,11 @@
 
 package main
 
-import "github.com/minio/cli"
+import (
+	"time"
+
+	"github.com/minio/cli"
+)
 
 // Collection of flags currently supported by every command.
 var globalFlags = []cli.Flag{
@@ -32,6 +36,10 @@ var globalFlags = []cli.Flag{
 		// Allow env. variables to be used as well as flags.
 		EnvVar: "S3_SECRET",
 	},
+	cli.StringFlag{
+		Name:  "credential-process",
+		Usage: "Command printing JSON credentials to sign requests with instead of --access and --secret",
+	},
 	cli.StringFlag{
 		Name:  "region, r",
 		Value: globalDefaultRegion,
@@ -46,7 +54,12 @@ var globalFlags = []cli.Flag{
 	},
 	cli.BoolFlag{
 		Name:  "verbose, v",
-		Usage: "Enable verbose output",
+		Usage: "Enable verbose output, the same as --verbosity 3",
+	},
+	cli.IntFlag{
+		Name:  "verbosity",
+		Value: verbosityNormal,
+		Usage: "0 quiet, 1 normal, 2 a line per request or 3 full request and response dumps",
 	},
 	cli.BoolFlag{
 		Name:  "extended",
@@ -56,6 +69,10 @@ var globalFlags = []cli.Flag{
 		Name:  "prepare",
 		Usage: `Prepare a reusable testing environment`,
 	},
+	cli.BoolFlag{
+		Name:  "selfcheck",
+		Usage: `Validate the configuration with a quick round trip instead of running all tests`,
+	},
 	cli.StringFlag{
 		Name:  "clean",
 		Usage: `Remove anything suffixed by the passed id`,
@@ -64,4 +81,203 @@ var globalFlags = []cli.Flag{
 		Name:  "id",
 		Usage: "Provide a unique suffix for test objects/buckets",
 	},
+	cli.BoolFlag{
+		Name:  "disable-md5",
+		Usage: "Do not send Content-MD5 on uploads, skips MD5 calculation",
+	},
+	cli.BoolFlag{
+		Name:  "unsigned-payload",
+		Usage: "Sign uploads with UNSIGNED-PAYLOAD, skips SHA256 calculation. Cannot be combined with --disable-md5",
+	},
+	cli.StringFlag{
+		Name:  "reset-hook",
+		Usage: "URL to POST to before a run to reset server state, must return 2xx",
+	},
+	cli.IntFlag{
+		Name:  "list-retries",
+		Value: 0,
+		Usage: "Retry listings this many times for eventually consistent servers",
+	},
+	cli.BoolFlag{
+		Name:  "check-ocsp",
+		Usage: "Report whether the server staples a valid OCSP response to its TLS certificate",
+	},
+	cli.IntFlag{
+		Name:  "max-upload-bytes",
+		Value: 0,
+		Usage: "Abort the run before uploading more than this many bytes, 0 means no limit",
+	},
+	cli.StringFlag{
+		Name:  "socks5",
+		Usage: "Connect to the server through the SOCKS5 proxy at this host:port",
+	},
+	cli.BoolFlag{
+		Name:  "insecure",
+		Usage: "Do not verify the certificate of the server, takes precedence over --ca-bundle",
+	},
+	cli.StringFlag{
+		Name:  "ca-bundle",
+		Usage: "Verify the certificate of the server with the CA certificates in this PEM file",
+	},
+	cli.BoolFlag{
+		Name:  "expect-continue",
+		Usage: "Send Expect: 100-continue with every request that has a body",
+	},
+	cli.Int64Flag{
+		Name:  "throttle-bandwidth",
+		Value: 0,
+		Usage: "Limit the bytes per second sent and received to simulate a slow link, 0 means no limit",
+	},
+	cli.StringFlag{
+		Name:  "replay",
+		Usage: "Re-sign and replay the requests captured in this HAR file instead of running tests",
+	},
+	cli.BoolFlag{
+		Name:  "strict",
+		Usage: "Also run strict checks of details clients should not but sometimes do depend on",
+	},
+	cli.StringFlag{
+		Name:  "foreign-bucket",
+		Usage: "An existing bucket owned by another account, for cross account tests",
+	},
+	cli.StringFlag{
+		Name:  "kms-key-id",
+		Usage: "KMS key id or ARN for the SSE-KMS test, the server default key if not given",
+	},
+	cli.StringSliceFlag{
+		Name:  "header",
+		Usage: "Add the header \"Name: Value\" to every request without signing it, may be repeated",
+	},
+	cli.StringSliceFlag{
+		Name:  "signed-header",
+		Usage: "Add the header \"Name: Value\" to every request and sign it, may be repeated",
+	},
+	cli.StringFlag{
+		Name:  "read-only",
+		Usage: "Only run tests that do not write, on existing objects in this bucket[/prefix]",
+	},
+	cli.IntFlag{
+		Name:  "max-rps",
+		Value: 0,
+		Usage: "Send at most this many requests per second, 0 means no limit",
+	},
+	cli.StringFlag{
+		Name:  "benchmark",
+		Usage: "Instead of running tests benchmark put, get or mixed load",
+	},
+	cli.DurationFlag{
+		Name:  "duration",
+		Value: 30 * time.Second,
+		Usage: "How long --benchmark runs for",
+	},
+	cli.IntFlag{
+		Name:  "concurrency",
+		Value: 4,
+		Usage: "The number of parallel workers --benchmark uses",
+	},
+	cli.IntFlag{
+		Name:  "size",
+		Value: 1024 * 1024,
+		Usage: "The size in bytes of the objects --benchmark uses",
+	},
+	cli.StringFlag{
+		Name:  "signing-region",
+		Usage: "Sign requests for this region instead of --region, for gateways that require a fixed one",
+	},
+	cli.IntFlag{
+		Name:  "workers",
+		Value: 8,
+		Usage: "The number of objects PutObject and --prepare upload concurrently",
+	},
+	cli.Int64Flag{
+		Name:  "max-part-size",
+		Value: defaultMaxPartSize,
+		Usage: "The largest part size in bytes the server accepts, lower it for test servers with a smaller limit",
+	},
+	cli.StringFlag{
+		Name:  "signature-version",
+		Value: "v4",
+		Usage: "Sign requests with AWS Signature Version v2 or v4",
+	},
+	cli.StringFlag{
+		Name:  "format",
+		Value: "human",
+		Usage: "Report test results as human, json or junit",
+	},
+	cli.BoolFlag{
+		Name:  "durations",
+		Usage: "Print how long every passing test took",
+	},
+	cli.BoolFlag{
+		Name:  "probe-max-size",
+		Usage: "Search for the largest object a single PUT is accepted for instead of running the tests",
+	},
+	cli.Int64Flag{
+		Name:  "probe-ceiling",
+		Value: defaultProbeCeiling,
+		Usage: "The largest object size in bytes --probe-max-size tries",
+	},
+	cli.IntFlag{
+		Name:  "max-retries",
+		Value: 4,
+		Usage: "How often a request failing with a retryable error is sent again, 0 disables retries",
+	},
+	cli.DurationFlag{
+		Name:  "retry-delay",
+		Value: time.Second,
+		Usage: "The wait before the first retry, doubled for every further retry",
+	},
+	cli.DurationFlag{
+		Name:  "request-timeout",
+		Value: defaultRequestTimeout,
+		Usage: "Cancel a request that has not completed within this long",
+	},
+	cli.DurationFlag{
+		Name:  "dial-timeout",
+		Value: defaultDialTimeout,
+		Usage: "The longest opening a connection may take",
+	},
+	cli.IntFlag{
+		Name:  "max-idle-conns",
+		Value: defaultMaxIdleConnsPerHost,
+		Usage: "The number of idle connections kept open for reuse",
+	},
+	cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Print every signed request instead of sending it",
+	},
+	cli.IntFlag{
+		Name:  "object-count",
+		Value: 101,
+		Usage: "The number of objects PutObject uploads for the listing tests, over 1000 crosses a listing page boundary",
+	},
+	cli.IntFlag{
+		Name:  "object-size",
+		Value: 60,
+		Usage: "The size in bytes of each object PutObject uploads for the listing tests",
+	},
+	cli.BoolFlag{
+		Name:  "cleanup",
+		Usage: "Remove every object and bucket the run created once the tests have run",
+	},
+	cli.BoolFlag{
+		Name:  "cleanup-only",
+		Usage: "Instead of running the tests remove every object and bucket an earlier run with the same --id left behind",
+	},
+	cli.StringFlag{
+		Name:  "manifest",
+		Usage: "Write a JSON line to this file for every bucket and object the run creates, or with --cleanup-only read them from it",
+	},
+	cli.StringFlag{
+		Name:  "metrics-out",
+		Usage: "Write request counts, latencies and test results to this file in the Prometheus text format",
+	},
+	cli.StringFlag{
+		Name:  "run",
+		Usage: "Only run the tests whose names match this regexp, e.g. \"PutObject|Multipart\"",
+	},
+	cli.StringFlag{
+		Name:  "skip",
+		Usage: "Do not run the tests whose names match this regexp",
+	},
 }
@@ -0,0 +1,114 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+)
+
+// generatedReader - a deterministic io.ReadSeeker of pseudo-random data.
+// The same seed and size always produce the same bytes, so large bodies can be
+// hashed, sent and later verified by regenerating them instead of holding them in memory.
+type generatedReader struct {
+	seed   int64
+	size   int64
+	offset int64
+	random *rand.Rand
+}
+
+// newGeneratedReader - create a new generatedReader of size bytes from seed.
+func newGeneratedReader(seed, size int64) *generatedReader {
+	return &generatedReader{
+		seed:   seed,
+		size:   size,
+		random: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Read - fill p with the next generated bytes.
+func (g *generatedReader) Read(p []byte) (n int, err error) {
+	if g.offset >= g.size {
+		return 0, io.EOF
+	}
+	if remaining := g.size - g.offset; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err = g.random.Read(p)
+	g.offset += int64(n)
+	return n, err
+}
+
+// Seek - only rewinding to the start or seeking to the end is supported
+// since generating from an arbitrary offset would require generating everything before it.
+func (g *generatedReader) Seek(offset int64, whence int) (int64, error) {
+	switch {
+	case offset == 0 && whence == 0:
+		// Restart the generator from the beginning.
+		g.random = rand.New(rand.NewSource(g.seed))
+		g.offset = 0
+	case offset == 0 && whence == 2:
+		g.offset = g.size
+	default:
+		return g.offset, fmt.Errorf("generatedReader: unsupported seek to %d from %d", offset, whence)
+	}
+	return g.offset, nil
+}
+
+// generatedBody - the size bytes a generatedReader produces for seed, for bodies small enough to hold in memory.
+func generatedBody(seed int64, size int) []byte {
+	body := make([]byte, size)
+	// Reading from a generatedReader never fails before size bytes have been produced.
+	io.ReadFull(newGeneratedReader(seed, int64(size)), body)
+	return body
+}
+
+// verifyBodyStream - compare a response body against the expected data without buffering either in full.
+func verifyBodyStream(resBody io.Reader, expected io.Reader) error {
+	const chunkSize = 32 * 1024
+	resChunk := make([]byte, chunkSize)
+	expectedChunk := make([]byte, chunkSize)
+	var offset int64
+	for {
+		expectedN, expectedErr := io.ReadFull(expected, expectedChunk)
+		resN, resErr := io.ReadFull(resBody, resChunk[:expectedN])
+		if resErr != nil && resErr != io.ErrUnexpectedEOF && resErr != io.EOF {
+			return resErr
+		}
+		if resN != expectedN {
+			err := fmt.Errorf("Unexpected Body Length Received: body ended after %d bytes", offset+int64(resN))
+			return err
+		}
+		if err := verifyBodyEqualAt(expectedChunk[:expectedN], resChunk[:resN], offset); err != nil {
+			return err
+		}
+		offset += int64(expectedN)
+		if expectedErr == io.EOF || expectedErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if expectedErr != nil {
+			return expectedErr
+		}
+	}
+	// Nothing should remain in the body.
+	if n, _ := resBody.Read(resChunk[:1]); n != 0 {
+		err := fmt.Errorf("Unexpected Body Length Received: body is longer than %d bytes", offset)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// newGetBucketACLReq - Create a new HTTP request for the GetBucketACL API.
+func newGetBucketACLReq(bucketName string) (Request, error) {
+	// getBucketACLReq - a new HTTP request for GetBucketACL.
+	var getBucketACLReq = Request{
+		customHeader: http.Header{},
+	}
+
+	// Set the bucketName.
+	getBucketACLReq.bucketName = bucketName
+
+	// Set the query values.
+	urlValues := make(url.Values)
+	urlValues.Set("acl", "")
+	getBucketACLReq.queryValues = urlValues
+
+	// No body is sent with GET requests.
+	reader := bytes.NewReader([]byte{})
+	_, sha256Sum, _, err := computeHash(reader)
+	if err != nil {
+		return Request{}, err
+	}
+
+	// Set the headers.
+	getBucketACLReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
+	getBucketACLReq.customHeader.Set("User-Agent", appUserAgent)
+
+	return getBucketACLReq, nil
+}
+
+// getBucketACLVerify - verify the response returned matches what is expected and return the decoded policy.
+func getBucketACLVerify(res *http.Response, expectedStatusCode int) (accessControlPolicy, error) {
+	if err := verifyStatusGetBucketACL(res.StatusCode, expectedStatusCode); err != nil {
+		return accessControlPolicy{}, err
+	}
+	if err := verifyHeaderGetBucketACL(res.Header); err != nil {
+		return accessControlPolicy{}, err
+	}
+	policy := accessControlPolicy{}
+	if err := xmlDecoder(res.Body, &policy); err != nil {
+		return accessControlPolicy{}, err
+	}
+	return policy, nil
+}
+
+// verifyStatusGetBucketACL - verify the status returned matches what is expected.
+func verifyStatusGetBucketACL(respStatusCode, expectedStatusCode int) error {
+	if respStatusCode != expectedStatusCode {
+		err := fmt.Errorf("Unexpected Status Received: wanted %v, got %v", expectedStatusCode, respStatusCode)
+		return err
+	}
+	return nil
+}
+
+// verifyHeaderGetBucketACL - verify the header returned matches what is expected.
+func verifyHeaderGetBucketACL(header http.Header) error {
+	if err := verifyStandardHeaders(header); err != nil {
+		return err
+	}
+	return nil
+}
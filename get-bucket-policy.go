@@ -140,6 +140,7 @@ func mainGetBucketPolicy(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
+	defer drainAndClose(res)
 	// Verify the response.
 	if err := getBucketPolicyVerify(res, 404, BucketAccessPolicy{}, expectedError); err != nil {
 		printMessage(message, err)
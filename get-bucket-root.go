@@ -0,0 +1,111 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// bucketRootResult - the parts of a response to GET on a bucket root that show how it was routed.
+type bucketRootResult struct {
+	XMLName  xml.Name
+	Name     string
+	Contents []ObjectInfo
+}
+
+// verifyBucketRootListing - verify the response to a GET of the bucket root is a listing of
+// bucketName rather than the result of a GET of an empty-named object.
+func verifyBucketRootListing(res *http.Response, bucketName string) error {
+	if res.StatusCode == http.StatusNotFound {
+		errResponse, err := parseErrorResponse(res.Body)
+		if err == nil && errResponse.Code == "NoSuchKey" {
+			err := fmt.Errorf("Bucket Root Routed as GetObject: wanted a ListBucketResult, got NoSuchKey for the empty key")
+			return err
+		}
+	}
+	if res.StatusCode != http.StatusOK {
+		err := StatusMismatchError{Expected: http.StatusOK, Got: res.StatusCode}
+		return err
+	}
+	if err := verifyStandardHeaders(res.Header); err != nil {
+		return err
+	}
+	result := bucketRootResult{}
+	if err := xmlDecoder(res.Body, &result); err != nil {
+		err = fmt.Errorf("Unexpected Body Received: wanted a ListBucketResult, got a body that is not XML: %v", err)
+		return err
+	}
+	if result.XMLName.Local != "ListBucketResult" {
+		err := fmt.Errorf("Unexpected Body Received: wanted a ListBucketResult, got a %v", result.XMLName.Local)
+		return err
+	}
+	if result.Name != bucketName {
+		err := fmt.Errorf("Unexpected Bucket Listed: wanted %v, got %v", bucketName, result.Name)
+		return err
+	}
+	if len(result.Contents) == 0 {
+		err := fmt.Errorf("Unexpected Empty Listing: the bucket root listed no objects although the bucket has some")
+		return err
+	}
+	return nil
+}
+
+// mainGetBucketRoot - Test that a GET of the bucket root with a trailing slash and no query lists the
+// bucket, rather than being routed as a GET of an object with an empty name.
+func mainGetBucketRoot(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] ListObjects (Bucket Root):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	// Make sure the bucket is not empty, so an empty listing is not mistaken for an empty body.
+	object := &ObjectInfo{
+		Key:  "s3verify/bucket-root/object",
+		Body: generatedBody(time.Now().UnixNano(), 60),
+	}
+	if _, err := putObject(config, bucketName, object); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Without parameters the request is a GET of /bucket/.
+	req, err := newListObjectsV1Req(bucketName, nil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if err := verifyBucketRootListing(res, bucketName); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,161 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// newGetBucketVersioningReq - Create a new HTTP request for the GetBucketVersioning API.
+func newGetBucketVersioningReq(bucketName string) (Request, error) {
+	// getBucketVersioningReq - a new HTTP request for GetBucketVersioning.
+	var getBucketVersioningReq = Request{
+		customHeader: http.Header{},
+	}
+
+	// Set the bucketName.
+	getBucketVersioningReq.bucketName = bucketName
+
+	// Set the query values.
+	urlValues := make(url.Values)
+	urlValues.Set("versioning", "")
+	getBucketVersioningReq.queryValues = urlValues
+
+	// No body is sent with GET requests.
+	reader := bytes.NewReader([]byte{})
+	_, sha256Sum, _, err := computeHash(reader)
+	if err != nil {
+		return Request{}, err
+	}
+
+	// Set the headers.
+	getBucketVersioningReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
+	getBucketVersioningReq.customHeader.Set("User-Agent", appUserAgent)
+
+	return getBucketVersioningReq, nil
+}
+
+// getBucketVersioningVerify - verify the response returned matches what is expected.
+func getBucketVersioningVerify(res *http.Response, expectedStatusCode int, expectedStatus string) error {
+	if err := verifyStatusGetBucketVersioning(res.StatusCode, expectedStatusCode); err != nil {
+		return err
+	}
+	if err := verifyHeaderGetBucketVersioning(res.Header); err != nil {
+		return err
+	}
+	if err := verifyBodyGetBucketVersioning(res.Body, expectedStatus); err != nil {
+		return err
+	}
+	return nil
+}
+
+// verifyStatusGetBucketVersioning - verify the status returned matches what is expected.
+func verifyStatusGetBucketVersioning(respStatusCode, expectedStatusCode int) error {
+	if respStatusCode != expectedStatusCode {
+		err := fmt.Errorf("Unexpected Status Received: wanted %v, got %v", expectedStatusCode, respStatusCode)
+		return err
+	}
+	return nil
+}
+
+// verifyHeaderGetBucketVersioning - verify the header returned matches what is expected.
+func verifyHeaderGetBucketVersioning(header http.Header) error {
+	if err := verifyStandardHeaders(header); err != nil {
+		return err
+	}
+	return nil
+}
+
+// verifyBodyGetBucketVersioning - verify the body is a VersioningConfiguration with the expected Status.
+// An empty expectedStatus means the Status element must be absent.
+func verifyBodyGetBucketVersioning(resBody io.Reader, expectedStatus string) error {
+	body, err := ioutil.ReadAll(resBody)
+	if err != nil {
+		return err
+	}
+	if len(body) == 0 {
+		err := fmt.Errorf("Unexpected Empty Body Received: wanted a VersioningConfiguration")
+		return err
+	}
+	// Decode without the namespace so a missing xmlns is not mistaken for a missing configuration.
+	received := struct {
+		XMLName xml.Name
+		Status  *string `xml:"Status"`
+	}{}
+	if err := xml.Unmarshal(body, &received); err != nil {
+		return err
+	}
+	if received.XMLName.Local != "VersioningConfiguration" {
+		err := fmt.Errorf("Unexpected Body Received: wanted a VersioningConfiguration, got %v", received.XMLName.Local)
+		return err
+	}
+	if expectedStatus == "" && received.Status != nil {
+		err := fmt.Errorf("Unexpected Status Element Received: wanted none, got %q", *received.Status)
+		return err
+	}
+	if expectedStatus != "" && received.Status == nil {
+		err := fmt.Errorf("Unexpected Versioning Status Received: wanted %v, got no Status element", expectedStatus)
+		return err
+	}
+	if expectedStatus != "" && *received.Status != expectedStatus {
+		err := fmt.Errorf("Unexpected Versioning Status Received: wanted %v, got %q", expectedStatus, *received.Status)
+		return err
+	}
+	return nil
+}
+
+// mainGetBucketVersioningUnset - GetBucketVersioning API test on a bucket which never had versioning configured.
+func mainGetBucketVersioningUnset(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] GetBucketVersioning (Never Configured):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	// s3verify never configures versioning on its own buckets.
+	bucketName := s3verifyBuckets[1].Name
+	req, err := newGetBucketVersioningReq(bucketName)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Execute the request.
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	// Spin scanBar
+	scanBar(message)
+	// Verify the response.
+	if err := getBucketVersioningVerify(res, http.StatusOK, ""); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,49 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test the GetBucketVersioning body checks report the Status that was received, not its address.
+func TestVerifyBodyGetBucketVersioning(t *testing.T) {
+	testCases := []struct {
+		body           string
+		expectedStatus string
+		err            string // The exact error expected, empty if the body is valid.
+	}{
+		{`<VersioningConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"/>`, "", ""},
+		{`<VersioningConfiguration><Status>Enabled</Status></VersioningConfiguration>`, "Enabled", ""},
+		{`<VersioningConfiguration><Status>Suspended</Status></VersioningConfiguration>`, "Enabled",
+			`Unexpected Versioning Status Received: wanted Enabled, got "Suspended"`},
+		{`<VersioningConfiguration/>`, "Enabled",
+			"Unexpected Versioning Status Received: wanted Enabled, got no Status element"},
+		{`<VersioningConfiguration><Status></Status></VersioningConfiguration>`, "",
+			`Unexpected Status Element Received: wanted none, got ""`},
+	}
+	for i, testCase := range testCases {
+		err := verifyBodyGetBucketVersioning(strings.NewReader(testCase.body), testCase.expectedStatus)
+		if testCase.err == "" && err != nil {
+			t.Errorf("Test %d: Expected no error, got %q", i+1, err)
+		}
+		if testCase.err != "" && (err == nil || err.Error() != testCase.err) {
+			t.Errorf("Test %d: Expected error %q, got %v", i+1, testCase.err, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// newGetObjectACLReq - Create a new HTTP request for the GetObjectACL API.
+func newGetObjectACLReq(bucketName, objectName string) (Request, error) {
+	// getObjectACLReq - a new HTTP request for GetObjectACL.
+	var getObjectACLReq = Request{
+		customHeader: http.Header{},
+	}
+
+	// Set the bucketName and objectName.
+	getObjectACLReq.bucketName = bucketName
+	getObjectACLReq.objectName = objectName
+
+	// Set the query values.
+	urlValues := make(url.Values)
+	urlValues.Set("acl", "")
+	getObjectACLReq.queryValues = urlValues
+
+	// No body is sent with GET requests.
+	reader := bytes.NewReader([]byte{})
+	_, sha256Sum, _, err := computeHash(reader)
+	if err != nil {
+		return Request{}, err
+	}
+
+	// Set the headers.
+	getObjectACLReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
+	getObjectACLReq.customHeader.Set("User-Agent", appUserAgent)
+
+	return getObjectACLReq, nil
+}
+
+// getObjectACLVerify - verify the response returned matches what is expected and return the decoded policy.
+func getObjectACLVerify(res *http.Response, expectedStatusCode int) (accessControlPolicy, error) {
+	if err := verifyStatusGetObjectACL(res.StatusCode, expectedStatusCode); err != nil {
+		return accessControlPolicy{}, err
+	}
+	if err := verifyHeaderGetObjectACL(res.Header); err != nil {
+		return accessControlPolicy{}, err
+	}
+	policy := accessControlPolicy{}
+	if err := xmlDecoder(res.Body, &policy); err != nil {
+		return accessControlPolicy{}, err
+	}
+	return policy, nil
+}
+
+// verifyStatusGetObjectACL - verify the status returned matches what is expected.
+func verifyStatusGetObjectACL(respStatusCode, expectedStatusCode int) error {
+	if respStatusCode != expectedStatusCode {
+		err := fmt.Errorf("Unexpected Status Received: wanted %v, got %v", expectedStatusCode, respStatusCode)
+		return err
+	}
+	return nil
+}
+
+// verifyHeaderGetObjectACL - verify the header returned matches what is expected.
+func verifyHeaderGetObjectACL(header http.Header) error {
+	if err := verifyStandardHeaders(header); err != nil {
+		return err
+	}
+	return nil
+}
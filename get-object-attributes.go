@@ -0,0 +1,223 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// objectAttributesCase - attribute groups to request and those of them that must be returned.
+// Groups requested but not required may be omitted, e.g. Checksum for an object uploaded without one.
+type objectAttributesCase struct {
+	Requested []string
+	Required  []string
+}
+
+// objectAttributesCases - the subsets of attributes requested by the GetObjectAttributes test.
+var objectAttributesCases = []objectAttributesCase{
+	objectAttributesCase{
+		Requested: []string{"ETag"},
+		Required:  []string{"ETag"},
+	},
+	objectAttributesCase{
+		Requested: []string{"ObjectSize"},
+		Required:  []string{"ObjectSize"},
+	},
+	objectAttributesCase{
+		Requested: []string{"ObjectParts", "Checksum"},
+		Required:  []string{"ObjectParts"},
+	},
+}
+
+// newGetObjectAttributesReq - Create a new HTTP request for the GetObjectAttributes API.
+func newGetObjectAttributesReq(bucketName, objectName string, attributes []string) (Request, error) {
+	// getObjectAttributesReq - a new HTTP request for GetObjectAttributes.
+	var getObjectAttributesReq = Request{
+		customHeader: http.Header{},
+	}
+
+	// Set the bucketName and objectName.
+	getObjectAttributesReq.bucketName = bucketName
+	getObjectAttributesReq.objectName = objectName
+
+	// Set the query values.
+	urlValues := make(url.Values)
+	urlValues.Set("attributes", "")
+	getObjectAttributesReq.queryValues = urlValues
+
+	// No body is sent with GET requests.
+	reader := bytes.NewReader([]byte{})
+	_, sha256Sum, _, err := computeHash(reader)
+	if err != nil {
+		return Request{}, err
+	}
+
+	// Set the headers.
+	getObjectAttributesReq.customHeader.Set("X-Amz-Object-Attributes", strings.Join(attributes, ","))
+	getObjectAttributesReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
+	getObjectAttributesReq.customHeader.Set("User-Agent", appUserAgent)
+
+	return getObjectAttributesReq, nil
+}
+
+// getObjectAttributesVerify - verify the response returned matches what is expected.
+func getObjectAttributesVerify(res *http.Response, expectedStatusCode int, attributesCase objectAttributesCase) error {
+	if err := verifyStatusGetObjectAttributes(res.StatusCode, expectedStatusCode); err != nil {
+		return err
+	}
+	if err := verifyHeaderGetObjectAttributes(res.Header); err != nil {
+		return err
+	}
+	if err := verifyBodyGetObjectAttributes(res.Body, attributesCase); err != nil {
+		return err
+	}
+	return nil
+}
+
+// verifyStatusGetObjectAttributes - verify the status returned matches what is expected.
+func verifyStatusGetObjectAttributes(respStatusCode, expectedStatusCode int) error {
+	if respStatusCode != expectedStatusCode {
+		err := fmt.Errorf("Unexpected Status Received: wanted %v, got %v", expectedStatusCode, respStatusCode)
+		return err
+	}
+	return nil
+}
+
+// verifyHeaderGetObjectAttributes - verify the header returned matches what is expected.
+func verifyHeaderGetObjectAttributes(header http.Header) error {
+	if err := verifyStandardHeaders(header); err != nil {
+		return err
+	}
+	return nil
+}
+
+// verifyBodyGetObjectAttributes - verify only the requested attribute groups were returned and none required are missing.
+func verifyBodyGetObjectAttributes(resBody io.Reader, attributesCase objectAttributesCase) error {
+	// Only the names of the returned groups matter here.
+	received := struct {
+		Groups []struct {
+			XMLName xml.Name
+		} `xml:",any"`
+	}{}
+	if err := xmlDecoder(resBody, &received); err != nil {
+		return err
+	}
+	requested := make(map[string]bool)
+	for _, group := range attributesCase.Requested {
+		requested[group] = true
+	}
+	returned := make(map[string]bool)
+	unrequested := []string{}
+	for _, group := range received.Groups {
+		name := group.XMLName.Local
+		returned[name] = true
+		if !requested[name] {
+			unrequested = append(unrequested, name)
+		}
+	}
+	if len(unrequested) > 0 {
+		sort.Strings(unrequested)
+		err := fmt.Errorf("Unrequested Attributes Received: asked for %v, also got %v", strings.Join(attributesCase.Requested, ","), strings.Join(unrequested, ","))
+		return err
+	}
+	for _, group := range attributesCase.Required {
+		if !returned[group] {
+			err := fmt.Errorf("Missing Attribute: %v was requested but not returned", group)
+			return err
+		}
+	}
+	return nil
+}
+
+// mainGetObjectAttributes - GetObjectAttributes API test requesting different subsets of attributes.
+func mainGetObjectAttributes(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] GetObjectAttributes (Attribute Subsets):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	// ObjectParts is only returned for multipart objects.
+	object := &ObjectInfo{
+		Key:  "s3verify/attributes/multipart",
+		Body: make([]byte, 1024*1024),
+	}
+	if _, err := io.ReadFull(crand.Reader, object.Body); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	uploadID, err := initiateMultipartUpload(config, bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	object.UploadID = uploadID
+	partETag, err := uploadPart(config, bucketName, object.Key, object.UploadID, 1, object.Body)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	complete := &completeMultipartUpload{
+		Parts: []completePart{
+			completePart{
+				PartNumber: 1,
+				ETag:       partETag,
+			},
+		},
+	}
+	if _, err := completeMultipart(config, bucketName, object.Key, object.UploadID, complete); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	for _, attributesCase := range objectAttributesCases {
+		// Spin scanBar
+		scanBar(message)
+		req, err := newGetObjectAttributesReq(bucketName, object.Key, attributesCase.Requested)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		res, err := config.execRequest("GET", req)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		err = getObjectAttributesVerify(res, http.StatusOK, attributesCase)
+		drainAndClose(res)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Remove the object so it does not interfere with future tests.
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,161 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// combinedConditionalCase - a GET carrying two conditional headers and the outcome S3 defines for it.
+//
+// S3 follows RFC 7232 section 6: when If-Match is present If-Unmodified-Since is ignored, and when
+// If-None-Match is present If-Modified-Since is ignored. So the ETag condition alone decides:
+//
+//	If-Match true,       If-Unmodified-Since false -> 200 OK
+//	If-Match false,      If-Unmodified-Since true  -> 412 Precondition Failed
+//	If-None-Match false, If-Modified-Since true    -> 304 Not Modified
+//	If-None-Match true,  If-Modified-Since false   -> 200 OK
+//
+// A server that evaluates only one of the two headers, or both with equal weight, gets one of these wrong.
+type combinedConditionalCase struct {
+	name           string
+	header         func(object *ObjectInfo) http.Header
+	expectedStatus int
+}
+
+// combinedConditionalCases - every pairing where the two headers disagree.
+var combinedConditionalCases = []combinedConditionalCase{
+	{
+		name: "If-Match true, If-Unmodified-Since false",
+		header: func(object *ObjectInfo) http.Header {
+			return http.Header{
+				"If-Match":            {object.ETag},
+				"If-Unmodified-Since": {object.LastModified.Add(-24 * time.Hour).UTC().Format(http.TimeFormat)},
+			}
+		},
+		expectedStatus: http.StatusOK,
+	},
+	{
+		name: "If-Match false, If-Unmodified-Since true",
+		header: func(object *ObjectInfo) http.Header {
+			return http.Header{
+				"If-Match":            {"\"1234567890\""},
+				"If-Unmodified-Since": {object.LastModified.Add(24 * time.Hour).UTC().Format(http.TimeFormat)},
+			}
+		},
+		expectedStatus: http.StatusPreconditionFailed,
+	},
+	{
+		name: "If-None-Match false, If-Modified-Since true",
+		header: func(object *ObjectInfo) http.Header {
+			return http.Header{
+				"If-None-Match":     {object.ETag},
+				"If-Modified-Since": {object.LastModified.Add(-24 * time.Hour).UTC().Format(http.TimeFormat)},
+			}
+		},
+		expectedStatus: http.StatusNotModified,
+	},
+	{
+		name: "If-None-Match true, If-Modified-Since false",
+		header: func(object *ObjectInfo) http.Header {
+			return http.Header{
+				"If-None-Match":     {"\"1234567890\""},
+				"If-Modified-Since": {object.LastModified.Add(24 * time.Hour).UTC().Format(http.TimeFormat)},
+			}
+		},
+		expectedStatus: http.StatusOK,
+	},
+}
+
+// newGetObjectConditionalReq - Create a new HTTP request for a GET object with the given conditional headers.
+func newGetObjectConditionalReq(bucketName, objectName string, conditions http.Header) (Request, error) {
+	var getObjectConditionalReq = Request{
+		customHeader: http.Header{},
+	}
+
+	// Set the bucketName and objectName.
+	getObjectConditionalReq.bucketName = bucketName
+	getObjectConditionalReq.objectName = objectName
+
+	reader := bytes.NewReader([]byte{}) // Compute hash using empty body because GET requests do not send a body.
+	_, sha256Sum, _, err := computeHash(reader)
+	if err != nil {
+		return Request{}, err
+	}
+
+	// Set the headers.
+	for k, v := range conditions {
+		getObjectConditionalReq.customHeader[k] = v
+	}
+	getObjectConditionalReq.customHeader.Set("User-Agent", appUserAgent)
+	getObjectConditionalReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
+
+	return getObjectConditionalReq, nil
+}
+
+// getObjectConditionalVerify - Verify the status decided by the conditions, and the body only if it was sent.
+func getObjectConditionalVerify(res *http.Response, objectBody []byte, expectedStatusCode int) error {
+	if err := verifyStandardHeaders(res.Header); err != nil {
+		return err
+	}
+	if err := verifyStatusGetObject(res.StatusCode, expectedStatusCode); err != nil {
+		return err
+	}
+	if expectedStatusCode != http.StatusOK {
+		return nil
+	}
+	return verifyBodyGetObject(res.Body, objectBody)
+}
+
+// mainGetObjectConditionalCombined - GET with pairs of conditional headers that disagree and verify
+// the ETag condition takes precedence over the date condition.
+func mainGetObjectConditionalCombined(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] GetObject (Combined Conditions):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	// The ETag and Last-Modified of the first object were recorded by HeadObject.
+	bucketName := s3verifyBuckets[0].Name
+	object := s3verifyObjects.snapshot()[0]
+	for _, conditionalCase := range combinedConditionalCases {
+		// Spin scanBar
+		scanBar(message)
+		req, err := newGetObjectConditionalReq(bucketName, object.Key, conditionalCase.header(object))
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		res, err := config.execRequest("GET", req)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		err = getObjectConditionalVerify(res, object.Body, conditionalCase.expectedStatus)
+		drainAndClose(res)
+		if err != nil {
+			err = fmt.Errorf("%s: %v", conditionalCase.name, err)
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,142 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// conditionalCase - a GET carrying one conditional header and the status S3 answers it with.
+type conditionalCase struct {
+	name           string
+	header         func(eTag string, lastModified time.Time) http.Header
+	expectedStatus int
+}
+
+// conditionalCases - the conditions that stop the object from being returned.
+var conditionalCases = []conditionalCase{
+	{
+		name: "If-None-Match current ETag",
+		header: func(eTag string, lastModified time.Time) http.Header {
+			return http.Header{"If-None-Match": {eTag}}
+		},
+		expectedStatus: http.StatusNotModified,
+	},
+	{
+		name: "If-Match bogus ETag",
+		header: func(eTag string, lastModified time.Time) http.Header {
+			return http.Header{"If-Match": {"\"1234567890\""}}
+		},
+		expectedStatus: http.StatusPreconditionFailed,
+	},
+	{
+		name: "If-Modified-Since in the future",
+		header: func(eTag string, lastModified time.Time) http.Header {
+			return http.Header{"If-Modified-Since": {lastModified.Add(24 * time.Hour).UTC().Format(http.TimeFormat)}}
+		},
+		expectedStatus: http.StatusNotModified,
+	},
+}
+
+// conditionalGetObjectVerify - verify a conditional GET was answered with expectedStatusCode and no object.
+// 304 responses must not carry a body at all, 412 responses carry a PreconditionFailed error.
+func conditionalGetObjectVerify(res *http.Response, expectedStatusCode int) error {
+	if err := verifyStatusGetObject(res.StatusCode, expectedStatusCode); err != nil {
+		return err
+	}
+	if err := verifyStandardHeaders(res.Header); err != nil {
+		return err
+	}
+	if expectedStatusCode == http.StatusPreconditionFailed {
+		errResponse := ErrorResponse{}
+		if err := xmlDecoder(res.Body, &errResponse); err != nil {
+			return err
+		}
+		if errResponse.Code != "PreconditionFailed" {
+			err := fmt.Errorf("Unexpected Error Response: wanted PreconditionFailed, got %v", errResponse.Code)
+			return err
+		}
+		return nil
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	return verifyBodyEmpty(body)
+}
+
+// mainConditionalGetObject - upload an object and GET it with conditions that fail against
+// its ETag and Last-Modified, verifying the exact status and that the object is not sent.
+func mainConditionalGetObject(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] GetObject (Conditional):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/get/conditional",
+		Body: []byte(randString(60, rand.NewSource(time.Now().UnixNano()), "")),
+	}
+	if _, err := putObject(config, bucketName, object); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Capture the validators as the server reports them.
+	header, err := headObject(config, bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	lastModified, err := time.Parse(http.TimeFormat, header.Get("Last-Modified"))
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	for _, c := range conditionalCases {
+		// Spin scanBar
+		scanBar(message)
+		req, err := newGetObjectConditionalReq(bucketName, object.Key, c.header(header.Get("ETag"), lastModified))
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		res, err := config.execRequest("GET", req)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		err = conditionalGetObjectVerify(res, c.expectedStatus)
+		drainAndClose(res)
+		if err != nil {
+			err = fmt.Errorf("%s: %v", c.name, err)
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,105 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// verifyEmptyContentLength - verify a response for a zero-byte object states its length as an explicit Content-Length: 0.
+// Some clients wait for the connection to close, or fail, when the length of an empty body is left unstated.
+func verifyEmptyContentLength(res *http.Response) error {
+	if len(res.TransferEncoding) > 0 {
+		err := fmt.Errorf("Unexpected Transfer-Encoding Received: wanted none for a zero-byte object, got %v", res.TransferEncoding)
+		return err
+	}
+	if length, ok := res.Header["Content-Length"]; !ok || len(length) != 1 || length[0] != "0" {
+		err := fmt.Errorf("Unexpected Content-Length Received: wanted an explicit 0 for a zero-byte object, got %v", length)
+		return err
+	}
+	return nil
+}
+
+// mainGetObjectEmpty - GET and HEAD a zero-byte object and verify both state Content-Length: 0.
+func mainGetObjectEmpty(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] GetObject (Zero Bytes):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/get/empty",
+		Body: []byte{},
+	}
+	if _, err := putObject(config, bucketName, object); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	getReq, err := newGetObjectReq(bucketName, object.Key, nil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	getRes, err := config.execRequest("GET", getReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(getRes)
+	if err := verifyEmptyContentLength(getRes); err != nil {
+		err = fmt.Errorf("GET: %v", err)
+		printMessage(message, err)
+		return false
+	}
+	if err := getObjectVerify(getRes, object.Body, http.StatusOK, nil); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// A HEAD response has no body either way, its Content-Length describes the object and must be 0 too.
+	headReq, err := newHeadObjectReq(bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	headRes, err := config.execRequest("HEAD", headReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(headRes)
+	if err := headObjectVerify(headRes, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyEmptyContentLength(headRes); err != nil {
+		err = fmt.Errorf("HEAD: %v", err)
+		printMessage(message, err)
+		return false
+	}
+	// Remove the object so it does not interfere with future tests.
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
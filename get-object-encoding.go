@@ -0,0 +1,158 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// The size of the repetitive object the content coding test uploads, large enough for gzip to be worth it.
+const encodingObjectSize = 64 * 1024
+
+// newGetObjectEncodingReq - Create a new HTTP request for a GET object accepting only the given content coding.
+func newGetObjectEncodingReq(bucketName, objectName, acceptEncoding string) (Request, error) {
+	getObjectEncodingReq, err := newGetObjectReq(bucketName, objectName, nil)
+	if err != nil {
+		return Request{}, err
+	}
+	// The http client never asks for or decompresses gzip on its own, so the response is seen exactly
+	// as the server sent it.
+	getObjectEncodingReq.customHeader.Set("Accept-Encoding", acceptEncoding)
+
+	return getObjectEncodingReq, nil
+}
+
+// getObjectGzipVerify - Verify a GET sent with Accept-Encoding: gzip returned expectedBody, either as is or
+// gzip compressed, with Content-Length counting the bytes sent and the ETag of the stored object.
+// It reports whether the server compressed the response.
+func getObjectGzipVerify(res *http.Response, expectedBody []byte, expectedETag string) (bool, error) {
+	if err := verifyStatusGetObject(res.StatusCode, http.StatusOK); err != nil {
+		return false, err
+	}
+	if err := verifyStandardHeaders(res.Header); err != nil {
+		return false, err
+	}
+	encoding := res.Header.Get("Content-Encoding")
+	if encoding != "" && encoding != "identity" && encoding != "gzip" {
+		err := fmt.Errorf("Unexpected Content-Encoding Received: wanted gzip or none, got %v", encoding)
+		return false, err
+	}
+	// The ETag is that of the stored object whatever coding the transfer used.
+	if eTag := strings.Trim(res.Header.Get("ETag"), "\""); eTag != expectedETag {
+		err := ChecksumMismatchError{Checksum: "ETag", Expected: expectedETag, Got: eTag}
+		return false, err
+	}
+	received, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+	if contentLength := res.Header.Get("Content-Length"); contentLength != "" && contentLength != strconv.Itoa(len(received)) {
+		err := HeaderMismatchError{Header: "Content-Length", Expected: strconv.Itoa(len(received)), Got: contentLength, Detail: "must count the bytes sent"}
+		return false, err
+	}
+	compressed := encoding == "gzip"
+	if compressed {
+		reader, err := gzip.NewReader(bytes.NewReader(received))
+		if err != nil {
+			return false, err
+		}
+		if received, err = ioutil.ReadAll(reader); err != nil {
+			return false, err
+		}
+	}
+	return compressed, verifyBodyEqual(expectedBody, received)
+}
+
+// mainGetObjectEncoding - Test a highly compressible object reads back intact with Accept-Encoding: identity and gzip.
+func mainGetObjectEncoding(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] GetObject (Accept-Encoding):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/get/encoding",
+		Body: bytes.Repeat([]byte("s3verify"), encodingObjectSize/len("s3verify")),
+	}
+	if _, err := putObject(config, bucketName, object); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	md5Sum, _, _, err := computeHash(bytes.NewReader(object.Body))
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	identityReq, err := newGetObjectEncodingReq(bucketName, object.Key, "identity")
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	identityRes, err := config.execRequest("GET", identityReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(identityRes)
+	if err := getObjectIdentityVerify(identityRes, object.Body); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if eTag := strings.Trim(identityRes.Header.Get("ETag"), "\""); eTag != hex.EncodeToString(md5Sum) {
+		err := ChecksumMismatchError{Checksum: "ETag", Expected: hex.EncodeToString(md5Sum), Got: eTag}
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	gzipReq, err := newGetObjectEncodingReq(bucketName, object.Key, "gzip")
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	gzipRes, err := config.execRequest("GET", gzipReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(gzipRes)
+	compressed, err := getObjectGzipVerify(gzipRes, object.Body, hex.EncodeToString(md5Sum))
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	if compressed {
+		printDetail("Accept-Encoding: gzip was answered gzip compressed")
+	} else {
+		printDetail("Accept-Encoding: gzip was answered uncompressed")
+	}
+	return true
+}
@@ -90,9 +90,10 @@ func verifyBodyGetObjectIfMatch(resBody io.Reader, objectBody []byte, shouldFail
 		if err != nil {
 			return err
 		}
-		if !shouldFail && !bytes.Equal(body, objectBody) { // Test should pass ensure body is what was uploaded.
-			err := fmt.Errorf("Unexpected Body Recieved: wanted %v, got %v", string(objectBody), string(body))
-			return err
+		if !shouldFail { // Test should pass ensure body is what was uploaded.
+			if err := verifyBodyEqual(objectBody, body); err != nil {
+				return err
+			}
 		}
 	}
 	// Otherwise test failed / passed as expected.
@@ -116,7 +117,7 @@ func mainGetObjectIfMatch(config ServerConfig, curTest int) bool {
 	// All getobject tests happen in s3verify created buckets
 	// on s3verify created objects.
 	bucketName := s3verifyBuckets[0].Name
-	for _, object := range s3verifyObjects {
+	for _, object := range s3verifyObjects.snapshot() {
 		// Spin scanBar
 		scanBar(message)
 		// Create new GET object If-Match request.
@@ -135,9 +136,10 @@ func mainGetObjectIfMatch(config ServerConfig, curTest int) bool {
 		}
 		// Spin scanBar
 		scanBar(message)
-		defer closeResponse(res)
 		// Verify the response...these checks do not check the headers yet.
-		if err := getObjectIfMatchVerify(res, object.Body, http.StatusOK, false); err != nil {
+		err = getObjectIfMatchVerify(res, object.Body, http.StatusOK, false)
+		drainAndClose(res)
+		if err != nil {
 			printMessage(message, err)
 			return false
 		}
@@ -159,9 +161,10 @@ func mainGetObjectIfMatch(config ServerConfig, curTest int) bool {
 		}
 		// Spin scanBar
 		scanBar(message)
-		defer closeResponse(badRes)
 		// Verify the request fails as expected.
-		if err := getObjectIfMatchVerify(badRes, []byte(""), http.StatusPreconditionFailed, true); err != nil {
+		err = getObjectIfMatchVerify(badRes, []byte(""), http.StatusPreconditionFailed, true)
+		drainAndClose(badRes)
+		if err != nil {
 			printMessage(message, err)
 			return false
 		}
@@ -70,11 +70,7 @@ func verifyBodyGetObjectIfModifiedSince(resBody io.Reader, expectedBody []byte)
 	if err != nil {
 		return err
 	}
-	if !bytes.Equal(body, expectedBody) {
-		err := fmt.Errorf("Unexpected Body Received: wanted %v, got %v", string(expectedBody), string(body))
-		return err
-	}
-	return nil
+	return verifyBodyEqual(expectedBody, body)
 }
 
 // verifyStatusGetObjectIfModifiedSince - Verify that the response status matches what is expected.
@@ -108,7 +104,7 @@ func mainGetObjectIfModifiedSince(config ServerConfig, curTest int) bool {
 	// All getobject if-modified-since tests happen in s3verify created buckets
 	// on s3verify created objects.
 	bucketName := s3verifyBuckets[0].Name
-	for _, object := range s3verifyObjects {
+	for _, object := range s3verifyObjects.snapshot() {
 		// Spin scanBar
 		scanBar(message)
 		// Create new GET object request.
@@ -123,9 +119,10 @@ func mainGetObjectIfModifiedSince(config ServerConfig, curTest int) bool {
 			printMessage(message, err)
 			return false
 		}
-		defer closeResponse(res)
 		// Verify the response...these checks do not check the headers yet.
-		if err := verifyGetObjectIfModifiedSince(res, []byte(""), http.StatusNotModified); err != nil {
+		err = verifyGetObjectIfModifiedSince(res, []byte(""), http.StatusNotModified)
+		drainAndClose(res)
+		if err != nil {
 			printMessage(message, err)
 			return false
 		}
@@ -141,9 +138,10 @@ func mainGetObjectIfModifiedSince(config ServerConfig, curTest int) bool {
 			printMessage(message, err)
 			return false
 		}
-		defer closeResponse(goodRes)
 		// Verify that the past date gives back the data.
-		if err := verifyGetObjectIfModifiedSince(goodRes, object.Body, http.StatusOK); err != nil {
+		err = verifyGetObjectIfModifiedSince(goodRes, object.Body, http.StatusOK)
+		drainAndClose(goodRes)
+		if err != nil {
 			printMessage(message, err)
 			return false
 		}
@@ -87,11 +87,8 @@ func verifyBodyGetObjectIfNoneMatch(resBody io.Reader, expectedBody []byte) erro
 	if err != nil {
 		return err
 	}
-	if !bytes.Equal(body, expectedBody) { // If the request does not go through an empty body is received.
-		err := fmt.Errorf("Unexpected Body Recieved: wanted %v, got %v", string(expectedBody), string(body))
-		return err
-	}
-	return nil
+	// If the request does not go through an empty body is received.
+	return verifyBodyEqual(expectedBody, body)
 }
 
 // Test the compatibility of the GetObject API when using the If-None-Match header.
@@ -104,7 +101,7 @@ func mainGetObjectIfNoneMatch(config ServerConfig, curTest int) bool {
 	// All getobject if-none-match tests are run in s3verify created buckets
 	// on s3verify created objects.
 	bucketName := s3verifyBuckets[0].Name
-	for _, object := range s3verifyObjects {
+	for _, object := range s3verifyObjects.snapshot() {
 		// Spin scanBar
 		scanBar(message)
 		// Create new GET object If-None-Match request.
@@ -119,9 +116,10 @@ func mainGetObjectIfNoneMatch(config ServerConfig, curTest int) bool {
 			printMessage(message, err)
 			return false
 		}
-		defer closeResponse(res)
 		// Verify the response...these checks do not check the headers yet.
-		if err := getObjectIfNoneMatchVerify(res, []byte(""), http.StatusNotModified); err != nil {
+		err = getObjectIfNoneMatchVerify(res, []byte(""), http.StatusNotModified)
+		drainAndClose(res)
+		if err != nil {
 			printMessage(message, err)
 			return false
 		}
@@ -137,9 +135,10 @@ func mainGetObjectIfNoneMatch(config ServerConfig, curTest int) bool {
 			printMessage(message, err)
 			return false
 		}
-		defer closeResponse(badRes)
 		// Verify the response returns the object since ETag != invalidETag
-		if err := getObjectIfNoneMatchVerify(badRes, object.Body, http.StatusOK); err != nil {
+		err = getObjectIfNoneMatchVerify(badRes, object.Body, http.StatusOK)
+		drainAndClose(badRes)
+		if err != nil {
 			printMessage(message, err)
 			return false
 		}
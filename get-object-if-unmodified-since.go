@@ -84,8 +84,7 @@ func verifyBodyGetObjectIfUnModifiedSince(resBody io.Reader, expectedBody []byte
 		if err != nil {
 			return err
 		}
-		if !bytes.Equal(body, expectedBody) {
-			err := fmt.Errorf("Unexpected Body Received: wanted %v, got %v", string(expectedBody), string(body))
+		if err := verifyBodyEqual(expectedBody, body); err != nil {
 			return err
 		}
 	}
@@ -124,7 +123,7 @@ func mainGetObjectIfUnModifiedSince(config ServerConfig, curTest int) bool {
 	// All getobject if-unmodified-since tests run in s3verify created buckets
 	// on s3verify created objects.
 	bucketName := s3verifyBuckets[0].Name
-	for _, object := range s3verifyObjects {
+	for _, object := range s3verifyObjects.snapshot() {
 		// Spin scanBar
 		scanBar(message)
 		// Form a request with a pastDate to make sure the object is not returned.
@@ -139,9 +138,10 @@ func mainGetObjectIfUnModifiedSince(config ServerConfig, curTest int) bool {
 			printMessage(message, err)
 			return false
 		}
-		defer closeResponse(res)
 		// Verify that the response returns an error.
-		if err := verifyGetObjectIfUnModifiedSince(res, []byte(""), http.StatusPreconditionFailed, true); err != nil {
+		err = verifyGetObjectIfUnModifiedSince(res, []byte(""), http.StatusPreconditionFailed, true)
+		drainAndClose(res)
+		if err != nil {
 			printMessage(message, err)
 			return false
 		}
@@ -157,9 +157,10 @@ func mainGetObjectIfUnModifiedSince(config ServerConfig, curTest int) bool {
 			printMessage(message, err)
 			return false
 		}
-		defer closeResponse(goodRes)
 		// Verify that the lastModified date in a request returns the object.
-		if err := verifyGetObjectIfUnModifiedSince(goodRes, object.Body, http.StatusOK, false); err != nil {
+		err = verifyGetObjectIfUnModifiedSince(goodRes, object.Body, http.StatusOK, false)
+		drainAndClose(goodRes)
+		if err != nil {
 			printMessage(message, err)
 			return false
 		}
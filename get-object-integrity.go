@@ -0,0 +1,91 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// The number of uploaded objects read back by the integrity test.
+const integritySampleSize = 10
+
+// verifyObjectIntegrity - verify a GET of object returns exactly its body with the ETag verifyObjectETag expects,
+// and that a HEAD reports the same ETag and Content-Length without returning a body.
+func verifyObjectIntegrity(config ServerConfig, bucketName string, object *ObjectInfo) error {
+	expectedLength := strconv.Itoa(len(object.Body))
+
+	getReq, err := newGetObjectReq(bucketName, object.Key, nil)
+	if err != nil {
+		return err
+	}
+	getRes, err := config.execRequest("GET", getReq)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(getRes)
+	if err := getObjectVerify(getRes, object.Body, http.StatusOK, nil); err != nil {
+		return err
+	}
+	if err := verifyObjectETag(object, getRes.Header.Get("ETag")); err != nil {
+		return err
+	}
+	if contentLength := getRes.Header.Get("Content-Length"); contentLength != expectedLength {
+		err := HeaderMismatchError{Header: "Content-Length", Expected: expectedLength, Got: contentLength}
+		return err
+	}
+
+	// The HEAD itself is verified to return no body.
+	headHeader, err := headObject(config, bucketName, object.Key)
+	if err != nil {
+		return err
+	}
+	for _, name := range []string{"ETag", "Content-Length"} {
+		if got := headHeader.Get(name); got != getRes.Header.Get(name) {
+			err := HeaderMismatchError{Header: name, Expected: getRes.Header.Get(name), Got: got, Detail: "HEAD must report what GET did"}
+			return err
+		}
+	}
+	return nil
+}
+
+// mainGetObjectIntegrity - Test a sample of the uploaded objects reads back byte for byte with the ETag of its body.
+func mainGetObjectIntegrity(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] GetObject (Integrity):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	objects := s3verifyObjects.snapshot()
+	sampled := 0
+	for _, i := range globalRandom.Perm(len(objects)) {
+		if sampled == integritySampleSize {
+			break
+		}
+		// Spin scanBar
+		scanBar(message)
+		if err := verifyObjectIntegrity(config, bucketName, objects[i]); err != nil {
+			err = fmt.Errorf("%s: %v", objects[i].Key, err)
+			printMessage(message, err)
+			return false
+		}
+		sampled++
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,178 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	crand "crypto/rand"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+)
+
+// multiRanges - the ranges sent together in a single Range header.
+var multiRanges = []rangeForm{
+	rangeForm{name: "first", value: "0-10", start: 0, end: 10},
+	rangeForm{name: "second", value: "20-30", start: 20, end: 30},
+}
+
+// multiRangeHeader - the Range header asking for all of multiRanges.
+func multiRangeHeader() string {
+	value := "bytes="
+	for i, r := range multiRanges {
+		if i > 0 {
+			value += ","
+		}
+		value += r.value
+	}
+	return value
+}
+
+// verifyBodyMultiRange - verify a multipart/byteranges body holds exactly multiRanges of body, in order.
+func verifyBodyMultiRange(res *http.Response, body []byte) error {
+	_, params, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if err != nil {
+		return err
+	}
+	reader := multipart.NewReader(res.Body, params["boundary"])
+	for _, r := range multiRanges {
+		part, err := reader.NextPart()
+		if err != nil {
+			err = fmt.Errorf("Missing Byte Range: %v range %v not returned: %v", r.name, r.value, err)
+			return err
+		}
+		expectedContentRange := fmt.Sprintf("bytes %d-%d/%d", r.start, r.end, len(body))
+		if contentRange := part.Header.Get("Content-Range"); contentRange != expectedContentRange {
+			err := HeaderMismatchError{Header: "Content-Range", Expected: expectedContentRange, Got: contentRange, Detail: r.name + " part"}
+			return err
+		}
+		received, err := ioutil.ReadAll(part)
+		if err != nil {
+			return err
+		}
+		if err := verifyBodyEqualAt(body[r.start:r.end+1], received, r.start); err != nil {
+			return err
+		}
+	}
+	if _, err := reader.NextPart(); err != io.EOF {
+		err := fmt.Errorf("Unexpected Byte Range: wanted %d parts only", len(multiRanges))
+		return err
+	}
+	return nil
+}
+
+// getObjectMultiRangeVerify - verify a GET with several ranges was answered in one of the ways S3 clients can
+// rely on, and describe which: ignored (200 with the whole object), served (206 multipart/byteranges) or
+// rejected (416 InvalidRange).
+func getObjectMultiRangeVerify(res *http.Response, body []byte) (string, error) {
+	if err := verifyStandardHeaders(res.Header); err != nil {
+		return "", err
+	}
+	switch res.StatusCode {
+	case http.StatusOK:
+		if contentLength := res.Header.Get("Content-Length"); contentLength != strconv.Itoa(len(body)) {
+			err := HeaderMismatchError{Header: "Content-Length", Expected: strconv.Itoa(len(body)), Got: contentLength, Detail: "the Range header was ignored"}
+			return "", err
+		}
+		received, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return "", err
+		}
+		if err := verifyBodyEqual(body, received); err != nil {
+			return "", err
+		}
+		return "Multiple ranges are ignored, the whole object is returned", nil
+	case http.StatusPartialContent:
+		mediaType, _, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+		if err != nil {
+			return "", err
+		}
+		if mediaType != "multipart/byteranges" {
+			err := HeaderMismatchError{Header: "Content-Type", Expected: "multipart/byteranges", Got: mediaType, Detail: "206 for multiple ranges"}
+			return "", err
+		}
+		if err := verifyBodyMultiRange(res, body); err != nil {
+			return "", err
+		}
+		return "Multiple ranges are returned as multipart/byteranges", nil
+	case http.StatusRequestedRangeNotSatisfiable:
+		errResponse := ErrorResponse{}
+		if err := xmlDecoder(res.Body, &errResponse); err != nil {
+			return "", err
+		}
+		if errResponse.Code != "InvalidRange" {
+			err := fmt.Errorf("Unexpected Error Response: wanted InvalidRange, got %v", errResponse.Code)
+			return "", err
+		}
+		return "Multiple ranges are rejected with InvalidRange", nil
+	}
+	err := fmt.Errorf("Unexpected Response Status Code: wanted 200, 206 or 416 for multiple ranges, got %v", res.StatusCode)
+	return "", err
+}
+
+// mainGetObjectMultiRange - Test a GET object request asking for more than one range, which S3 does not support,
+// and report how the server handles it.
+func mainGetObjectMultiRange(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] GetObject (Multiple Ranges):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/get/multi-range",
+		Body: make([]byte, 1024),
+	}
+	if _, err := io.ReadFull(crand.Reader, object.Body); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if _, err := putObject(config, bucketName, object); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	req, err := newGetObjectRangeHeaderReq(bucketName, object.Key, multiRangeHeader())
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	behavior, err := getObjectMultiRangeVerify(res, object.Body)
+	if err != nil {
+		err = fmt.Errorf("Range %v: %v", multiRangeHeader(), err)
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	printDetail(behavior)
+	return true
+}
@@ -0,0 +1,170 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	crand "crypto/rand"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+)
+
+// Size of the object the range forms are requested from.
+const rangeFormsObjectSize = 4 * 1024
+
+// rangeForm - a Range header and the bytes of the object it selects.
+type rangeForm struct {
+	name  string
+	value string
+	start int64 // Offset of the first byte returned.
+	end   int64 // Offset of the last byte returned.
+}
+
+// rangeForms - the forms of a single byte range a client may send.
+var rangeForms = []rangeForm{
+	rangeForm{name: "prefix", value: "bytes=0-99", start: 0, end: 99},
+	rangeForm{name: "middle", value: "bytes=1000-2047", start: 1000, end: 2047},
+	rangeForm{name: "open-ended", value: "bytes=100-", start: 100, end: rangeFormsObjectSize - 1},
+	rangeForm{name: "suffix", value: "bytes=-50", start: rangeFormsObjectSize - 50, end: rangeFormsObjectSize - 1},
+	rangeForm{name: "past the end", value: "bytes=4000-9999", start: 4000, end: rangeFormsObjectSize - 1},
+}
+
+// verifyHeaderGetObjectRange - verify Content-Range and Content-Length describe the bytes start to end
+// of an object of objectSize bytes.
+func verifyHeaderGetObjectRange(header http.Header, start, end, objectSize int64) error {
+	if err := verifyStandardHeaders(header); err != nil {
+		return err
+	}
+	expectedContentRange := fmt.Sprintf("bytes %d-%d/%d", start, end, objectSize)
+	if contentRange := header.Get("Content-Range"); contentRange != expectedContentRange {
+		err := fmt.Errorf("Unexpected Content-Range Received: wanted %v, got %v", expectedContentRange, contentRange)
+		return err
+	}
+	expectedContentLength := strconv.FormatInt(end-start+1, 10)
+	if contentLength := header.Get("Content-Length"); contentLength != expectedContentLength {
+		err := fmt.Errorf("Unexpected Content-Length Received: wanted %v, got %v", expectedContentLength, contentLength)
+		return err
+	}
+	return nil
+}
+
+// getObjectRangeFormVerify - verify a ranged GET returned exactly the selected slice of body.
+func getObjectRangeFormVerify(res *http.Response, form rangeForm, body []byte) error {
+	if err := verifyStatusGetObject(res.StatusCode, http.StatusPartialContent); err != nil {
+		return err
+	}
+	if err := verifyHeaderGetObjectRange(res.Header, form.start, form.end, int64(len(body))); err != nil {
+		return err
+	}
+	received, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	return verifyBodyEqualAt(body[form.start:form.end+1], received, form.start)
+}
+
+// getObjectRangeUnsatisfiableVerify - verify a range starting past the end of the object was rejected.
+func getObjectRangeUnsatisfiableVerify(res *http.Response) error {
+	if err := verifyStatusGetObject(res.StatusCode, http.StatusRequestedRangeNotSatisfiable); err != nil {
+		return err
+	}
+	if err := verifyStandardHeaders(res.Header); err != nil {
+		return err
+	}
+	errResponse := ErrorResponse{}
+	if err := xmlDecoder(res.Body, &errResponse); err != nil {
+		return err
+	}
+	if errResponse.Code != "InvalidRange" {
+		err := fmt.Errorf("Unexpected Error Response: wanted InvalidRange, got %v", errResponse.Code)
+		return err
+	}
+	return nil
+}
+
+// mainGetObjectRangeForms - Test GET object with prefix, middle, open-ended and suffix ranges
+// on an object of known content, and a range that can not be satisfied.
+func mainGetObjectRangeForms(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] GetObject (Range Forms):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/get/range-forms",
+		Body: make([]byte, rangeFormsObjectSize),
+	}
+	if _, err := io.ReadFull(crand.Reader, object.Body); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if _, err := putObject(config, bucketName, object); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	for _, form := range rangeForms {
+		// Spin scanBar
+		scanBar(message)
+		req, err := newGetObjectRangeHeaderReq(bucketName, object.Key, form.value)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		res, err := config.execRequest("GET", req)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		err = getObjectRangeFormVerify(res, form, object.Body)
+		drainAndClose(res)
+		if err != nil {
+			err = fmt.Errorf("Range %v (%v): %v", form.value, form.name, err)
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Spin scanBar
+	scanBar(message)
+	// A range starting at or past the end of the object selects nothing.
+	unsatisfiable := "bytes=" + strconv.Itoa(rangeFormsObjectSize) + "-"
+	req, err := newGetObjectRangeHeaderReq(bucketName, object.Key, unsatisfiable)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if err := getObjectRangeUnsatisfiableVerify(res); err != nil {
+		err = fmt.Errorf("Range %v: %v", unsatisfiable, err)
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
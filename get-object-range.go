@@ -28,6 +28,12 @@ import (
 
 // newGetObjectRangeReq - Create a new GET object range request.
 func newGetObjectRangeReq(bucketName, objectName string, startRange, endRange int64) (Request, error) {
+	return newGetObjectRangeHeaderReq(bucketName, objectName, "bytes="+strconv.FormatInt(startRange, 10)+"-"+strconv.FormatInt(endRange, 10))
+}
+
+// newGetObjectRangeHeaderReq - Create a new GET object request with the Range header set as is,
+// which allows open-ended and suffix ranges.
+func newGetObjectRangeHeaderReq(bucketName, objectName, byteRange string) (Request, error) {
 	// getObjectRangeReq - a new HTTP request for a GET object with a specific range request.
 	var getObjectRangeReq = Request{
 		customHeader: http.Header{},
@@ -44,7 +50,7 @@ func newGetObjectRangeReq(bucketName, objectName string, startRange, endRange in
 	}
 
 	// Set the headers.
-	getObjectRangeReq.customHeader.Set("Range", "bytes="+strconv.FormatInt(startRange, 10)+"-"+strconv.FormatInt(endRange, 10))
+	getObjectRangeReq.customHeader.Set("Range", byteRange)
 	getObjectRangeReq.customHeader.Set("User-Agent", appUserAgent)
 	getObjectRangeReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
 	return getObjectRangeReq, nil
@@ -59,7 +65,7 @@ func mainGetObjectRange(config ServerConfig, curTest int) bool {
 	// All getobject tests happen in s3verify created buckets
 	// on s3verify created objects.
 	bucketName := s3verifyBuckets[0].Name
-	for _, object := range s3verifyObjects {
+	for _, object := range s3verifyObjects.snapshot() {
 		// Spin scanBar
 		scanBar(message)
 		startRange := rand.Int63n(object.Size)
@@ -76,10 +82,11 @@ func mainGetObjectRange(config ServerConfig, curTest int) bool {
 			printMessage(message, err)
 			return false
 		}
-		defer closeResponse(res)
 		bufRange := object.Body[startRange : endRange+1]
 		// Verify the response...these checks do not check the headers yet.
-		if err := getObjectVerify(res, bufRange, http.StatusPartialContent, nil); err != nil {
+		err = getObjectVerify(res, bufRange, http.StatusPartialContent, nil)
+		drainAndClose(res)
+		if err != nil {
 			printMessage(message, err)
 			return false
 		}
@@ -24,6 +24,8 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strings"
 )
 
 // overWrittenHeaers - map the request headers that can be sent
@@ -70,6 +72,18 @@ func newGetObjectReq(bucketName, objectName string, responseHeaders map[string]s
 	return getObjectReq, nil
 }
 
+// newGetObjectVersionReq - Create a new HTTP request for a GET of a specific object version.
+func newGetObjectVersionReq(bucketName, objectName, versionID string) (Request, error) {
+	getObjectVersionReq, err := newGetObjectReq(bucketName, objectName, nil)
+	if err != nil {
+		return Request{}, err
+	}
+	// Set the versionId to be retrieved.
+	getObjectVersionReq.queryValues.Set("versionId", versionID)
+
+	return getObjectVersionReq, nil
+}
+
 // TODO: These checks only verify correctly formatted requests. There is no request that is made to fail / check failure yet.
 
 // getObjectVerify - Check a Response's Status, Headers, and Body for AWS S3 compliance.
@@ -108,11 +122,7 @@ func verifyBodyGetObject(resBody io.Reader, expectedBody []byte) error {
 		return err
 	}
 	// Compare what was created to be uploaded and what is contained in the response body.
-	if !bytes.Equal(body, expectedBody) {
-		err := fmt.Errorf("Unexpected Body Recieved: wanted %v, got %v", string(expectedBody), string(body))
-		return err
-	}
-	return nil
+	return verifyBodyEqual(expectedBody, body)
 }
 
 // verifyStatusGetObject - Verify that the status returned matches what is expected.
@@ -124,6 +134,31 @@ func verifyStatusGetObject(respStatusCode, expectedStatusCode int) error {
 	return nil
 }
 
+// varyFieldName - a header field name as allowed in a Vary header (RFC 7230 token).
+var varyFieldName = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
+// verifyVaryHeader - verify a Vary header, if returned, lets caches store the response.
+// Returns whether one was returned, minimal servers that leave it out are not failed.
+func verifyVaryHeader(header http.Header) (bool, error) {
+	vary := strings.Join(header["Vary"], ",")
+	if vary == "" {
+		return false, nil
+	}
+	for _, name := range strings.Split(vary, ",") {
+		name = strings.TrimSpace(name)
+		// A Vary of * means every request is unique and nothing can be served from a cache.
+		if name == "*" {
+			err := HeaderMismatchError{Header: "Vary", Expected: "header field names", Got: vary, Detail: "* prevents caching any variant"}
+			return true, err
+		}
+		if !varyFieldName.MatchString(name) {
+			err := HeaderMismatchError{Header: "Vary", Expected: "header field names", Got: vary, Detail: fmt.Sprintf("%q is not a field name", name)}
+			return true, err
+		}
+	}
+	return true, nil
+}
+
 // mainGetObject - test a get object request.
 func mainGetObject(config ServerConfig, curTest int) bool {
 	message := fmt.Sprintf("[%02d/%d] GetObject:", curTest, globalTotalNumTest)
@@ -140,7 +175,8 @@ func mainGetObject(config ServerConfig, curTest int) bool {
 	// All getobject tests happen in s3verify created buckets
 	// on s3verify objects.
 	bucketName := s3verifyBuckets[0].Name
-	for _, object := range s3verifyObjects {
+	varyReturned := true
+	for _, object := range s3verifyObjects.snapshot() {
 		// Spin scanBar
 		scanBar(message)
 		// Create new GET object request.
@@ -155,12 +191,20 @@ func mainGetObject(config ServerConfig, curTest int) bool {
 			printMessage(message, err)
 			return false
 		}
-		defer closeResponse(res)
 		// Verify the response.
-		if err := getObjectVerify(res, object.Body, http.StatusOK, expectedHeaders); err != nil {
+		err = getObjectVerify(res, object.Body, http.StatusOK, expectedHeaders)
+		drainAndClose(res)
+		if err != nil {
 			printMessage(message, err)
 			return false
 		}
+		// Caches must be able to tell the overridden variants apart.
+		returned, err := verifyVaryHeader(res.Header)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		varyReturned = varyReturned && returned
 		// Spin scanBar
 		scanBar(message)
 	}
@@ -168,5 +212,8 @@ func mainGetObject(config ServerConfig, curTest int) bool {
 	scanBar(message)
 	// Test passed.
 	printMessage(message, nil)
+	if !varyReturned {
+		printDetail("Warning: no Vary header returned with response header overrides")
+	}
 	return true
 }
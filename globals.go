@@ -26,11 +26,28 @@ import (
 )
 
 var (
-	globalVerbose       bool          // Used to decide whether or not http traces will be printed.
+	globalVerbosity     int           // How much is printed, from only the summary to http traces.
 	globalDefaultRegion = "us-east-1" // Default all aws requests to us-east-1 unless told otherwise.
 	globalTotalNumTest  int           // The total number of tests being run.
 	globalRandom        *rand.Rand    // A global random seed used by retry code.
 	globalSuffix        string        // The suffix to append to all s3verify created objects and buckets.
+	globalHashStrategy  hashStrategy  // The digests calculated over uploaded bodies.
+	globalListRetries   int           // The number of times a listing is retried before it must reflect recent changes.
+	globalUploadBudget  *uploadBudget // The bytes uploaded so far and the limit on them.
+	globalStrict        bool          // Whether checks of behavior clients should not depend on are run.
+	globalForeignBucket string        // An existing bucket owned by another account.
+	globalKMSKeyID      string        // The KMS key SSE-KMS objects are encrypted with, the server default if empty.
+	globalReadOnly      bool          // Whether only tests that do not write to the server may run.
+	globalRateLimiter   *rateLimiter  // Limits the number of requests sent per second.
+	globalCustomHeaders *extraHeaders // Headers given on the command line to add to every request.
+	globalMaxPartSize   int64         // The largest part size the server accepts.
+	globalReporter      reporter      // Presents the result of every test.
+	globalCleanup       bool          // Whether everything a run created is removed once its tests have run.
+	globalManifest      *manifest     // Records every bucket and object the run creates.
+	globalDateSkew      *dateSkew     // The largest difference between a response Date and the local clock.
+	globalTestFilter    *testFilter   // Selects the tests to run by name.
+	globalShowDurations bool          // Whether the time every test took is printed with its result.
+	globalMetrics       *runMetrics   // Counts and times every request for --metrics-out.
 )
 
 // lockedRandSource provides protected rand source, implements rand.Source interface.
@@ -57,38 +74,104 @@ func (r *lockedRandSource) Seed(seed int64) {
 }
 
 // Separate out context.
-func setGlobals(verbose bool, numTests int, suffix string) {
+func setGlobals(verbosity int, numTests int, suffix string) {
 	globalTotalNumTest = numTests
-	globalVerbose = verbose
-	if globalVerbose {
+	globalVerbosity = verbosity
+	if globalVerbosity >= verbosityDebug {
 		// Allow printing of traces.
 		console.DebugPrint = true
 	}
 	globalRandom = rand.New(&lockedRandSource{src: rand.NewSource(time.Now().UTC().UnixNano())})
 	globalSuffix = suffix
+	// Results are reported for a person unless another format is asked for.
+	globalReporter = &humanReporter{}
+	globalDateSkew = &dateSkew{}
 }
 
 // Set any global flags here.
 func setGlobalsFromContext(ctx *cli.Context) error {
-	verbose := ctx.Bool("verbose") || ctx.GlobalBool("verbose")
+	verbosity, err := verbosityFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	// Strict checks are only run when asked for.
+	globalStrict = ctx.GlobalBool("strict")
+	// Production buckets must never be written to.
+	globalReadOnly = ctx.GlobalString("read-only") != ""
+	// Only print how long every test took if asked for.
+	globalShowDurations = ctx.GlobalBool("durations")
+	// Buckets and objects are left behind unless asked otherwise.
+	globalCleanup = ctx.GlobalBool("cleanup")
+	// Only run the tests asked for, all of them if no filter was given.
+	filter, err := newTestFilter(ctx.GlobalString("run"), ctx.GlobalString("skip"))
+	if err != nil {
+		return err
+	}
+	globalTestFilter = filter
+	// The length of unpreparedTests == preparedTests.
+	tests := unpreparedTests
+	if globalReadOnly {
+		tests = readOnlyTests
+	}
 	numTests := 0
 	// Calculate the total number of tests being run.
 	if ctx.Bool("extended") || ctx.GlobalBool("extended") {
-		numTests = len(unpreparedTests)
+		numTests = len(tests)
 	} else {
-		// The length of unpreparedTests == preparedTests.
-		for _, test := range unpreparedTests {
+		for _, test := range tests {
 			if !test.Extended {
 				numTests++
 			}
 		}
 	}
+	for _, test := range tests {
+		if (test.Strict && !globalStrict) || (test.Mutating && globalReadOnly) || !globalTestFilter.selects(test) {
+			if !test.Extended || ctx.Bool("extended") || ctx.GlobalBool("extended") {
+				numTests--
+			}
+		}
+	}
 	// Standard suffix.
 	suffix := "tmp-bucket"
 	if ctx.GlobalString("id") != "" {
 		suffix = ctx.GlobalString("id")
 	}
-	setGlobals(verbose, numTests, suffix)
+	setGlobals(verbosity, numTests, suffix)
+	// Decide which digests uploads need.
+	hashStrategy, err := newHashStrategy(ctx.GlobalBool("disable-md5"), ctx.GlobalBool("unsigned-payload"))
+	if err != nil {
+		return err
+	}
+	globalHashStrategy = hashStrategy
+	// Allow eventually consistent listings to catch up.
+	globalListRetries = ctx.GlobalInt("list-retries")
+	// Limit the data uploaded when testing against paid services.
+	globalUploadBudget = newUploadBudget(int64(ctx.GlobalInt("max-upload-bytes")))
+	// Limit the request rate for servers that throttle.
+	globalRateLimiter = newRateLimiter(ctx.GlobalInt("max-rps"))
+	// Test servers may cap parts below 5GiB.
+	globalMaxPartSize = ctx.GlobalInt64("max-part-size")
+	// Cross account checks need a bucket the user does not own.
+	globalForeignBucket = ctx.GlobalString("foreign-bucket")
+	// SSE-KMS uploads use the server's default key unless one is given.
+	globalKMSKeyID = ctx.GlobalString("kms-key-id")
+	// Gateways may need extra headers on every request.
+	customHeaders, err := newExtraHeaders(ctx.GlobalStringSlice("signed-header"), ctx.GlobalStringSlice("header"))
+	if err != nil {
+		return err
+	}
+	globalCustomHeaders = customHeaders
+	// Record what the run creates, unless the manifest is the input of a cleanup.
+	manifestPath := ctx.GlobalString("manifest")
+	if ctx.GlobalBool("cleanup-only") {
+		manifestPath = ""
+	}
+	globalManifest, err = newManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+	// Only count requests if the metrics are written out.
+	globalMetrics = newRunMetrics(ctx.GlobalString("metrics-out"))
 
 	return nil
 }
@@ -0,0 +1,193 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/minio/mc/pkg/console"
+)
+
+// harFile - the subset of a HTTP Archive (HAR 1.2) file needed to replay its requests.
+type harFile struct {
+	Log struct {
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+// harEntry - a single captured request.
+type harEntry struct {
+	Request harRequest `json:"request"`
+}
+
+// harRequest - the request half of a harEntry.
+type harRequest struct {
+	Method   string      `json:"method"`
+	URL      string      `json:"url"`
+	Headers  []harHeader `json:"headers"`
+	PostData *struct {
+		Text string `json:"text"`
+	} `json:"postData,omitempty"`
+}
+
+// harHeader - a single captured header.
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Headers that were part of the original signature or framing. They are recalculated on replay.
+var replayDroppedHeaders = map[string]bool{
+	"Authorization":        true,
+	"Content-Length":       true,
+	"Host":                 true,
+	"Transfer-Encoding":    true,
+	"X-Amz-Content-Sha256": true,
+	"X-Amz-Date":           true,
+	"X-Amz-Security-Token": true,
+}
+
+// readHARFile - read and decode the HAR file at path.
+func readHARFile(path string) (harFile, error) {
+	har := harFile{}
+	f, err := os.Open(path)
+	if err != nil {
+		return har, err
+	}
+	defer f.Close()
+	if err := json.NewDecoder(f).Decode(&har); err != nil {
+		err = fmt.Errorf("Invalid HAR File %s: %v", path, err)
+		return har, err
+	}
+	return har, nil
+}
+
+// newReplayReq - turn a captured request into a Request for the configured server. Requests are
+// assumed to be path style, as sent by s3verify, and are signed again when executed since the
+// captured signature and date will have expired.
+func newReplayReq(captured harRequest) (Request, error) {
+	var replayReq = Request{
+		customHeader: http.Header{},
+	}
+	capturedURL, err := url.Parse(captured.URL)
+	if err != nil {
+		return Request{}, err
+	}
+	// Split the path into the bucket and object name.
+	path := strings.TrimPrefix(capturedURL.Path, "/")
+	if i := strings.Index(path, "/"); i >= 0 {
+		replayReq.bucketName = path[:i]
+		replayReq.objectName = path[i+1:]
+	} else {
+		replayReq.bucketName = path
+	}
+	// Drop any presigned query parameters, keep everything else.
+	urlValues := make(url.Values)
+	for k, v := range capturedURL.Query() {
+		if strings.HasPrefix(k, "X-Amz-") {
+			continue
+		}
+		urlValues[k] = v
+	}
+	replayReq.queryValues = urlValues
+	for _, header := range captured.Headers {
+		name := http.CanonicalHeaderKey(header.Name)
+		if replayDroppedHeaders[name] {
+			continue
+		}
+		replayReq.customHeader.Set(name, header.Value)
+	}
+	body := []byte{}
+	if captured.PostData != nil {
+		body = []byte(captured.PostData.Text)
+	}
+	reader := bytes.NewReader(body)
+	_, sha256Sum, contentLength, err := computeHash(reader)
+	if err != nil {
+		return Request{}, err
+	}
+	replayReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
+	replayReq.customHeader.Set("User-Agent", appUserAgent)
+	if contentLength > 0 {
+		replayReq.contentBody = reader
+		replayReq.contentLength = contentLength
+	}
+	return replayReq, nil
+}
+
+// verifyReplaySigned - a signature error means the request was not signed again correctly,
+// any other response is reported as the server's answer to the replayed request.
+func verifyReplaySigned(res *http.Response) error {
+	if res.StatusCode != http.StatusForbidden {
+		return nil
+	}
+	errResponse := ErrorResponse{}
+	if err := xmlDecoder(res.Body, &errResponse); err != nil {
+		return nil
+	}
+	if errResponse.Code == "SignatureDoesNotMatch" || errResponse.Code == "RequestTimeTooSkewed" {
+		err := fmt.Errorf("Replayed Request Not Signed Correctly: %v: %v", errResponse.Code, errResponse.Message)
+		return err
+	}
+	return nil
+}
+
+// mainReplayHAR - re-issue every request captured in the HAR file at path against the configured server.
+// Returns false if the file could not be read or any request could not be sent or was not signed correctly.
+func mainReplayHAR(config ServerConfig, path string) bool {
+	har, err := readHARFile(path)
+	if err != nil {
+		console.Errorln(err)
+		return false
+	}
+	globalTotalNumTest = len(har.Log.Entries)
+	passed := true
+	for i, entry := range har.Log.Entries {
+		message := fmt.Sprintf("[%02d/%d] Replay (%s %s):", i+1, globalTotalNumTest, entry.Request.Method, entry.Request.URL)
+		// Spin scanBar
+		scanBar(message)
+		req, err := newReplayReq(entry.Request)
+		if err != nil {
+			printMessage(message, err)
+			passed = false
+			continue
+		}
+		res, err := config.execRequest(entry.Request.Method, req)
+		if err != nil {
+			printMessage(message, err)
+			passed = false
+			continue
+		}
+		err = verifyReplaySigned(res)
+		drainAndClose(res)
+		if err != nil {
+			printMessage(message, err)
+			passed = false
+			continue
+		}
+		printMessage(message, nil)
+		printDetail(res.Status)
+	}
+	return passed
+}
@@ -0,0 +1,115 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// A HAR file captured a year before it is replayed: a PUT signed with a header and a presigned GET.
+const staleHAR = `{"log": {"entries": [
+	{"request": {
+		"method": "PUT",
+		"url": "http://s3.example.com/s3verify-har/object?tagging",
+		"headers": [
+			{"name": "Authorization", "value": "AWS4-HMAC-SHA256 Credential=stale/20151002/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-date, Signature=0000"},
+			{"name": "X-Amz-Date", "value": "20151002T120000Z"},
+			{"name": "X-Amz-Content-Sha256", "value": "0000"},
+			{"name": "Content-Length", "value": "8"},
+			{"name": "Content-Type", "value": "application/xml"}
+		],
+		"postData": {"text": "s3verify"}
+	}},
+	{"request": {
+		"method": "GET",
+		"url": "http://s3.example.com/s3verify-har/object?versionId=1&X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Credential=stale%2F20151002%2Fus-east-1%2Fs3%2Faws4_request&X-Amz-Date=20151002T120000Z&X-Amz-Expires=60&X-Amz-SignedHeaders=host&X-Amz-Signature=0000",
+		"headers": []
+	}}
+]}}`
+
+// Test that replayed requests are signed again with the configured credentials, keeping their
+// query, headers and body but none of the captured signature.
+func TestReplayHARResigns(t *testing.T) {
+	var mutex sync.Mutex
+	var received []*http.Request
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mutex.Lock()
+		received = append(received, r)
+		bodies = append(bodies, string(body))
+		mutex.Unlock()
+		writeS3Headers(w)
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "s3verify-har")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	harPath := filepath.Join(dir, "capture.har")
+	if err := ioutil.WriteFile(harPath, []byte(staleHAR), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := newTestConfig(server.URL)
+	if !mainReplayHAR(config, harPath) {
+		t.Fatal("Expected the replay to pass")
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(received) != 2 {
+		t.Fatalf("Expected 2 replayed requests, got %d", len(received))
+	}
+	today := time.Now().UTC().Format("20060102")
+	for i, req := range received {
+		auth := req.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=s3verify-access/"+today+"/") {
+			t.Errorf("Request %d: Expected a fresh signature with the configured credentials, got %q", i+1, auth)
+		}
+		if date := req.Header.Get("X-Amz-Date"); !strings.HasPrefix(date, today) {
+			t.Errorf("Request %d: Expected a fresh X-Amz-Date, got %q", i+1, date)
+		}
+		for name := range req.URL.Query() {
+			if strings.HasPrefix(name, "X-Amz-") {
+				t.Errorf("Request %d: Expected the presigned %s query parameter to be dropped", i+1, name)
+			}
+		}
+	}
+	put, get := received[0], received[1]
+	if put.Method != "PUT" || put.URL.Path != "/s3verify-har/object" || put.URL.RawQuery != "tagging=" {
+		t.Errorf("Expected PUT /s3verify-har/object?tagging=, got %s %s", put.Method, put.URL.RequestURI())
+	}
+	if put.Header.Get("Content-Type") != "application/xml" || bodies[0] != "s3verify" {
+		t.Errorf("Expected the captured headers and body to be kept, got %q with %q", put.Header.Get("Content-Type"), bodies[0])
+	}
+	if sha := put.Header.Get("X-Amz-Content-Sha256"); sha == "0000" {
+		t.Error("Expected X-Amz-Content-Sha256 to be calculated again")
+	}
+	if get.Method != "GET" || get.URL.Query().Get("versionId") != "1" {
+		t.Errorf("Expected GET with versionId=1, got %s %s", get.Method, get.URL.RequestURI())
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// headBucketForeignVerify - verify a HEAD on a bucket owned by another account reveals only that
+// the bucket exists: 403 Forbidden, or 301 Moved Permanently if it lives in another region.
+func headBucketForeignVerify(res *http.Response) error {
+	if res.StatusCode == http.StatusMovedPermanently {
+		return headBucketVerify(res, http.StatusMovedPermanently)
+	}
+	if res.StatusCode == http.StatusNotFound {
+		err := fmt.Errorf("Unexpected Status Received: wanted %v or %v, got %v: the server denies a bucket owned by another account exists",
+			http.StatusForbidden, http.StatusMovedPermanently, res.StatusCode)
+		return err
+	}
+	return headBucketVerify(res, http.StatusForbidden)
+}
+
+// mainHeadBucketForeign - HeadBucket API test on a bucket owned by another account.
+func mainHeadBucketForeign(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] HeadBucket (Other Account):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	// Servers without multiple accounts have no such bucket to test with.
+	if globalForeignBucket == "" {
+		printMessage(message, nil)
+		printDetail("Skipped: use --foreign-bucket to name a bucket owned by another account")
+		return true
+	}
+	req, err := newHeadBucketReq(globalForeignBucket)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	res, err := config.execRequest("HEAD", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	// Spin scanBar
+	scanBar(message)
+	if err := headBucketForeignVerify(res); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -110,7 +110,7 @@ func mainHeadBucket(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(res)
+	defer drainAndClose(res)
 	// Verify the response.
 	if err := headBucketVerify(res, http.StatusOK); err != nil {
 		printMessage(message, err)
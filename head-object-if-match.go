@@ -103,7 +103,7 @@ func mainHeadObjectIfMatch(config ServerConfig, curTest int) bool {
 	// All headObject if-match tests are run in s3verify created buckets
 	// on s3verify created objects.
 	bucketName := s3verifyBuckets[0].Name
-	object := s3verifyObjects[0]
+	object := s3verifyObjects.snapshot()[0]
 	// Create a new valid request for HEAD object with if-match header set.
 	req, err := newHeadObjectIfMatchReq(bucketName, object.Key, object.ETag)
 	if err != nil {
@@ -118,7 +118,7 @@ func mainHeadObjectIfMatch(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(res)
+	defer drainAndClose(res)
 	// Spin scanBar
 	scanBar(message)
 	// Verify the response.
@@ -142,7 +142,7 @@ func mainHeadObjectIfMatch(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(badRes)
+	defer drainAndClose(badRes)
 	// Spin scanBar
 	scanBar(message)
 	// Verify the request sends back the right error.
@@ -105,7 +105,7 @@ func mainHeadObjectIfModifiedSince(config ServerConfig, curTest int) bool {
 	// All headobject if-modified-since tests happen in s3verify created buckets
 	// on s3verify created objects.
 	bucketName := s3verifyBuckets[0].Name
-	object := s3verifyObjects[0]
+	object := s3verifyObjects.snapshot()[0]
 	// Spin scanBar
 	scanBar(message)
 	// Create a new request.
@@ -122,7 +122,7 @@ func mainHeadObjectIfModifiedSince(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(res)
+	defer drainAndClose(res)
 	// Spin scanBar
 	scanBar(message)
 	// Verify the response.
@@ -146,7 +146,7 @@ func mainHeadObjectIfModifiedSince(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(badRes)
+	defer drainAndClose(badRes)
 	// Spin scanBar
 	scanBar(message)
 	// Verify the bad request failed as expected.
@@ -104,7 +104,7 @@ func mainHeadObjectIfNoneMatch(config ServerConfig, curTest int) bool {
 	// All headobject if-none-match tests happen in s3verify created buckets
 	// on s3verify created objects.
 	bucketName := s3verifyBuckets[0].Name
-	object := s3verifyObjects[0]
+	object := s3verifyObjects.snapshot()[0]
 	// Create a new request for a HEAD object with if-none-match header set.
 	req, err := newHeadObjectIfNoneMatchReq(bucketName, object.Key, validETag)
 	if err != nil {
@@ -119,7 +119,7 @@ func mainHeadObjectIfNoneMatch(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(res)
+	defer drainAndClose(res)
 	// Spin scanBar
 	scanBar(message)
 	// Verify the response.
@@ -143,7 +143,7 @@ func mainHeadObjectIfNoneMatch(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(badRes)
+	defer drainAndClose(badRes)
 	// Spin scanBar
 	scanBar(message)
 	// Verify the response.
@@ -107,7 +107,7 @@ func mainHeadObjectIfUnModifiedSince(config ServerConfig, curTest int) bool {
 	// All headobject if-unmodified-since tests happen in s3verify created buckets
 	// on s3verify created objects.
 	bucketName := s3verifyBuckets[0].Name
-	object := s3verifyObjects[0]
+	object := s3verifyObjects.snapshot()[0]
 	// Create a new request.
 	req, err := newHeadObjectIfUnModifiedSinceReq(bucketName, object.Key, object.LastModified)
 	if err != nil {
@@ -122,7 +122,7 @@ func mainHeadObjectIfUnModifiedSince(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(res)
+	defer drainAndClose(res)
 	// Spin scanBar
 	scanBar(message)
 	// Verify the request succeeds as expected.
@@ -146,7 +146,7 @@ func mainHeadObjectIfUnModifiedSince(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(badRes)
+	defer drainAndClose(badRes)
 	// Spin scanBar
 	scanBar(message)
 	// Verify the response failed.
@@ -0,0 +1,102 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// verifyHeaderTaggingCount - verify HEAD reports expected tags for objectName. An untagged object
+// may leave x-amz-tagging-count off or report 0.
+func verifyHeaderTaggingCount(config ServerConfig, bucketName, objectName string, expected int) error {
+	header, err := headObject(config, bucketName, objectName)
+	if err != nil {
+		return err
+	}
+	count := header.Get("x-amz-tagging-count")
+	if count == "" && expected == 0 {
+		return nil
+	}
+	if count != strconv.Itoa(expected) {
+		err := HeaderMismatchError{Header: "x-amz-tagging-count", Expected: strconv.Itoa(expected), Got: count}
+		return err
+	}
+	return nil
+}
+
+// mainHeadObjectTaggingCount - Test HEAD reports the number of tags of a tagged object in x-amz-tagging-count
+// and none for an untagged object.
+func mainHeadObjectTaggingCount(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] HeadObject (Tagging Count):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	seed := time.Now().UnixNano()
+	taggedObject := &ObjectInfo{
+		Key:  "s3verify/tagging/count/tagged",
+		Body: generatedBody(seed, 60),
+	}
+	untaggedObject := &ObjectInfo{
+		Key:  "s3verify/tagging/count/untagged",
+		Body: generatedBody(seed+1, 60),
+	}
+	tags := map[string]string{"s3verify": "count", "first": "1", "second": "2"}
+	if _, err := putObjectTagged(config, bucketName, taggedObject, tags); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if _, err := putObject(config, bucketName, untaggedObject); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Servers without tagging would fail the count for a reason other than the HEAD response.
+	_, supported, err := getObjectTagging(config, bucketName, taggedObject.Key, "")
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if supported {
+		if err := verifyHeaderTaggingCount(config, bucketName, taggedObject.Key, len(tags)); err != nil {
+			printMessage(message, err)
+			return false
+		}
+		// Spin scanBar
+		scanBar(message)
+		if err := verifyHeaderTaggingCount(config, bucketName, untaggedObject.Key, 0); err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Spin scanBar
+	scanBar(message)
+	for _, object := range []*ObjectInfo{taggedObject, untaggedObject} {
+		if err := removeObject(config, bucketName, object.Key); err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Test passed.
+	printMessage(message, nil)
+	if !supported {
+		printDetail("Skipped: server does not support object tagging")
+	}
+	return true
+}
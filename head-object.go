@@ -96,12 +96,29 @@ func verifyHeaderHeadObject(header http.Header) error {
 	return nil
 }
 
+// headObject - HEAD an object and return its headers once the response is verified.
+func headObject(config ServerConfig, bucketName, objectName string) (http.Header, error) {
+	req, err := newHeadObjectReq(bucketName, objectName)
+	if err != nil {
+		return nil, err
+	}
+	res, err := config.execRequest("HEAD", req)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(res)
+	if err := headObjectVerify(res, http.StatusOK); err != nil {
+		return nil, err
+	}
+	return res.Header, nil
+}
+
 // mainHeadObject - test the HeadObject API with no header set.
 func mainHeadObject(config ServerConfig, curTest int) bool {
 	message := fmt.Sprintf("[%02d/%d] HeadObject:", curTest, globalTotalNumTest)
 	// All headobject tests are run in s3verify buckets on s3verify created objects.
 	bucketName := s3verifyBuckets[0].Name
-	for _, object := range s3verifyObjects {
+	for _, object := range s3verifyObjects.snapshot() {
 		// Spin scanBar
 		scanBar(message)
 		// Create a new HEAD object with no headers.
@@ -116,9 +133,10 @@ func mainHeadObject(config ServerConfig, curTest int) bool {
 			printMessage(message, err)
 			return false
 		}
-		defer closeResponse(res)
 		// Verify the response.
-		if err := headObjectVerify(res, http.StatusOK); err != nil {
+		err = headObjectVerify(res, http.StatusOK)
+		drainAndClose(res)
+		if err != nil {
 			printMessage(message, err)
 			return false
 		}
@@ -0,0 +1,139 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net/textproto"
+	"sort"
+	"strings"
+)
+
+// awsHeaderCasing - the exact casing AWS uses on the wire for headers clients are known to match case sensitively.
+var awsHeaderCasing = map[string]string{
+	"etag":             "ETag",
+	"last-modified":    "Last-Modified",
+	"content-length":   "Content-Length",
+	"content-type":     "Content-Type",
+	"accept-ranges":    "Accept-Ranges",
+	"x-amz-request-id": "x-amz-request-id",
+	"x-amz-id-2":       "x-amz-id-2",
+	"x-amz-version-id": "x-amz-version-id",
+}
+
+// rawResponseHeaderNames - send req on a connection of its own and return the header names of
+// the response exactly as they were received. net/http canonicalizes them, e.g. ETag to Etag.
+func rawResponseHeaderNames(config ServerConfig, method string, customReq Request) ([]string, error) {
+	req, err := config.newRequest(method, customReq)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := dialRaw(config, req.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	req.Close = true
+	if err := req.Write(conn); err != nil {
+		return nil, err
+	}
+	reader := textproto.NewReader(bufio.NewReader(conn))
+	// Skip the status line.
+	if _, err := reader.ReadLine(); err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for {
+		line, err := reader.ReadLine()
+		if err != nil {
+			return nil, err
+		}
+		if line == "" {
+			return names, nil
+		}
+		if i := strings.Index(line, ":"); i > 0 {
+			names = append(names, line[:i])
+		}
+	}
+}
+
+// verifyHeaderCasing - verify the known headers were sent with the casing AWS uses.
+func verifyHeaderCasing(names []string) error {
+	deviations := []string{}
+	for _, name := range names {
+		expected, ok := awsHeaderCasing[strings.ToLower(name)]
+		if ok && name != expected {
+			deviations = append(deviations, fmt.Sprintf("%s instead of %s", name, expected))
+		}
+	}
+	if len(deviations) > 0 {
+		sort.Strings(deviations)
+		err := fmt.Errorf("Unexpected Header Casing Received: %v", strings.Join(deviations, ", "))
+		return err
+	}
+	return nil
+}
+
+// mainHeaderCasing - verify the wire casing of key response headers matches AWS.
+func mainHeaderCasing(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Strict (Header Casing):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/strict/header-casing",
+		Body: []byte("s3verify header casing"),
+	}
+	if _, err := putObject(config, bucketName, object); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Both HEAD and GET return the object headers.
+	for _, method := range []string{"HEAD", "GET"} {
+		// Spin scanBar
+		scanBar(message)
+		req, err := newGetObjectReq(bucketName, object.Key, nil)
+		if method == "HEAD" {
+			req, err = newHeadObjectReq(bucketName, object.Key)
+		}
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		names, err := rawResponseHeaderNames(config, method, req)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		if err := verifyHeaderCasing(names); err != nil {
+			err = fmt.Errorf("%s: %v", method, err)
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Remove the object so it does not interfere with future tests.
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,72 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// silentReporter - records results like any other reporter but prints nothing.
+type silentReporter struct {
+	testRecorder
+}
+
+func (r *silentReporter) result(message string, err error) { r.record(message, err) }
+func (r *silentReporter) detail(detail string)             { r.addDetail(detail) }
+func (r *silentReporter) fail(err error)                   { r.failLast(err) }
+func (r *silentReporter) info(info string)                 {}
+func (r *silentReporter) finish()                          {}
+
+// newTestConfig - set up the globals a run would and return a config for a local server at endpoint.
+// Retries are kept but their delays are made short enough not to slow down the tests.
+func newTestConfig(endpoint string) ServerConfig {
+	setGlobals(verbosityQuiet, 1, "test")
+	globalReporter = &silentReporter{}
+	globalUploadBudget = newUploadBudget(0)
+	globalLearnedRegion = &regionHint{}
+	globalRateLimiter = nil
+	globalCustomHeaders = nil
+	scanBar = func(string) {}
+	return ServerConfig{
+		Access:           "s3verify-access",
+		Secret:           "s3verify-secret",
+		Endpoint:         endpoint,
+		Region:           globalDefaultRegion,
+		SignatureVersion: signatureV4,
+		MaxRetries:       4,
+		RetryDelay:       time.Millisecond,
+		Client: &http.Client{
+			Transport: &http.Transport{},
+		},
+	}
+}
+
+// writeS3Headers - set the headers every S3 response carries.
+func writeS3Headers(w http.ResponseWriter) {
+	w.Header().Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	w.Header().Set("x-amz-request-id", "s3verify-test")
+}
+
+// writeS3Error - answer with an S3 error response, extra is added to the Error element as is.
+func writeS3Error(w http.ResponseWriter, status int, code, extra string) {
+	writeS3Headers(w)
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message>%s<RequestId>s3verify-test</RequestId></Error>", code, code, extra)
+}
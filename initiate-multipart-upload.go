@@ -44,6 +44,12 @@ var multipartObjects = []*ObjectInfo{
 
 // newInitiateMultipartUploadReq - Create a new HTTP request for the initiate-multipart-upload API.
 func newInitiateMultipartUploadReq(bucketName, objectName string) (Request, error) {
+	return newInitiateMultipartUploadHeaderReq(bucketName, objectName, nil)
+}
+
+// newInitiateMultipartUploadHeaderReq - Create a new HTTP request for the initiate-multipart-upload API
+// that also sends the given headers, such as the Content-Type and metadata of the final object.
+func newInitiateMultipartUploadHeaderReq(bucketName, objectName string, header http.Header) (Request, error) {
 	// Initialize url queries.
 	urlValues := make(url.Values)
 	urlValues.Set("uploads", "")
@@ -66,6 +72,9 @@ func newInitiateMultipartUploadReq(bucketName, objectName string) (Request, erro
 		return Request{}, err
 	}
 
+	for k, v := range header {
+		initiateMultipartUploadReq.customHeader[k] = v
+	}
 	initiateMultipartUploadReq.customHeader.Set("User-Agent", appUserAgent)
 	initiateMultipartUploadReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
 
@@ -104,6 +113,10 @@ func verifyBodyInitiateMultipartUpload(resBody io.Reader) (string, error) {
 	}
 	// Body was sent set the object UploadID.
 	uploadID := resInitiateMultipartUpload.UploadID
+	if uploadID == "" {
+		err := fmt.Errorf("Missing UploadId: the initiated upload can not be continued without one")
+		return "", err
+	}
 	return uploadID, nil
 }
 
@@ -115,6 +128,20 @@ func verifyHeaderInitiateMultipartUpload(header http.Header) error {
 	return nil
 }
 
+// initiateMultipartUpload - start a new multipart upload for objectName, verify the response and return its uploadID.
+func initiateMultipartUpload(config ServerConfig, bucketName, objectName string) (string, error) {
+	req, err := newInitiateMultipartUploadReq(bucketName, objectName)
+	if err != nil {
+		return "", err
+	}
+	res, err := config.execRequest("POST", req)
+	if err != nil {
+		return "", err
+	}
+	defer drainAndClose(res)
+	return initiateMultipartUploadVerify(res, http.StatusOK)
+}
+
 // mainInitiateMultipartUpload - initiate multipart upload test.
 func mainInitiateMultipartUpload(config ServerConfig, curTest int) bool {
 	message := fmt.Sprintf("[%02d/%d] Multipart (Initiate-Upload):", curTest, globalTotalNumTest)
@@ -138,9 +165,9 @@ func mainInitiateMultipartUpload(config ServerConfig, curTest int) bool {
 			printMessage(message, err)
 			return false
 		}
-		defer closeResponse(res)
 		// Verify the response and get the uploadID.
 		uploadID, err := initiateMultipartUploadVerify(res, http.StatusOK)
+		drainAndClose(res)
 		if err != nil {
 			printMessage(message, err)
 			return false
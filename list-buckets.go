@@ -158,7 +158,7 @@ func mainListBuckets(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(res)
+	defer drainAndClose(res)
 	// Spin the scanBar
 	scanBar(message)
 	// Check for S3 Compatibility
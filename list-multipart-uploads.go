@@ -26,7 +26,8 @@ import (
 )
 
 // newListMultipartUploadsReq - Create a new HTTP request for List Multipart Uploads API.
-func newListMultipartUploadsReq(bucketName string) (Request, error) {
+// Only uploads of objects starting with prefix are listed, every upload if it is empty.
+func newListMultipartUploadsReq(bucketName, prefix string) (Request, error) {
 	// listMultipartUploadsReq - a new HTTP request for the List Multipart Uploads API.
 	var listMultipartUploadsReq = Request{
 		customHeader: http.Header{},
@@ -35,6 +36,9 @@ func newListMultipartUploadsReq(bucketName string) (Request, error) {
 	// Set the query values.
 	urlValues := make(url.Values)
 	urlValues.Set("uploads", "")
+	if prefix != "" {
+		urlValues.Set("prefix", prefix)
+	}
 	listMultipartUploadsReq.queryValues = urlValues
 
 	// Set the bucketName.
@@ -135,7 +139,7 @@ func mainListMultipartUploads(config ServerConfig, curTest int) bool {
 	// Spin scanBar
 	scanBar(message)
 	// Create a new request.
-	req, err := newListMultipartUploadsReq(bucketName)
+	req, err := newListMultipartUploadsReq(bucketName, "")
 	if err != nil {
 		printMessage(message, err)
 		return false
@@ -148,7 +152,7 @@ func mainListMultipartUploads(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(res)
+	defer drainAndClose(res)
 	// Spin scanBar
 	scanBar(message)
 	// Verify the response.
@@ -0,0 +1,201 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// The number of parts uploaded to the listed upload and the page size its parts are listed with,
+// small enough that ListParts has to be followed across pages.
+const (
+	listMultipartParts    = 5
+	listMultipartMaxParts = 2
+)
+
+// listUploadIDs - the ids of every upload in progress in bucketName for objects starting with prefix.
+func listUploadIDs(config ServerConfig, bucketName, prefix string) (map[string]bool, error) {
+	req, err := newListMultipartUploadsReq(bucketName, prefix)
+	if err != nil {
+		return nil, err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(res)
+	if err := verifyStatusListMultipartUploads(res.StatusCode, http.StatusOK); err != nil {
+		return nil, err
+	}
+	if err := verifyHeaderListMultipartUploads(res.Header); err != nil {
+		return nil, err
+	}
+	result := listMultipartUploadsResult{}
+	if err := xmlDecoder(res.Body, &result); err != nil {
+		return nil, err
+	}
+	uploadIDs := map[string]bool{}
+	for _, upload := range result.Uploads {
+		uploadIDs[upload.UploadID] = true
+	}
+	return uploadIDs, nil
+}
+
+// listAllParts - list every part of uploadID, maxParts at a time, verifying each page is marked truncated
+// exactly when more parts follow and continues where the previous one stopped.
+func listAllParts(config ServerConfig, bucketName, objectName, uploadID string, maxParts int) ([]objectPart, error) {
+	parts := []objectPart{}
+	partNumberMarker := 0
+	for {
+		req, err := newListPartsPageReq(bucketName, objectName, uploadID, maxParts, partNumberMarker)
+		if err != nil {
+			return nil, err
+		}
+		res, err := config.execRequest("GET", req)
+		if err != nil {
+			return nil, err
+		}
+		result := listObjectPartsResult{}
+		err = verifyStatusListParts(res.StatusCode, http.StatusOK)
+		if err == nil {
+			err = xmlDecoder(res.Body, &result)
+		}
+		drainAndClose(res)
+		if err != nil {
+			return nil, err
+		}
+		if len(result.ObjectParts) > maxParts {
+			err := fmt.Errorf("Unexpected Number of Parts Received: wanted at most %v with max-parts=%v, got %v", maxParts, maxParts, len(result.ObjectParts))
+			return nil, err
+		}
+		for _, part := range result.ObjectParts {
+			if part.PartNumber <= partNumberMarker {
+				err := fmt.Errorf("Unexpected Part Received: part %v listed after part-number-marker=%v", part.PartNumber, partNumberMarker)
+				return nil, err
+			}
+		}
+		parts = append(parts, result.ObjectParts...)
+		if !result.IsTruncated {
+			return parts, nil
+		}
+		if len(result.ObjectParts) == 0 {
+			err := fmt.Errorf("Unexpected Empty Page Received: IsTruncated set after part-number-marker=%v", partNumberMarker)
+			return nil, err
+		}
+		partNumberMarker = result.ObjectParts[len(result.ObjectParts)-1].PartNumber
+		if result.NextPartNumberMarker != 0 {
+			partNumberMarker = result.NextPartNumberMarker
+		}
+	}
+}
+
+// verifyListedParts - verify listed holds exactly the parts numbered 1 onward with the ETags UploadPart returned.
+func verifyListedParts(listed []objectPart, eTags []string) error {
+	if len(listed) != len(eTags) {
+		err := fmt.Errorf("Unexpected Number of Parts Listed: wanted %v, got %v", len(eTags), len(listed))
+		return err
+	}
+	for i, part := range listed {
+		if part.PartNumber != i+1 {
+			err := fmt.Errorf("Unexpected PartNumber Listed: wanted %v, got %v", i+1, part.PartNumber)
+			return err
+		}
+		if expected := "\"" + eTags[i] + "\""; part.ETag != expected {
+			err := ChecksumMismatchError{Checksum: "ETag of part " + strconv.Itoa(part.PartNumber), Expected: expected, Got: part.ETag}
+			return err
+		}
+	}
+	return nil
+}
+
+// mainListMultipart - Test two uploads in progress are both listed by ListMultipartUploads and the parts of
+// one are listed by ListParts, page by page, with the part numbers and ETags UploadPart returned.
+func mainListMultipart(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Multipart (List Uploads and Parts):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	prefix := fmt.Sprintf("s3verify/multipart/list-%d/", time.Now().UnixNano())
+	objectNames := []string{prefix + "parts", prefix + "empty"}
+	uploadIDs := []string{}
+	// Both uploads are aborted however the test ends.
+	defer func() {
+		for i, uploadID := range uploadIDs {
+			abortMultipartUpload(config, bucketName, objectNames[i], uploadID)
+		}
+	}()
+	for _, objectName := range objectNames {
+		uploadID, err := initiateMultipartUpload(config, bucketName, objectName)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		uploadIDs = append(uploadIDs, uploadID)
+	}
+	// Only the last part of a completed upload may be under 5MiB, the upload is never completed.
+	eTags := []string{}
+	for partNumber := 1; partNumber <= listMultipartParts; partNumber++ {
+		// Spin scanBar
+		scanBar(message)
+		eTag, err := uploadPart(config, bucketName, objectNames[0], uploadIDs[0], partNumber, generatedBody(int64(partNumber), 1024))
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		eTags = append(eTags, eTag)
+	}
+	// Spin scanBar
+	scanBar(message)
+	listed, err := listUploadIDs(config, bucketName, prefix)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	for i, uploadID := range uploadIDs {
+		if !listed[uploadID] {
+			err := fmt.Errorf("Missing Upload: %v of %v is in progress and was not listed", uploadID, objectNames[i])
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Spin scanBar
+	scanBar(message)
+	parts, err := listAllParts(config, bucketName, objectNames[0], uploadIDs[0], listMultipartMaxParts)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyListedParts(parts, eTags); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	for i, uploadID := range uploadIDs {
+		if err := abortMultipartUpload(config, bucketName, objectNames[i], uploadID); err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	uploadIDs = nil
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,90 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// newListObjectVersionsReq - Create a new HTTP request for the ListObjectVersions API.
+func newListObjectVersionsReq(bucketName string, parameters map[string]string) (Request, error) {
+	// listObjectVersionsReq - a new HTTP request for ListObjectVersions.
+	var listObjectVersionsReq = Request{
+		customHeader: http.Header{},
+	}
+
+	// Set the bucketName.
+	listObjectVersionsReq.bucketName = bucketName
+
+	// Set the query values.
+	urlValues := make(url.Values)
+	urlValues.Set("versions", "")
+	for k, v := range parameters {
+		urlValues.Set(k, v)
+	}
+	listObjectVersionsReq.queryValues = urlValues
+
+	// No body is sent with GET requests.
+	reader := bytes.NewReader([]byte{})
+	_, sha256Sum, _, err := computeHash(reader)
+	if err != nil {
+		return Request{}, err
+	}
+
+	// Set the headers.
+	listObjectVersionsReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
+	listObjectVersionsReq.customHeader.Set("User-Agent", appUserAgent)
+
+	return listObjectVersionsReq, nil
+}
+
+// verifyStatusListObjectVersions - verify the status returned matches what is expected.
+func verifyStatusListObjectVersions(respStatusCode, expectedStatusCode int) error {
+	if respStatusCode != expectedStatusCode {
+		err := fmt.Errorf("Unexpected Status Received: wanted %v, got %v", expectedStatusCode, respStatusCode)
+		return err
+	}
+	return nil
+}
+
+// listObjectVersionsPage - fetch a single page of a ListObjectVersions listing.
+func listObjectVersionsPage(config ServerConfig, bucketName string, parameters map[string]string) (listVersionsResult, error) {
+	receivedList := listVersionsResult{}
+	req, err := newListObjectVersionsReq(bucketName, parameters)
+	if err != nil {
+		return receivedList, err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return receivedList, err
+	}
+	defer drainAndClose(res)
+	if err := verifyStatusListObjectVersions(res.StatusCode, http.StatusOK); err != nil {
+		return receivedList, err
+	}
+	if err := verifyStandardHeaders(res.Header); err != nil {
+		return receivedList, err
+	}
+	if err := xmlDecoder(res.Body, &receivedList); err != nil {
+		return receivedList, err
+	}
+	return receivedList, nil
+}
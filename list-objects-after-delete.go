@@ -0,0 +1,131 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// The prefix used by the ListObjects after delete test.
+const listDeletePrefix = "s3verify/list-delete/"
+
+// listKeysWithPrefix - list every key under prefix, following markers.
+func listKeysWithPrefix(config ServerConfig, bucketName, prefix string) ([]string, error) {
+	listedKeys := []string{}
+	marker := ""
+	for {
+		parameters := map[string]string{
+			"prefix": prefix,
+		}
+		if marker != "" {
+			parameters["marker"] = marker
+		}
+		receivedList, err := listObjectsPage(config, bucketName, parameters)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range receivedList.Contents {
+			listedKeys = append(listedKeys, object.Key)
+		}
+		if !receivedList.IsTruncated || len(receivedList.Contents) == 0 {
+			return listedKeys, nil
+		}
+		marker = receivedList.Contents[len(receivedList.Contents)-1].Key
+	}
+}
+
+// verifyListingEventually - list prefix until it matches expectedKeys, retrying up to globalListRetries times.
+func verifyListingEventually(config ServerConfig, bucketName, prefix string, expectedKeys []string) (err error) {
+	for range newRetryTimer(globalListRetries+1, time.Second, time.Second*30, MaxJitter, globalRandom) {
+		var listedKeys []string
+		listedKeys, err = listKeysWithPrefix(config, bucketName, prefix)
+		if err != nil {
+			return err
+		}
+		if err = verifyListedKeys(listedKeys, expectedKeys); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// mainListObjectsAfterDelete - verify that ListObjects reflects removed objects.
+func mainListObjectsAfterDelete(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] ListObjects (After Delete):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	objects := []*ObjectInfo{}
+	for i := 0; i < 10; i++ {
+		object := &ObjectInfo{
+			Key:  listDeletePrefix + strconv.Itoa(i),
+			Body: []byte(randString(60, rand.NewSource(time.Now().UnixNano()), "")),
+		}
+		if _, err := putObject(config, bucketName, object); err != nil {
+			printMessage(message, err)
+			return false
+		}
+		objects = append(objects, object)
+		// Spin scanBar
+		scanBar(message)
+	}
+	allKeys := []string{}
+	for _, object := range objects {
+		allKeys = append(allKeys, object.Key)
+	}
+	// Every uploaded object must be listed.
+	if err := verifyListingEventually(config, bucketName, listDeletePrefix, allKeys); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Remove half of the objects.
+	remainingKeys := []string{}
+	for i, object := range objects {
+		if i%2 == 0 {
+			remainingKeys = append(remainingKeys, object.Key)
+			continue
+		}
+		if err := removeObject(config, bucketName, object.Key); err != nil {
+			printMessage(message, err)
+			return false
+		}
+		// Spin scanBar
+		scanBar(message)
+	}
+	// Only the remaining objects may be listed.
+	if err := verifyListingEventually(config, bucketName, listDeletePrefix, remainingKeys); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Remove the remaining objects.
+	for _, objectName := range remainingKeys {
+		if err := removeObject(config, bucketName, objectName); err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,152 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// verifyBodyListObjectsEmptyBucket - verify the body returned is a well formed but empty listing.
+func verifyBodyListObjectsEmptyBucket(resBody io.Reader, expectedList listBucketResult) error {
+	receivedList := listBucketResult{}
+	if err := xmlDecoder(resBody, &receivedList); err != nil {
+		return err
+	}
+	if receivedList.Name != expectedList.Name {
+		err := fmt.Errorf("Unexpected Bucket Listed: wanted %v, got %v", expectedList.Name, receivedList.Name)
+		return err
+	}
+	if receivedList.Prefix != expectedList.Prefix {
+		err := fmt.Errorf("Unexpected Prefix Received: wanted %v, got %v", expectedList.Prefix, receivedList.Prefix)
+		return err
+	}
+	if receivedList.MaxKeys != expectedList.MaxKeys {
+		err := fmt.Errorf("Unexpected MaxKeys Received: wanted %v, got %v", expectedList.MaxKeys, receivedList.MaxKeys)
+		return err
+	}
+	if receivedList.IsTruncated {
+		err := fmt.Errorf("Unexpected IsTruncated Received: an empty bucket listing should never be truncated")
+		return err
+	}
+	if len(receivedList.Contents) != 0 || len(receivedList.CommonPrefixes) != 0 {
+		err := fmt.Errorf("Incorrect Number of Objects Listed: wanted 0 objects and 0 prefixes, got %d objects and %d prefixes",
+			len(receivedList.Contents), len(receivedList.CommonPrefixes))
+		return err
+	}
+	return nil
+}
+
+// listObjectsEmptyBucketVerify - verify the response returned for an empty bucket matches what is expected.
+func listObjectsEmptyBucketVerify(res *http.Response, expectedStatusCode int, expectedList listBucketResult) error {
+	if err := verifyStatusListObjectsV1(res.StatusCode, expectedStatusCode); err != nil {
+		return err
+	}
+	if err := verifyBodyListObjectsEmptyBucket(res.Body, expectedList); err != nil {
+		return err
+	}
+	if err := verifyHeaderListObjectsV1(res.Header); err != nil {
+		return err
+	}
+	return nil
+}
+
+// mainListObjectsEmptyBucket - ListObjects V1 API test on a freshly created bucket with no objects.
+func mainListObjectsEmptyBucket(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] ListObjects (Empty Bucket):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	// Create a new bucket that will never hold any objects.
+	bucketName := "s3verify-" + globalSuffix + "-empty"
+	putBucketReq, err := newPutBucketReq(config.Region, bucketName)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Execute the request.
+	putBucketRes, err := config.execRequest("PUT", putBucketReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(putBucketRes)
+	// Verify the bucket was created.
+	if err := putBucketVerify(putBucketRes, bucketName, http.StatusOK, ErrorResponse{}); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// The prefix and max-keys should be echoed back even though nothing matches.
+	expectedList := listBucketResult{
+		Name:     bucketName,
+		Prefix:   "s3verify/",
+		MaxKeys:  100,
+		Contents: []ObjectInfo{},
+	}
+	// Store the parameters.
+	parameters := map[string]string{
+		"prefix":   "s3verify/",
+		"max-keys": "100",
+	}
+	// Create a new request.
+	req, err := newListObjectsV1Req(bucketName, parameters)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Execute the request.
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	// Spin scanBar
+	scanBar(message)
+	// Verify the response.
+	if err := listObjectsEmptyBucketVerify(res, http.StatusOK, expectedList); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Remove the empty bucket so it does not interfere with future tests.
+	removeBucketReq, err := newRemoveBucketReq(bucketName)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Execute the request.
+	removeBucketRes, err := config.execRequest("DELETE", removeBucketReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(removeBucketRes)
+	// Verify the bucket was removed.
+	if err := removeBucketVerify(removeBucketRes, http.StatusNoContent, ErrorResponse{}); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,184 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The prefix shared by every object uploaded for the listing tests.
+const paginationPrefix = "s3verify/put/object/"
+
+// listObjectsPage - fetch a single page of a ListObjects V1 listing.
+func listObjectsPage(config ServerConfig, bucketName string, parameters map[string]string) (listBucketResult, error) {
+	receivedList := listBucketResult{}
+	req, err := newListObjectsV1Req(bucketName, parameters)
+	if err != nil {
+		return receivedList, err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return receivedList, err
+	}
+	defer drainAndClose(res)
+	if err := verifyStatusListObjectsV1(res.StatusCode, http.StatusOK); err != nil {
+		return receivedList, err
+	}
+	if err := verifyHeaderListObjectsV1(res.Header); err != nil {
+		return receivedList, err
+	}
+	if err := xmlDecoder(res.Body, &receivedList); err != nil {
+		return receivedList, err
+	}
+	return receivedList, nil
+}
+
+// verifyListedKeys - verify that every expected key was listed exactly once and nothing else was.
+func verifyListedKeys(listedKeys []string, expectedKeys []string) error {
+	seen := make(map[string]int)
+	for _, key := range listedKeys {
+		seen[key]++
+	}
+	for _, key := range expectedKeys {
+		if seen[key] != 1 {
+			err := fmt.Errorf("Incorrect Listing for %s: wanted it listed once, got it listed %d times", key, seen[key])
+			return err
+		}
+	}
+	if len(listedKeys) != len(expectedKeys) {
+		err := fmt.Errorf("Incorrect Number of Objects Listed: wanted %d, got %d", len(expectedKeys), len(listedKeys))
+		return err
+	}
+	return nil
+}
+
+// mainListObjectsPagination - ListObjects V1 pagination test over the objects uploaded for listing.
+func mainListObjectsPagination(config ServerConfig, curTest int, bucketName string, testObjects []*ObjectInfo) bool {
+	message := fmt.Sprintf("[%02d/%d] ListObjects (Pagination):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	expectedKeys := []string{}
+	for _, object := range testObjects {
+		if strings.HasPrefix(object.Key, paginationPrefix) {
+			expectedKeys = append(expectedKeys, object.Key)
+		}
+	}
+	sort.Strings(expectedKeys)
+
+	// With the default max-keys of 1000 the first page holds every object unless there are more than 1000.
+	receivedList, err := listObjectsPage(config, bucketName, map[string]string{
+		"prefix": paginationPrefix,
+	})
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	firstPageKeys := expectedKeys
+	if len(firstPageKeys) > maxListKeys {
+		firstPageKeys = firstPageKeys[:maxListKeys]
+	}
+	if receivedList.IsTruncated != (len(expectedKeys) > maxListKeys) {
+		err := fmt.Errorf("Unexpected IsTruncated Received for %d objects and the default max-keys of %d: wanted %v, got %v",
+			len(expectedKeys), maxListKeys, len(expectedKeys) > maxListKeys, receivedList.IsTruncated)
+		printMessage(message, err)
+		return false
+	}
+	listedKeys := []string{}
+	for _, object := range receivedList.Contents {
+		listedKeys = append(listedKeys, object.Key)
+	}
+	if err := verifyListedKeys(listedKeys, firstPageKeys); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+
+	// With max-keys set to 30 follow the markers until the listing is complete.
+	maxKeys := 30
+	expectedPages := (len(expectedKeys) + maxKeys - 1) / maxKeys
+	listedKeys = []string{}
+	marker := ""
+	for page := 1; ; page++ {
+		// Spin scanBar
+		scanBar(message)
+		parameters := map[string]string{
+			"prefix":   paginationPrefix,
+			"max-keys": strconv.Itoa(maxKeys),
+		}
+		if marker != "" {
+			parameters["marker"] = marker
+		}
+		receivedList, err := listObjectsPage(config, bucketName, parameters)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		if len(receivedList.Contents) > maxKeys {
+			err := fmt.Errorf("Incorrect Number of Objects Listed: wanted at most %d on page %d, got %d", maxKeys, page, len(receivedList.Contents))
+			printMessage(message, err)
+			return false
+		}
+		for _, object := range receivedList.Contents {
+			listedKeys = append(listedKeys, object.Key)
+		}
+		// Every page but the last must be truncated.
+		if receivedList.IsTruncated != (page < expectedPages) {
+			err := fmt.Errorf("Unexpected IsTruncated Received on page %d of %d: wanted %v, got %v", page, expectedPages, page < expectedPages, receivedList.IsTruncated)
+			printMessage(message, err)
+			return false
+		}
+		if !receivedList.IsTruncated {
+			break
+		}
+		// NextMarker is only returned when a delimiter is set, otherwise the last key is the marker.
+		marker = receivedList.NextMarker
+		if marker == "" && len(receivedList.Contents) > 0 {
+			marker = receivedList.Contents[len(receivedList.Contents)-1].Key
+		}
+		if marker == "" {
+			err := fmt.Errorf("Unexpected Empty Page Received: page %d is truncated but lists no objects", page)
+			printMessage(message, err)
+			return false
+		}
+	}
+	if err := verifyListedKeys(listedKeys, expectedKeys); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
+
+// mainListObjectsPaginationUnPrepared - ListObjects pagination test over the objects uploaded by PutObject.
+func mainListObjectsPaginationUnPrepared(config ServerConfig, curTest int) bool {
+	bucketName := s3verifyBuckets[0].Name
+	return mainListObjectsPagination(config, curTest, bucketName, s3verifyObjects.snapshot())
+}
+
+// mainListObjectsPaginationPrepared - ListObjects pagination test over the objects uploaded by --prepare.
+func mainListObjectsPaginationPrepared(config ServerConfig, curTest int) bool {
+	bucketName := preparedBuckets[0].Name
+	return mainListObjectsPagination(config, curTest, bucketName, preparedObjects.snapshot())
+}
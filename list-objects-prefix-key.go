@@ -0,0 +1,86 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The prefix used by the ListObjects prefix equal to a key test.
+const listPrefixKeyPrefix = "s3verify/list-prefix-key/"
+
+// mainListObjectsPrefixKey - verify that a prefix equal to a full key lists that key and the keys
+// extending it but none of its neighbors, and that a prefix matching no keys lists nothing.
+func mainListObjectsPrefixKey(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] ListObjects (Prefix Equals Key):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	fullKey := listPrefixKeyPrefix + "object"
+	keys := []string{
+		fullKey,
+		fullKey + "-extended",          // Starts with the full key so it must be listed too.
+		fullKey + "/nested",            // As must a key below it.
+		listPrefixKeyPrefix + "objec",  // A prefix of the full key must not be listed.
+		listPrefixKeyPrefix + "objecs", // Nor a key sorting right before it.
+		listPrefixKeyPrefix + "objecu", // Nor one sorting after it.
+	}
+	for _, key := range keys {
+		object := &ObjectInfo{
+			Key:  key,
+			Body: generatedBody(time.Now().UnixNano(), 60),
+		}
+		if _, err := putObject(config, bucketName, object); err != nil {
+			printMessage(message, err)
+			return false
+		}
+		// Spin scanBar
+		scanBar(message)
+	}
+	expectedKeys := []string{}
+	for _, key := range keys {
+		if strings.HasPrefix(key, fullKey) {
+			expectedKeys = append(expectedKeys, key)
+		}
+	}
+	// Prefix matching is inclusive: the full key itself is listed.
+	if err := verifyListingEventually(config, bucketName, fullKey, expectedKeys); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// A prefix extending a key that no key starts with lists nothing.
+	if err := verifyListingEventually(config, bucketName, fullKey+"-missing", []string{}); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Remove the objects created by this test.
+	for _, key := range keys {
+		if err := removeObject(config, bucketName, key); err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
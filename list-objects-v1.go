@@ -57,6 +57,17 @@ func newListObjectsV1Req(bucketName string, parameters map[string]string) (Reque
 	return listObjectsV1Req, nil
 }
 
+// maxListKeys - the most keys a single ListObjects page holds when max-keys is not set.
+const maxListKeys = 1000
+
+// firstListPage - the objects a listing without max-keys returns on its first page.
+func firstListPage(objects ObjectInfos) ObjectInfos {
+	if len(objects) > maxListKeys {
+		return objects[:maxListKeys]
+	}
+	return objects
+}
+
 // listObjectsV1Verify - verify the response returned matches what is expected.
 func listObjectsV1Verify(res *http.Response, expectedStatusCode int, expectedList listBucketResult) error {
 	if err := verifyStatusListObjectsV1(res.StatusCode, expectedStatusCode); err != nil {
@@ -141,8 +152,8 @@ func mainListObjectsV1(config ServerConfig, curTest int, bucketName string, test
 	sort.Sort(objectInfo)
 	// Test for listobjects with no extra parameters.
 	expectedList := listBucketResult{
-		Name:     bucketName, // Listing from the first bucket created that houses all objects.
-		Contents: objectInfo, // The first bucket created will house all the objects created by the PUT object test.
+		Name:     bucketName,                // Listing from the first bucket created that houses all objects.
+		Contents: firstListPage(objectInfo), // The first bucket created will house all the objects created by the PUT object test.
 	}
 	// Create a new request.
 	noParamReq, err := newListObjectsV1Req(bucketName, nil) // No extra parameters for the first test.
@@ -158,7 +169,7 @@ func mainListObjectsV1(config ServerConfig, curTest int, bucketName string, test
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(noParamRes)
+	defer drainAndClose(noParamRes)
 	// Spin scanBar
 	scanBar(message)
 	// Verify the response.
@@ -193,7 +204,7 @@ func mainListObjectsV1(config ServerConfig, curTest int, bucketName string, test
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(maxKeysRes)
+	defer drainAndClose(maxKeysRes)
 	// Spin scanBar
 	scanBar(message)
 	// Verify the max-keys parameter is respected.
@@ -208,7 +219,7 @@ func mainListObjectsV1(config ServerConfig, curTest int, bucketName string, test
 	expectedListPrefix := listBucketResult{
 		Name: bucketName,
 		// Should only return objects that were put during the put-object test.
-		Contents: objectInfo[1:],
+		Contents: firstListPage(objectInfo[1:]),
 		Prefix:   "s3verify/put/object/",
 	}
 	// Store the parameters.
@@ -229,7 +240,7 @@ func mainListObjectsV1(config ServerConfig, curTest int, bucketName string, test
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(prefixRes)
+	defer drainAndClose(prefixRes)
 	// Verify the prefix parameter is respected.
 	if err := listObjectsV1Verify(prefixRes, http.StatusOK, expectedListPrefix); err != nil {
 		printMessage(message, err)
@@ -267,7 +278,7 @@ func mainListObjectsV1(config ServerConfig, curTest int, bucketName string, test
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(prefixDelimRes)
+	defer drainAndClose(prefixDelimRes)
 	// Verify that delimiter and prefix parameters are respected.
 	if err := listObjectsV1Verify(prefixDelimRes, http.StatusOK, expectedListDelimiterPrefix); err != nil {
 		printMessage(message, err)
@@ -284,11 +295,11 @@ func mainListObjectsV1(config ServerConfig, curTest int, bucketName string, test
 //
 func mainListObjectsV1UnPrepared(config ServerConfig, curTest int) bool {
 	bucketName := s3verifyBuckets[0].Name
-	return mainListObjectsV1(config, curTest, bucketName, s3verifyObjects)
+	return mainListObjectsV1(config, curTest, bucketName, s3verifyObjects.snapshot())
 }
 
 //
 func mainListObjectsV1Prepared(config ServerConfig, curTest int) bool {
 	bucketName := preparedBuckets[0].Name
-	return mainListObjectsV1(config, curTest, bucketName, preparedObjects)
+	return mainListObjectsV1(config, curTest, bucketName, preparedObjects.snapshot())
 }
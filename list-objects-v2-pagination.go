@@ -0,0 +1,137 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// verifyKeyCount - verify KeyCount counts the keys and common prefixes returned on the page.
+func verifyKeyCount(receivedList listBucketV2Result) error {
+	if returned := len(receivedList.Contents) + len(receivedList.CommonPrefixes); receivedList.KeyCount != returned {
+		err := fmt.Errorf("Unexpected KeyCount Received: wanted %d, got %d", returned, receivedList.KeyCount)
+		return err
+	}
+	return nil
+}
+
+// mainListObjectsV2Pagination - ListObjects V2 pagination test over the objects uploaded for listing. Every page
+// is followed by its NextContinuationToken until IsTruncated is false, then a listing with start-after
+// must skip every key up to and including it.
+func mainListObjectsV2Pagination(config ServerConfig, curTest int, bucketName string, testObjects []*ObjectInfo) bool {
+	message := fmt.Sprintf("[%02d/%d] ListObjects V2 (Pagination):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	expectedKeys := []string{}
+	for _, object := range testObjects {
+		if strings.HasPrefix(object.Key, paginationPrefix) {
+			expectedKeys = append(expectedKeys, object.Key)
+		}
+	}
+	sort.Strings(expectedKeys)
+
+	// With max-keys set to 30 follow the continuation-tokens until the listing is complete.
+	maxKeys := 30
+	parameters := map[string]string{
+		"prefix":   paginationPrefix,
+		"max-keys": strconv.Itoa(maxKeys),
+	}
+	listedKeys := []string{}
+	for page := 1; ; page++ {
+		// Spin scanBar
+		scanBar(message)
+		receivedList, err := listObjectsV2Page(config, bucketName, parameters)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		if err := verifyKeyCount(receivedList); err != nil {
+			err = fmt.Errorf("Page %d: %v", page, err)
+			printMessage(message, err)
+			return false
+		}
+		if len(receivedList.Contents) > maxKeys {
+			err := fmt.Errorf("Incorrect Number of Objects Listed: wanted at most %d on page %d, got %d", maxKeys, page, len(receivedList.Contents))
+			printMessage(message, err)
+			return false
+		}
+		for _, object := range receivedList.Contents {
+			listedKeys = append(listedKeys, object.Key)
+		}
+		if !receivedList.IsTruncated {
+			break
+		}
+		if receivedList.NextContinuationToken == "" {
+			err := fmt.Errorf("Missing NextContinuationToken: page %d is truncated but does not say where to continue", page)
+			printMessage(message, err)
+			return false
+		}
+		parameters["continuation-token"] = receivedList.NextContinuationToken
+	}
+	if err := verifyListedKeys(listedKeys, expectedKeys); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+
+	// A listing with start-after begins with the first key after it.
+	if len(expectedKeys) > 1 {
+		startAfter := expectedKeys[len(expectedKeys)/2]
+		receivedList, err := listObjectsV2Page(config, bucketName, map[string]string{
+			"prefix":      paginationPrefix,
+			"start-after": startAfter,
+			"max-keys":    strconv.Itoa(maxKeys),
+		})
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		if err := verifyKeyCount(receivedList); err != nil {
+			printMessage(message, err)
+			return false
+		}
+		if len(receivedList.Contents) == 0 {
+			err := fmt.Errorf("Unexpected Empty Listing Received: keys follow start-after %s", startAfter)
+			printMessage(message, err)
+			return false
+		}
+		if first, expected := receivedList.Contents[0].Key, expectedKeys[len(expectedKeys)/2+1]; first != expected {
+			err := fmt.Errorf("Unexpected First Key Received after start-after %s: wanted %s, got %s", startAfter, expected, first)
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
+
+// mainListObjectsV2PaginationUnPrepared - ListObjects V2 pagination test over the objects uploaded by PutObject.
+func mainListObjectsV2PaginationUnPrepared(config ServerConfig, curTest int) bool {
+	bucketName := s3verifyBuckets[0].Name
+	return mainListObjectsV2Pagination(config, curTest, bucketName, s3verifyObjects.snapshot())
+}
+
+// mainListObjectsV2PaginationPrepared - ListObjects V2 pagination test over the objects uploaded by --prepare.
+func mainListObjectsV2PaginationPrepared(config ServerConfig, curTest int) bool {
+	bucketName := preparedBuckets[0].Name
+	return mainListObjectsV2Pagination(config, curTest, bucketName, preparedObjects.snapshot())
+}
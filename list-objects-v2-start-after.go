@@ -0,0 +1,128 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// listObjectsV2Page - fetch a single page of a ListObjects V2 listing.
+func listObjectsV2Page(config ServerConfig, bucketName string, parameters map[string]string) (listBucketV2Result, error) {
+	receivedList := listBucketV2Result{}
+	req, err := newListObjectsV2Req(bucketName, parameters)
+	if err != nil {
+		return receivedList, err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return receivedList, err
+	}
+	defer drainAndClose(res)
+	if err := verifyStatusListObjectsV2(res.StatusCode, http.StatusOK); err != nil {
+		return receivedList, err
+	}
+	if err := verifyHeaderListObjectsV2(res.Header); err != nil {
+		return receivedList, err
+	}
+	if err := xmlDecoder(res.Body, &receivedList); err != nil {
+		return receivedList, err
+	}
+	return receivedList, nil
+}
+
+// mainListObjectsV2StartAfter - paginate a ListObjects V2 listing sending start-after alongside every
+// continuation-token and verify start-after is ignored, as it must be once a continuation-token is given.
+func mainListObjectsV2StartAfter(config ServerConfig, curTest int, bucketName string, testObjects []*ObjectInfo) bool {
+	message := fmt.Sprintf("[%02d/%d] ListObjects V2 (StartAfter with ContinuationToken):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	expectedKeys := []string{}
+	for _, object := range testObjects {
+		if strings.HasPrefix(object.Key, paginationPrefix) {
+			expectedKeys = append(expectedKeys, object.Key)
+		}
+	}
+	sort.Strings(expectedKeys)
+	if len(expectedKeys) < 4 {
+		err := fmt.Errorf("Not enough test objects: need at least 4 under %s, found %d", paginationPrefix, len(expectedKeys))
+		printMessage(message, err)
+		return false
+	}
+	// At least four pages, with start-after pointing past the whole of the second page so a
+	// server that honors it skips keys the continuation-token says come next.
+	maxKeys := len(expectedKeys) / 4
+	startAfter := expectedKeys[2*maxKeys]
+	parameters := map[string]string{
+		"prefix":   paginationPrefix,
+		"max-keys": strconv.Itoa(maxKeys),
+	}
+	listedKeys := []string{}
+	for {
+		// Spin scanBar
+		scanBar(message)
+		receivedList, err := listObjectsV2Page(config, bucketName, parameters)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		// Each page must pick up exactly where the last one stopped.
+		if len(receivedList.Contents) > 0 && len(listedKeys) < len(expectedKeys) {
+			if first := receivedList.Contents[0].Key; first != expectedKeys[len(listedKeys)] {
+				err := fmt.Errorf("Unexpected Page Start Received: wanted %s from the continuation-token, got %s (start-after was %s)",
+					expectedKeys[len(listedKeys)], first, startAfter)
+				printMessage(message, err)
+				return false
+			}
+		}
+		for _, object := range receivedList.Contents {
+			listedKeys = append(listedKeys, object.Key)
+		}
+		if !receivedList.IsTruncated {
+			break
+		}
+		if receivedList.NextContinuationToken == "" {
+			err := fmt.Errorf("Missing NextContinuationToken: a truncated listing must say where to continue")
+			printMessage(message, err)
+			return false
+		}
+		parameters["continuation-token"] = receivedList.NextContinuationToken
+		parameters["start-after"] = startAfter
+	}
+	if err := verifyListedKeys(listedKeys, expectedKeys); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
+
+// mainListObjectsV2StartAfterUnPrepared - start-after precedence test over the objects uploaded by PutObject.
+func mainListObjectsV2StartAfterUnPrepared(config ServerConfig, curTest int) bool {
+	bucketName := s3verifyBuckets[0].Name
+	return mainListObjectsV2StartAfter(config, curTest, bucketName, s3verifyObjects.snapshot())
+}
+
+// mainListObjectsV2StartAfterPrepared - start-after precedence test over the objects uploaded by --prepare.
+func mainListObjectsV2StartAfterPrepared(config ServerConfig, curTest int) bool {
+	bucketName := preparedBuckets[0].Name
+	return mainListObjectsV2StartAfter(config, curTest, bucketName, preparedObjects.snapshot())
+}
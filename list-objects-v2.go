@@ -124,7 +124,7 @@ func mainListObjectsV2(config ServerConfig, curTest int, bucketName string, test
 
 	expectedList := listBucketV2Result{
 		Name:     bucketName, // List only from the first bucket created because that is the bucket holding the objects.
-		Contents: objectInfo,
+		Contents: firstListPage(objectInfo),
 	}
 	// Create a new request.
 	req, err := newListObjectsV2Req(bucketName, nil)
@@ -140,7 +140,7 @@ func mainListObjectsV2(config ServerConfig, curTest int, bucketName string, test
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(res)
+	defer drainAndClose(res)
 	// Verify the response.
 	if err := listObjectsV2Verify(res, http.StatusOK, expectedList); err != nil {
 		printMessage(message, err)
@@ -152,7 +152,7 @@ func mainListObjectsV2(config ServerConfig, curTest int, bucketName string, test
 	// Test for listobjects with start-after parameter set.
 	expectedListStartAfter := listBucketV2Result{
 		Name:     bucketName,
-		Contents: objectInfo[31:],
+		Contents: firstListPage(objectInfo[31:]),
 	}
 
 	// Store the parameters.
@@ -172,7 +172,7 @@ func mainListObjectsV2(config ServerConfig, curTest int, bucketName string, test
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(startAfterRes)
+	defer drainAndClose(startAfterRes)
 	// Verify the response
 	if err := listObjectsV2Verify(startAfterRes, http.StatusOK, expectedListStartAfter); err != nil {
 		printMessage(message, err)
@@ -205,7 +205,7 @@ func mainListObjectsV2(config ServerConfig, curTest int, bucketName string, test
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(maxKeysRes)
+	defer drainAndClose(maxKeysRes)
 	// Spin scanBar
 	scanBar(message)
 	// Verify the max-keys parameter is respected.
@@ -220,7 +220,7 @@ func mainListObjectsV2(config ServerConfig, curTest int, bucketName string, test
 	expectedListPrefix := listBucketV2Result{
 		Name: bucketName,
 		// Should only return objects that were put during the put-object test.
-		Contents: objectInfo[1:],
+		Contents: firstListPage(objectInfo[1:]),
 		Prefix:   "s3verify/put/object/",
 	}
 	// Store the parameters.
@@ -241,7 +241,7 @@ func mainListObjectsV2(config ServerConfig, curTest int, bucketName string, test
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(prefixRes)
+	defer drainAndClose(prefixRes)
 	// Verify the prefix parameter is respected.
 	if err := listObjectsV2Verify(prefixRes, http.StatusOK, expectedListPrefix); err != nil {
 		printMessage(message, err)
@@ -279,7 +279,7 @@ func mainListObjectsV2(config ServerConfig, curTest int, bucketName string, test
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(prefixDelimRes)
+	defer drainAndClose(prefixDelimRes)
 	// Verify that delimiter and prefix parameters are respected.
 	if err := listObjectsV2Verify(prefixDelimRes, http.StatusOK, expectedListDelimiterPrefix); err != nil {
 		printMessage(message, err)
@@ -296,11 +296,11 @@ func mainListObjectsV2(config ServerConfig, curTest int, bucketName string, test
 //
 func mainListObjectsV2UnPrepared(config ServerConfig, curTest int) bool {
 	bucketName := s3verifyBuckets[0].Name
-	return mainListObjectsV2(config, curTest, bucketName, s3verifyObjects)
+	return mainListObjectsV2(config, curTest, bucketName, s3verifyObjects.snapshot())
 }
 
 //
 func mainListObjectsV2Prepared(config ServerConfig, curTest int) bool {
 	bucketName := preparedBuckets[0].Name
-	return mainListObjectsV2(config, curTest, bucketName, preparedObjects)
+	return mainListObjectsV2(config, curTest, bucketName, preparedObjects.snapshot())
 }
@@ -23,6 +23,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 )
 
 // newListPartsReq - Create a new HTTP request for the ListParts API.
@@ -55,6 +56,20 @@ func newListPartsReq(bucketName, objectName, uploadID string) (Request, error) {
 	return listPartsReq, nil
 }
 
+// newListPartsPageReq - Create a new HTTP request for a page of at most maxParts parts, starting after partNumberMarker.
+func newListPartsPageReq(bucketName, objectName, uploadID string, maxParts, partNumberMarker int) (Request, error) {
+	listPartsReq, err := newListPartsReq(bucketName, objectName, uploadID)
+	if err != nil {
+		return Request{}, err
+	}
+	listPartsReq.queryValues.Set("max-parts", strconv.Itoa(maxParts))
+	if partNumberMarker > 0 {
+		listPartsReq.queryValues.Set("part-number-marker", strconv.Itoa(partNumberMarker))
+	}
+
+	return listPartsReq, nil
+}
+
 // listPartsVerify - verify that the returned response matches what is expected.
 func listPartsVerify(res *http.Response, expectedStatusCode int, expectedList listObjectPartsResult) error {
 	if err := verifyStatusListParts(res.StatusCode, expectedStatusCode); err != nil {
@@ -93,8 +108,8 @@ func verifyBodyListParts(resBody io.Reader, expectedList listObjectPartsResult)
 			}
 		}
 	}
-	if totalParts != 1 {
-		err := fmt.Errorf("Incorrect number of parts listed: wanted 1, got %v", totalParts)
+	if totalParts != len(expectedList.ObjectParts) || len(result.ObjectParts) != len(expectedList.ObjectParts) {
+		err := fmt.Errorf("Incorrect number of parts listed: wanted %v, got %v of which %v matched", len(expectedList.ObjectParts), len(result.ObjectParts), totalParts)
 		return err
 	}
 	return nil
@@ -140,7 +155,7 @@ func mainListParts(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(res)
+	defer drainAndClose(res)
 	// Spin scanBar
 	scanBar(message)
 	// Verify the response.
@@ -61,7 +61,17 @@ $ s3verify --access YOUR_ACCESS_KEY --secret YOUR_SECRET_KEY --url https://s3.am
 type APItest struct {
 	Test     func(ServerConfig, int) bool
 	Extended bool // Extended tests will only be invoked at the users request.
+	Strict   bool // Strict tests will only be invoked when --strict is set.
 	Critical bool // Tests marked critical must pass before more tests can be run.
+	Mutating bool // Tests that write to or delete from the server are never run with --read-only.
+
+	// The feature area the time of the test is summarized under, e.g. areaObject.
+	Area string
+
+	// Optional hooks run immediately before and after Test. A failing Setup fails the test
+	// without running it, Teardown runs whenever Setup succeeded even if Test failed.
+	Setup    func(ServerConfig) error
+	Teardown func(ServerConfig) error
 }
 
 func commandNotFound(ctx *cli.Context, command string) {
@@ -84,11 +94,10 @@ func registerApp() *cli.App {
 
 // makeConfigFromCtx - parse the passed context to create a new config.
 func makeConfigFromCtx(ctx *cli.Context) (*ServerConfig, error) {
-	if ctx.GlobalString("access") != "" &&
-		ctx.GlobalString("secret") != "" &&
+	hasKeys := ctx.GlobalString("access") != "" && ctx.GlobalString("secret") != ""
+	if (hasKeys || ctx.GlobalString("credential-process") != "") &&
 		ctx.GlobalString("url") != "" {
-		config := newServerConfig(ctx)
-		return config, nil
+		return newServerConfig(ctx)
 	}
 	// If config cannot be created successfully show help and exit immediately.
 	return nil, fmt.Errorf("Unable to create config.")
@@ -102,76 +111,281 @@ func callAllAPIs(ctx *cli.Context) {
 		// Could not create a config. Exit immediately.
 		cli.ShowAppHelpAndExit(ctx, 1)
 	}
+	// Machine readable output replaces the progress bar and the line printed per test.
+	if config.OutputFormat != outputHuman {
+		report, err := newReporter(config.OutputFormat)
+		if err != nil {
+			console.Fatalln(err)
+		}
+		globalReporter = report
+		scanBar = func(string) {}
+	}
+	// Quiet runs only print the summary.
+	if globalVerbosity < verbosityNormal {
+		scanBar = func(string) {}
+	}
+	// Finish the results once the run is over, whichever way it ends, so machine readable output is always written.
+	runFinalizers = append(runFinalizers, func() {
+		globalReporter.finish()
+	})
+	// A read-only run must not be combined with anything that writes.
+	if ctx.GlobalString("read-only") != "" {
+		for _, flag := range []string{"prepare", "clean", "id", "selfcheck", "reset-hook", "replay", "probe-max-size", "cleanup", "cleanup-only", "manifest"} {
+			if ctx.GlobalIsSet(flag) {
+				fatalRun(fmt.Sprintf("--read-only can not be used with --%s.", flag))
+			}
+		}
+	}
+	// A dry run only prints requests, anything working on the server directly can not be combined with it.
+	if config.DryRun {
+		for _, flag := range []string{"prepare", "clean", "id", "selfcheck", "reset-hook", "replay", "probe-max-size", "cleanup-only", "benchmark", "read-only"} {
+			if ctx.GlobalIsSet(flag) {
+				fatalRun(fmt.Sprintf("--dry-run can not be used with --%s.", flag))
+			}
+		}
+	}
+	// Write the metrics of the run once it is over if asked to, whichever way it ends.
+	if globalMetrics != nil {
+		recorder := &testRecorder{}
+		globalReporter = metricsReporter{reporter: globalReporter, recorder: recorder}
+		runFinalizers = append(runFinalizers, func() {
+			if err := globalMetrics.write(recorder.records); err != nil {
+				globalReporter.info(fmt.Sprintf("Unable to write metrics to %v: %v", globalMetrics.path, err))
+			}
+		})
+	}
+	// Warn about a server clock far off the local one once the run is over, whichever way it ends.
+	runFinalizers = append(runFinalizers, func() {
+		if warning := globalDateSkew.warning(); warning != "" {
+			globalReporter.info(warning)
+		}
+	})
+	defer finishRun()
+	// Nothing is verified in a dry run, the server is never contacted.
+	if config.DryRun {
+		globalReporter = dryRunReporter{globalReporter}
+	}
 	// Test that the given endpoint is reachable with a simple GET request.
-	if err := verifyHostReachable(config.Endpoint, config.Region); err != nil {
+	if err := verifyHostReachable(config.Endpoint, config.Region, config.Client.Transport); err != nil {
 		// If the provided endpoint is unreachable error out instantly.
-		console.Fatalln(err)
+		fatalRun(err)
+	}
+	// Report on OCSP stapling if asked for, a failure here does not stop the run.
+	if ctx.GlobalBool("check-ocsp") {
+		report, err := checkOCSPStaple(*config)
+		if err != nil {
+			console.Errorln(err)
+		} else {
+			globalReporter.info(report)
+		}
+	}
+	// If a reset hook was given, reset the server state before anything is run.
+	if hookURL := ctx.GlobalString("reset-hook"); hookURL != "" {
+		if err := callResetHook(hookURL); err != nil {
+			fatalRun(err)
+		}
 	}
 	// Determine whether or not extended tests will be run.
 	testExtended := ctx.GlobalBool("extended")
+	// If only a self-check is asked for run it and exit.
+	if ctx.GlobalBool("selfcheck") {
+		if !mainSelfCheck(*config) {
+			fatalRun("Self-check failed, fix the configuration before running s3verify.")
+		}
+		globalReporter.info("Self-check passed.")
+		return
+	}
+	// If a benchmark is asked for run it instead of the tests.
+	if mode := ctx.GlobalString("benchmark"); mode != "" {
+		bench := benchmarkConfig{
+			mode:        mode,
+			duration:    ctx.GlobalDuration("duration"),
+			concurrency: ctx.GlobalInt("concurrency"),
+			size:        int64(ctx.GlobalInt("size")),
+		}
+		if err := mainBenchmark(*config, bench); err != nil {
+			fatalRun(err)
+		}
+		return
+	}
+	// If the object size limit is to be probed do that instead of running the tests.
+	if ctx.GlobalBool("probe-max-size") {
+		if err := mainProbeMaxSize(*config, ctx.GlobalInt64("probe-ceiling")); err != nil {
+			fatalRun(err)
+		}
+		return
+	}
+	// If a HAR file is given replay it instead of running the tests.
+	if harPath := ctx.GlobalString("replay"); harPath != "" {
+		if !mainReplayHAR(*config, harPath) {
+			fatalRun("Replay failed.")
+		}
+		return
+	}
+	// If a read-only run is asked for, use existing objects and never write.
+	if target := ctx.GlobalString("read-only"); target != "" {
+		bucketName, prefix := splitReadOnlyTarget(target)
+		if err := prepareReadOnly(*config, bucketName, prefix); err != nil {
+			fatalRun(err)
+		}
+		globalReporter.info(fmt.Sprintf("S3verify running read-only tests on %d objects in %s.", s3verifyObjects.len(), bucketName))
+		if !runReadOnlyTests(*config, testExtended) {
+			exitRun(1)
+		}
+		return
+	}
 	// If a test environment is asked for prepare it now.
 	if ctx.GlobalBool("prepare") {
-		// Create a prepared testing environment with 1 bucket and 1001 objects.
+		// Create a prepared testing environment with 1 bucket and --object-count objects.
 		_, err := mainPrepareS3Verify(*config)
 		if err != nil {
-			console.Fatalln(err)
+			fatalRun(err)
 		}
-		console.Printf("Please run: S3_URL=%s S3_ACCESS=%s S3_SECRET=%s s3verify -id %s", config.Endpoint, config.Access, config.Secret, globalSuffix)
+		globalReporter.info(fmt.Sprintf("Please run: S3_URL=%s S3_ACCESS=%s S3_SECRET=%s s3verify -id %s", config.Endpoint, config.Access, config.Secret, globalSuffix))
 	} else if ctx.GlobalString("clean") != "" { // Clean any previously --prepare(d) tests up.
 		// Retrieve the bucket to be cleaned up.
 		bucketName := "s3verify-" + ctx.GlobalString("clean")
 		if err := cleanS3verify(*config, bucketName); err != nil {
-			console.Fatalln(err)
+			fatalRun(err)
+		}
+	} else if ctx.GlobalBool("cleanup-only") { // Remove what an earlier run with the same --id left behind.
+		if ctx.GlobalString("id") == "" && ctx.GlobalString("manifest") == "" {
+			fatalRun("--cleanup-only needs the --id or the --manifest of the run to clean up.")
+		}
+		if !mainCleanupOnly(*config, globalSuffix, ctx.GlobalString("manifest")) {
+			exitRun(1)
 		}
 	} else if ctx.GlobalString("id") != "" { // If an id is provided assume that this is an already prepared bucket and use it as such.
 		bucketName := "s3verify-" + globalSuffix
-		console.Printf("S3verify attempting to use %s to test AWS S3 V4 signature compatibility.", bucketName)
+		globalReporter.info(fmt.Sprintf("S3verify attempting to use %s to test AWS S3 V4 signature compatibility.", bucketName))
 		if err := validateBucket(*config, bucketName); err != nil {
-			console.Fatalln(err)
+			fatalRun(err)
+		}
+		if !runPreparedTests(*config, testExtended) {
+			exitRun(1)
 		}
-		runPreparedTests(*config, testExtended)
 	} else {
 		// If the user does not use --prepare flag then just run all non preparedTests.
-		runUnPreparedTests(*config, testExtended)
+		if !runUnPreparedTests(*config, testExtended) {
+			exitRun(1)
+		}
 	}
 }
 
 // runUnPreparedTests - run all tests if --prepare was not used.
-func runUnPreparedTests(config ServerConfig, testExtended bool) {
-	runTests(config, unpreparedTests, testExtended)
+func runUnPreparedTests(config ServerConfig, testExtended bool) bool {
+	return runTests(config, unpreparedTests, testExtended)
 }
 
 // runPreparedTests - run all previously prepared tests.
-func runPreparedTests(config ServerConfig, testExtended bool) {
-	runTests(config, preparedTests, testExtended)
+func runPreparedTests(config ServerConfig, testExtended bool) bool {
+	return runTests(config, preparedTests, testExtended)
+}
+
+// runReadOnlyTests - run only the tests that do not write, on objects that already exist.
+func runReadOnlyTests(config ServerConfig, testExtended bool) bool {
+	return runTests(config, readOnlyTests, testExtended)
 }
 
-// runTests - run all provided tests.
-func runTests(config ServerConfig, tests []APItest, testExtended bool) {
+// runTests - run all provided tests. It returns false if a critical test failed or the upload budget ran out
+// and the run was cut short, the cleanup is done either way.
+func runTests(config ServerConfig, tests []APItest, testExtended bool) bool {
+	aborted := false
 	count := 1
 	for _, test := range tests {
+		// Stop before the next test if the last one ran out of upload budget.
+		if globalUploadBudget.isExceeded() {
+			globalReporter.info("Aborting run: the --max-upload-bytes budget has been used up.")
+			aborted = true
+			break
+		}
+		if test.Strict && !globalStrict {
+			continue
+		}
+		if !globalTestFilter.selects(test) {
+			continue
+		}
+		// Refuse anything that writes when only reads are allowed.
+		if test.Mutating && globalReadOnly {
+			console.Errorln("Refusing to run a mutating test with --read-only.")
+			continue
+		}
 		if test.Extended {
 			// Only run extended tests if explicitly asked for.
 			if testExtended {
-				test.Test(config, count)
+				runTest(config, test, count)
 				count++
 			}
 		} else {
-			if !test.Test(config, count) && test.Critical {
-				// If the test failed and it was critical exit immediately.
-				os.Exit(1)
+			if !runTest(config, test, count) && test.Critical && !config.DryRun {
+				// If the test failed and it was critical no further test can run, unless nothing was sent.
+				globalReporter.info("Aborting run: a critical test failed.")
+				aborted = true
+				break
 			}
 			count++
 		}
 	}
+	// Remove everything the run created before the results are finished.
+	if globalCleanup {
+		mainCleanup(config)
+	}
+	if globalUploadBudget.limit > 0 {
+		globalReporter.info(globalUploadBudget.String())
+	}
+	return !aborted
+}
+
+// runFinalizers - what has to be done once the run is over however it ends, e.g. writing its metrics.
+// They run in reverse order of registration.
+var runFinalizers []func()
+
+// finishRun - run the finalizers, each only once.
+func finishRun() {
+	for i := len(runFinalizers) - 1; i >= 0; i-- {
+		runFinalizers[i]()
+	}
+	runFinalizers = nil
+}
+
+// exitRun - finish the run and exit with code. os.Exit skips deferred calls, the run would be left unfinished.
+func exitRun(code int) {
+	finishRun()
+	os.Exit(code)
+}
+
+// fatalRun - print data as an error, finish the run and exit.
+func fatalRun(data ...interface{}) {
+	console.Errorln(data...)
+	exitRun(1)
+}
+
+// runTest - run a single test surrounded by its Setup and Teardown hooks. A failing Setup is reported
+// as the result of the test, a failing Teardown fails the result the test already reported.
+func runTest(config ServerConfig, test APItest, count int) (passed bool) {
+	globalReporter.start(test.Area)
+	if test.Setup != nil {
+		if err := test.Setup(config); err != nil {
+			printMessage(fmt.Sprintf("[%02d/%d] %s (Setup):", count, globalTotalNumTest, testName(test)), err)
+			return false
+		}
+	}
+	if test.Teardown != nil {
+		defer func() {
+			if err := test.Teardown(config); err != nil {
+				globalReporter.fail(fmt.Errorf("Teardown of %s failed: %w", testName(test), err))
+				passed = false
+			}
+		}()
+	}
+	return test.Test(config, count)
 }
 
 // main - Set up and run the app.
 func main() {
 	app := registerApp()
 	app.Before = func(ctx *cli.Context) error {
-		setGlobalsFromContext(ctx)
-		return nil
+		return setGlobalsFromContext(ctx)
 	}
 	app.RunAndExitOnError()
 }
@@ -0,0 +1,130 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// hookedTestPasses - whether mainHookedTest passes, and the number of times it ran.
+var (
+	hookedTestPasses bool
+	hookedTestRuns   int
+)
+
+// mainHookedTest - a test that reports like every other test and passes if hookedTestPasses is set.
+func mainHookedTest(config ServerConfig, curTest int) bool {
+	hookedTestRuns++
+	message := fmt.Sprintf("[%02d/%d] Hooked Test:", curTest, globalTotalNumTest)
+	if !hookedTestPasses {
+		printMessage(message, fmt.Errorf("Hooked Test Failed"))
+		return false
+	}
+	printMessage(message, nil)
+	return true
+}
+
+// Test that Teardown runs whenever Setup succeeded, that hook failures are reported under the name of
+// the test and that a test only ever has one result.
+func TestRunTestHooks(t *testing.T) {
+	failing := func(ServerConfig) error { return fmt.Errorf("hook failed") }
+	passing := func(ServerConfig) error { return nil }
+	testCases := []struct {
+		name         string
+		testPasses   bool
+		setupErr     bool
+		teardownErr  bool
+		passed       bool
+		testRuns     int
+		teardownRuns int
+		recordName   string
+		recordError  string
+	}{
+		{"All pass", true, false, false, true, 1, 1, "Hooked Test", ""},
+		{"Test fails", false, false, false, false, 1, 1, "Hooked Test", "Hooked Test Failed"},
+		{"Setup fails", true, true, false, false, 0, 0, "HookedTest (Setup)", "hook failed"},
+		{"Teardown fails", true, false, true, false, 1, 1, "Hooked Test", "Teardown of HookedTest failed: hook failed"},
+		{"Test and Teardown fail", false, false, true, false, 1, 1, "Hooked Test", "Hooked Test Failed\nTeardown of HookedTest failed: hook failed"},
+	}
+	for _, testCase := range testCases {
+		newTestConfig("http://127.0.0.1")
+		reporter := globalReporter.(*silentReporter)
+		hookedTestPasses, hookedTestRuns = testCase.testPasses, 0
+		teardownRuns := 0
+		test := APItest{Test: mainHookedTest, Area: areaOther, Setup: passing}
+		if testCase.setupErr {
+			test.Setup = failing
+		}
+		test.Teardown = func(config ServerConfig) error {
+			teardownRuns++
+			if testCase.teardownErr {
+				return failing(config)
+			}
+			return passing(config)
+		}
+		if passed := runTest(ServerConfig{}, test, 1); passed != testCase.passed {
+			t.Errorf("%s: Expected passed to be %v, got %v", testCase.name, testCase.passed, passed)
+		}
+		if hookedTestRuns != testCase.testRuns {
+			t.Errorf("%s: Expected the test to run %d times, got %d", testCase.name, testCase.testRuns, hookedTestRuns)
+		}
+		if teardownRuns != testCase.teardownRuns {
+			t.Errorf("%s: Expected Teardown to run %d times, got %d", testCase.name, testCase.teardownRuns, teardownRuns)
+		}
+		if len(reporter.records) != 1 {
+			t.Fatalf("%s: Expected a single result, got %d", testCase.name, len(reporter.records))
+		}
+		record := reporter.records[0]
+		if record.TestName != testCase.recordName {
+			t.Errorf("%s: Expected the result of %q, got %q", testCase.name, testCase.recordName, record.TestName)
+		}
+		if record.Error != testCase.recordError {
+			t.Errorf("%s: Expected error %q, got %q", testCase.name, testCase.recordError, record.Error)
+		}
+		if failed := record.Status == "failed"; failed == testCase.passed {
+			t.Errorf("%s: Expected status failed to be %v, got %s", testCase.name, !testCase.passed, record.Status)
+		}
+		if summary := reporter.summary(); !strings.HasPrefix(summary, "Tests: 1,") {
+			t.Errorf("%s: Expected the summary to count one test, got %q", testCase.name, summary)
+		}
+	}
+}
+
+// Test that a run cut short by the upload budget reports it was aborted, so it exits non-zero.
+func TestRunTestsBudgetAborts(t *testing.T) {
+	newTestConfig("http://127.0.0.1")
+	globalUploadBudget = newUploadBudget(1)
+	defer func() { globalUploadBudget = newUploadBudget(0) }()
+	hookedTestPasses, hookedTestRuns = true, 0
+	test := APItest{
+		Test: mainHookedTest,
+		Area: areaOther,
+		// Uses up the budget, the test after it must not run.
+		Setup: func(ServerConfig) error {
+			globalUploadBudget.reserve(2)
+			return nil
+		},
+	}
+	if runTests(ServerConfig{}, []APItest{test, test}, false) {
+		t.Error("Expected the run to be reported as aborted")
+	}
+	if hookedTestRuns != 1 {
+		t.Errorf("Expected only the first test to run, got %d runs", hookedTestRuns)
+	}
+}
@@ -0,0 +1,211 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// xmlOperation - an API that takes an XML request body, described well enough to send it a malformed one.
+type xmlOperation struct {
+	name        string
+	method      string
+	subresource string // The query parameter selecting the API on the bucket or object.
+	// prepare - optionally create what the request needs, returning the object and query values
+	// to address it with and a cleanup to run afterwards.
+	prepare func(config ServerConfig, bucketName string) (objectName string, queryValues url.Values, cleanup func() error, err error)
+	// optional - the API may not be implemented, in which case NotImplemented is not a failure.
+	optional bool
+}
+
+// xmlOperations - every API s3verify knows of that takes an XML body.
+var xmlOperations = []xmlOperation{
+	{name: "DeleteObjects", method: "POST", subresource: "delete"},
+	{name: "PutBucketVersioning", method: "PUT", subresource: "versioning"},
+	{name: "PutBucketWebsite", method: "PUT", subresource: "website", optional: true},
+	{name: "PutBucketEncryption", method: "PUT", subresource: "encryption", optional: true},
+	{name: "CompleteMultipartUpload", method: "POST", prepare: prepareMalformedComplete},
+}
+
+// malformedXMLBodies - bodies that no XML API can accept.
+var malformedXMLBodies = []string{
+	"s3verify: this is not XML",
+	"<?xml version=\"1.0\" encoding=\"UTF-8\"?><Root><Element>truncated",
+	"<Root><Element></Other></Root>",
+}
+
+// prepareMalformedComplete - initiate the upload a malformed CompleteMultipartUpload is sent to.
+func prepareMalformedComplete(config ServerConfig, bucketName string) (string, url.Values, func() error, error) {
+	objectName := "s3verify/malformed-xml/complete"
+	uploadID, err := initiateMultipartUpload(config, bucketName, objectName)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	urlValues := make(url.Values)
+	urlValues.Set("uploadId", uploadID)
+	cleanup := func() error {
+		return abortMultipartUpload(config, bucketName, objectName, uploadID)
+	}
+	return objectName, urlValues, cleanup, nil
+}
+
+// newMalformedXMLReq - Create a new HTTP request sending body to an XML API.
+func newMalformedXMLReq(bucketName, objectName string, queryValues url.Values, body []byte) (Request, error) {
+	// malformedXMLReq - a new HTTP request with a malformed XML body.
+	var malformedXMLReq = Request{
+		customHeader: http.Header{},
+	}
+
+	// Set the bucketName, objectName and query values.
+	malformedXMLReq.bucketName = bucketName
+	malformedXMLReq.objectName = objectName
+	malformedXMLReq.queryValues = queryValues
+
+	reader := bytes.NewReader(body)
+	md5Sum, sha256Sum, contentLength, err := computeHash(reader)
+	if err != nil {
+		return Request{}, err
+	}
+
+	// Set the body, header and content length. A correct Content-MD5 keeps the body itself the only problem.
+	malformedXMLReq.contentBody = reader
+	malformedXMLReq.contentLength = contentLength
+	malformedXMLReq.customHeader.Set("Content-MD5", base64.StdEncoding.EncodeToString(md5Sum))
+	malformedXMLReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
+	malformedXMLReq.customHeader.Set("User-Agent", appUserAgent)
+
+	return malformedXMLReq, nil
+}
+
+// malformedXMLVerify - verify a malformed body was rejected with 400 MalformedXML.
+func malformedXMLVerify(res *http.Response) error {
+	if err := verifyStatusMalformedXML(res.StatusCode); err != nil {
+		return err
+	}
+	if err := verifyHeaderMalformedXML(res.Header); err != nil {
+		return err
+	}
+	if err := verifyBodyMalformedXML(res.Body); err != nil {
+		return err
+	}
+	return nil
+}
+
+// verifyStatusMalformedXML - verify the request was rejected as the client's fault.
+func verifyStatusMalformedXML(respStatusCode int) error {
+	if respStatusCode != http.StatusBadRequest {
+		err := fmt.Errorf("Unexpected Status Received: wanted %v, got %v", http.StatusBadRequest, respStatusCode)
+		return err
+	}
+	return nil
+}
+
+// verifyHeaderMalformedXML - verify the headers returned are standard.
+func verifyHeaderMalformedXML(header http.Header) error {
+	if err := verifyStandardHeaders(header); err != nil {
+		return err
+	}
+	return nil
+}
+
+// verifyBodyMalformedXML - verify the error returned is MalformedXML.
+func verifyBodyMalformedXML(resBody io.Reader) error {
+	errResponse := ErrorResponse{}
+	if err := xmlDecoder(resBody, &errResponse); err != nil {
+		return err
+	}
+	if errResponse.Code != "MalformedXML" {
+		err := fmt.Errorf("Unexpected Error Response: wanted MalformedXML, got %v", errResponse.Code)
+		return err
+	}
+	return nil
+}
+
+// sendMalformedXML - send body to the operation and verify it is rejected. Returns whether the
+// operation is implemented at all, an optional one that is not is skipped rather than failed.
+func sendMalformedXML(config ServerConfig, op xmlOperation, bucketName string, body []byte) (bool, error) {
+	objectName := ""
+	urlValues := make(url.Values)
+	if op.subresource != "" {
+		urlValues.Set(op.subresource, "")
+	}
+	if op.prepare != nil {
+		preparedObject, preparedValues, cleanup, err := op.prepare(config, bucketName)
+		if err != nil {
+			return true, err
+		}
+		defer cleanup()
+		objectName = preparedObject
+		for k, v := range preparedValues {
+			urlValues[k] = v
+		}
+	}
+	req, err := newMalformedXMLReq(bucketName, objectName, urlValues, body)
+	if err != nil {
+		return true, err
+	}
+	res, err := config.execRequest(op.method, req)
+	if err != nil {
+		return true, err
+	}
+	defer drainAndClose(res)
+	if op.optional && isNotImplemented(res) {
+		return false, nil
+	}
+	if err := malformedXMLVerify(res); err != nil {
+		return true, fmt.Errorf("%s with body %q: %v", op.name, body, err)
+	}
+	return true, nil
+}
+
+// mainMalformedXML - send malformed XML to every API that takes an XML body and verify each
+// rejects it with 400 MalformedXML rather than failing with a 5xx or accepting it.
+func mainMalformedXML(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Malformed XML:", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	skipped := []string{}
+	for _, op := range xmlOperations {
+		for _, body := range malformedXMLBodies {
+			// Spin scanBar
+			scanBar(message)
+			implemented, err := sendMalformedXML(config, op, bucketName, []byte(body))
+			if err != nil {
+				printMessage(message, err)
+				return false
+			}
+			if !implemented {
+				skipped = append(skipped, op.name)
+				break
+			}
+		}
+	}
+	// Test passed.
+	printMessage(message, nil)
+	if len(skipped) > 0 {
+		printDetail("Not implemented: " + strings.Join(skipped, ", "))
+	}
+	return true
+}
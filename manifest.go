@@ -0,0 +1,150 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// The kinds of artifact a manifest entry can describe.
+const (
+	manifestBucket = "bucket"
+	manifestObject = "object"
+)
+
+// manifestEntry - one bucket or object created by the run.
+type manifestEntry struct {
+	Time          time.Time `json:"time"`
+	Kind          string    `json:"kind"`
+	Bucket        string    `json:"bucket"`
+	Key           string    `json:"key,omitempty"`
+	Size          *int64    `json:"size,omitempty"` // Unknown for copies and completed multipart uploads.
+	ETag          string    `json:"etag,omitempty"`
+	ContentMD5    string    `json:"contentMD5,omitempty"`
+	ContentSHA256 string    `json:"contentSHA256,omitempty"`
+	VersionID     string    `json:"versionId,omitempty"`
+	StorageClass  string    `json:"storageClass,omitempty"`
+	CopySource    string    `json:"copySource,omitempty"`
+}
+
+// manifest - writes a manifestEntry per line for every bucket and object the run creates.
+// Every entry is written as soon as the server confirms it so an interrupted run still leaves a record.
+type manifest struct {
+	mutex sync.Mutex
+	file  *os.File // No manifest is written if nil.
+}
+
+// newManifest - create a manifest writing to path. An empty path records nothing.
+func newManifest(path string) (*manifest, error) {
+	if path == "" {
+		return &manifest{}, nil
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &manifest{file: file}, nil
+}
+
+// newManifestEntry - the entry describing what customReq created, or nil if it created nothing.
+func newManifestEntry(method string, customReq Request, res *http.Response) *manifestEntry {
+	if res.StatusCode != http.StatusOK || customReq.bucketName == "" {
+		return nil
+	}
+	entry := &manifestEntry{
+		Time:   time.Now().UTC(),
+		Bucket: customReq.bucketName,
+		Key:    customReq.objectName,
+	}
+	switch {
+	case method == "PUT" && customReq.objectName == "" && len(customReq.queryValues) == 0:
+		// Bucket configuration is set with a query parameter, only a plain PUT creates the bucket.
+		entry.Kind = manifestBucket
+		return entry
+	case method == "PUT" && customReq.objectName != "" && len(customReq.queryValues) == 0:
+		entry.Kind = manifestObject
+		entry.CopySource = customReq.customHeader.Get("x-amz-copy-source")
+		if entry.CopySource == "" && customReq.contentLength >= 0 && !customReq.chunked {
+			size := customReq.contentLength
+			entry.Size = &size
+		}
+	case method == "POST" && customReq.objectName != "" && customReq.queryValues.Get("uploadId") != "":
+		// CompleteMultipartUpload creates the object, its ETag is only in the body.
+		entry.Kind = manifestObject
+	default:
+		return nil
+	}
+	entry.ETag = res.Header.Get("ETag")
+	entry.ContentMD5 = customReq.customHeader.Get("Content-MD5")
+	entry.ContentSHA256 = customReq.customHeader.Get("X-Amz-Content-Sha256")
+	entry.VersionID = res.Header.Get("x-amz-version-id")
+	entry.StorageClass = customReq.customHeader.Get("x-amz-storage-class")
+	return entry
+}
+
+// record - add what customReq created to the manifest, if anything.
+func (m *manifest) record(method string, customReq Request, res *http.Response) {
+	if m == nil || m.file == nil {
+		return
+	}
+	entry := newManifestEntry(method, customReq, res)
+	if entry == nil {
+		return
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	// A manifest that can not be written must not fail the test that created the object.
+	m.file.Write(append(line, '\n'))
+}
+
+// readManifest - the buckets and the keys in each recorded in the manifest at path.
+// Bucket names are returned in the order they were first recorded.
+func readManifest(path string) ([]string, map[string][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	bucketNames := []string{}
+	keys := make(map[string][]string)
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		entry := manifestEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// The last line of an interrupted run may be incomplete.
+			continue
+		}
+		if !seen[entry.Bucket] {
+			seen[entry.Bucket] = true
+			bucketNames = append(bucketNames, entry.Bucket)
+		}
+		if entry.Kind == manifestObject {
+			keys[entry.Bucket] = append(keys[entry.Bucket], entry.Key)
+		}
+	}
+	return bucketNames, keys, scanner.Err()
+}
@@ -0,0 +1,188 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newManifestServer - a server holding buckets and objects that supports what a run records and
+// what a cleanup sweeps. Every DELETE is recorded in deleted as its path.
+func newManifestServer(deleted *[]string) *httptest.Server {
+	var mutex sync.Mutex
+	buckets := map[string]bool{}
+	objects := map[string]bool{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		path := strings.TrimSuffix(r.URL.Path, "/")
+		isBucket := strings.Count(strings.Trim(path, "/"), "/") == 0
+		query := r.URL.Query()
+		_, versions := query["versions"]
+		_, uploads := query["uploads"]
+		switch {
+		case r.Method == "GET" && path == "":
+			names := ""
+			for name := range buckets {
+				names += fmt.Sprintf("<Bucket><Name>%s</Name></Bucket>", strings.TrimPrefix(name, "/"))
+			}
+			writeS3Headers(w)
+			fmt.Fprintf(w, `<ListAllMyBucketsResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Buckets>%s</Buckets></ListAllMyBucketsResult>`, names)
+		case r.Method == "PUT" && isBucket:
+			buckets[path] = true
+			writeS3Headers(w)
+			w.Header().Set("Location", path)
+		case r.Method == "PUT":
+			ioutil.ReadAll(r.Body)
+			objects[path] = true
+			writeS3Headers(w)
+			w.Header().Set("ETag", "\"s3verify\"")
+		case r.Method == "HEAD" && !objects[path]:
+			writeS3Headers(w)
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == "GET" && versions:
+			writeS3Error(w, http.StatusNotImplemented, "NotImplemented", "")
+		case r.Method == "GET" && uploads:
+			writeS3Headers(w)
+			w.Write([]byte(`<ListMultipartUploadsResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></ListMultipartUploadsResult>`))
+		case r.Method == "GET" && isBucket:
+			contents := ""
+			for name := range objects {
+				if strings.HasPrefix(name, path+"/") {
+					contents += fmt.Sprintf("<Contents><Key>%s</Key></Contents>", strings.TrimPrefix(name, path+"/"))
+				}
+			}
+			writeS3Headers(w)
+			fmt.Fprintf(w, `<ListBucketResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><IsTruncated>false</IsTruncated>%s</ListBucketResult>`, contents)
+		case r.Method == "DELETE":
+			*deleted = append(*deleted, path)
+			delete(buckets, path)
+			delete(objects, path)
+			writeS3Headers(w)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeS3Error(w, http.StatusBadRequest, "InvalidRequest", "")
+		}
+	}))
+}
+
+// recordManifestRun - create bucketName and put objectName in it with every entry recorded in a manifest at path.
+func recordManifestRun(t *testing.T, config ServerConfig, path, bucketName, objectName string) {
+	var err error
+	globalManifest, err = newManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	putBucketReq, err := newPutBucketReq(config.Region, bucketName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := config.execRequest("PUT", putBucketReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainAndClose(res)
+	if _, err := putObject(config, bucketName, &ObjectInfo{Key: objectName, Body: []byte("s3verify")}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Test that every entry is in the manifest as soon as it is recorded, without the manifest being
+// closed or the run finishing, so an interrupted run still leaves a complete record.
+func TestManifestWrittenImmediately(t *testing.T) {
+	var deleted []string
+	server := newManifestServer(&deleted)
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "s3verify-manifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "manifest.jsonl")
+
+	config := newTestConfig(server.URL)
+	defer func() { globalManifest = nil }()
+	recordManifestRun(t, config, path, "s3verify-manifest", "s3verify/object")
+
+	// Read the file on its own while the manifest is still open.
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	entries := []manifestEntry{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		entry := manifestEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Expected every line to be a complete entry, got %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Kind != manifestBucket || entries[0].Bucket != "s3verify-manifest" {
+		t.Errorf("Expected the bucket first, got %+v", entries[0])
+	}
+	if entries[1].Kind != manifestObject || entries[1].Bucket != "s3verify-manifest" || entries[1].Key != "s3verify/object" {
+		t.Errorf("Expected the object second, got %+v", entries[1])
+	}
+	if entries[1].Size == nil || *entries[1].Size != int64(len("s3verify")) || entries[1].ETag != "\"s3verify\"" {
+		t.Errorf("Expected the size and ETag of the object, got %+v", entries[1])
+	}
+}
+
+// Test that --cleanup-only --manifest reads back what a run recorded and removes exactly that.
+func TestCleanupOnlyManifest(t *testing.T) {
+	var deleted []string
+	server := newManifestServer(&deleted)
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "s3verify-manifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "manifest.jsonl")
+
+	config := newTestConfig(server.URL)
+	defer func() { globalManifest = nil }()
+	recordManifestRun(t, config, path, "s3verify-manifest", "s3verify/object")
+	// A cleanup records nothing itself.
+	globalManifest = &manifest{}
+
+	if !mainCleanupOnly(config, "unused", path) {
+		t.Fatalf("Expected the cleanup to pass, got %v", globalReporter.(*silentReporter).records)
+	}
+	want := []string{"/s3verify-manifest/s3verify/object", "/s3verify-manifest"}
+	if strings.Join(deleted, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v to be removed, got %v", want, deleted)
+	}
+}
@@ -0,0 +1,341 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// requestDurationBuckets - the upper bounds in seconds of the request duration histogram.
+var requestDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// requestKey - the labels requests are counted by.
+type requestKey struct {
+	operation string // The S3 operation, e.g. PutObject.
+	code      string // The status code, or error if no response was received.
+}
+
+// durationKey - the labels request durations are observed by.
+type durationKey struct {
+	operation string
+}
+
+// durationHistogram - the durations of a set of requests, cumulative per bucket as Prometheus expects.
+type durationHistogram struct {
+	buckets []int64 // The number of requests that took at most requestDurationBuckets[i].
+	sum     float64 // Seconds.
+	count   int64
+}
+
+// runMetrics - counts and times every request sent, to be written out once the run is over.
+type runMetrics struct {
+	path      string // Where the metrics are written, in the Prometheus text format.
+	mutex     sync.Mutex
+	requests  map[requestKey]int64
+	durations map[durationKey]*durationHistogram
+}
+
+// newRunMetrics - metrics written to path once the run is over, nil if path is empty.
+func newRunMetrics(path string) *runMetrics {
+	if path == "" {
+		return nil
+	}
+	return &runMetrics{
+		path:      path,
+		requests:  make(map[requestKey]int64),
+		durations: make(map[durationKey]*durationHistogram),
+	}
+}
+
+// requestTarget - whether req addresses the service, a bucket or an object. Requests are path style.
+func requestTarget(req *http.Request) string {
+	path := strings.Trim(req.URL.Path, "/")
+	switch {
+	case path == "":
+		return "service"
+	case !strings.Contains(path, "/"):
+		return "bucket"
+	}
+	return "object"
+}
+
+// bucketSubresources - the query parameters selecting a bucket subresource, and the name of the
+// resource in the S3 operations on it. Checked in order, the first one present wins.
+var bucketSubresources = []struct {
+	query string
+	name  string
+}{
+	{"versioning", "BucketVersioning"},
+	{"policy", "BucketPolicy"},
+	{"lifecycle", "BucketLifecycleConfiguration"},
+	{"cors", "BucketCors"},
+	{"tagging", "BucketTagging"},
+	{"acl", "BucketAcl"},
+	{"location", "BucketLocation"},
+	{"object-lock", "ObjectLockConfiguration"},
+	{"website", "BucketWebsite"},
+	{"encryption", "BucketEncryption"},
+	{"notification", "BucketNotificationConfiguration"},
+	{"logging", "BucketLogging"},
+	{"replication", "BucketReplication"},
+	{"ownershipControls", "BucketOwnershipControls"},
+	{"publicAccessBlock", "PublicAccessBlock"},
+	{"accelerate", "BucketAccelerateConfiguration"},
+	{"requestPayment", "BucketRequestPayment"},
+}
+
+// objectSubresources - the query parameters selecting an object subresource, as bucketSubresources.
+var objectSubresources = []struct {
+	query string
+	name  string
+}{
+	{"tagging", "ObjectTagging"},
+	{"acl", "ObjectAcl"},
+	{"retention", "ObjectRetention"},
+	{"legal-hold", "ObjectLegalHold"},
+	{"attributes", "ObjectAttributes"},
+}
+
+// requestOperation - the S3 operation req performs, told apart by its method, target, query
+// subresource and headers such as x-amz-copy-source. The method is returned for anything unknown.
+func requestOperation(req *http.Request) string {
+	query := req.URL.Query()
+	_, hasUploadID := query["uploadId"]
+	isCopy := req.Header.Get("x-amz-copy-source") != ""
+	// prefix - Get, Put or Delete for the method, as the operations on subresources are named.
+	prefix := map[string]string{"GET": "Get", "PUT": "Put", "DELETE": "Delete"}[req.Method]
+	switch requestTarget(req) {
+	case "service":
+		if req.Method == "GET" {
+			return "ListBuckets"
+		}
+	case "bucket":
+		for _, subresource := range bucketSubresources {
+			if _, ok := query[subresource.query]; ok && prefix != "" {
+				return prefix + subresource.name
+			}
+		}
+		_, hasUploads := query["uploads"]
+		_, hasVersions := query["versions"]
+		_, hasDelete := query["delete"]
+		switch {
+		case req.Method == "GET" && hasUploads:
+			return "ListMultipartUploads"
+		case req.Method == "GET" && hasVersions:
+			return "ListObjectVersions"
+		case req.Method == "GET" && query.Get("list-type") == "2":
+			return "ListObjectsV2"
+		case req.Method == "GET":
+			return "ListObjects"
+		case req.Method == "HEAD":
+			return "HeadBucket"
+		case req.Method == "PUT":
+			return "CreateBucket"
+		case req.Method == "DELETE":
+			return "DeleteBucket"
+		case req.Method == "POST" && hasDelete:
+			return "DeleteObjects"
+		case req.Method == "POST":
+			return "PostObject"
+		}
+	case "object":
+		for _, subresource := range objectSubresources {
+			if _, ok := query[subresource.query]; ok && prefix != "" {
+				return prefix + subresource.name
+			}
+		}
+		_, hasUploads := query["uploads"]
+		switch {
+		case req.Method == "GET" && hasUploadID:
+			return "ListParts"
+		case req.Method == "GET":
+			return "GetObject"
+		case req.Method == "HEAD":
+			return "HeadObject"
+		case req.Method == "PUT" && hasUploadID && isCopy:
+			return "UploadPartCopy"
+		case req.Method == "PUT" && hasUploadID:
+			return "UploadPart"
+		case req.Method == "PUT" && isCopy:
+			return "CopyObject"
+		case req.Method == "PUT":
+			return "PutObject"
+		case req.Method == "DELETE" && hasUploadID:
+			return "AbortMultipartUpload"
+		case req.Method == "DELETE":
+			return "DeleteObject"
+		case req.Method == "POST" && hasUploads:
+			return "CreateMultipartUpload"
+		case req.Method == "POST" && hasUploadID:
+			return "CompleteMultipartUpload"
+		}
+	}
+	return req.Method
+}
+
+// observe - count req and the time until its response, or err, was received.
+func (m *runMetrics) observe(req *http.Request, res *http.Response, err error, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(res.StatusCode)
+	}
+	operation := requestOperation(req)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.requests[requestKey{operation: operation, code: code}]++
+	histogram, ok := m.durations[durationKey{operation: operation}]
+	if !ok {
+		histogram = &durationHistogram{buckets: make([]int64, len(requestDurationBuckets))}
+		m.durations[durationKey{operation: operation}] = histogram
+	}
+	seconds := elapsed.Seconds()
+	for i, bound := range requestDurationBuckets {
+		if seconds <= bound {
+			histogram.buckets[i]++
+		}
+	}
+	histogram.sum += seconds
+	histogram.count++
+}
+
+// escapeLabelValue - escape value for use between the quotes of a Prometheus label.
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// formatMetricFloat - a float the way Prometheus reads it.
+func formatMetricFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// format - every metric in the Prometheus text exposition format, series sorted for stable output.
+func (m *runMetrics) format(records []testRecord, finished time.Time) string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	var buf bytes.Buffer
+
+	requestKeys := []requestKey{}
+	for key := range m.requests {
+		requestKeys = append(requestKeys, key)
+	}
+	sort.Sort(requestKeysByLabels(requestKeys))
+	buf.WriteString("# HELP s3verify_requests_total Requests sent, by S3 operation and status code.\n")
+	buf.WriteString("# TYPE s3verify_requests_total counter\n")
+	for _, key := range requestKeys {
+		fmt.Fprintf(&buf, "s3verify_requests_total{operation=\"%s\",code=\"%s\"} %d\n",
+			escapeLabelValue(key.operation), key.code, m.requests[key])
+	}
+
+	durationKeys := []durationKey{}
+	for key := range m.durations {
+		durationKeys = append(durationKeys, key)
+	}
+	sort.Sort(durationKeysByLabels(durationKeys))
+	buf.WriteString("# HELP s3verify_request_duration_seconds Time until the response headers were received, by S3 operation.\n")
+	buf.WriteString("# TYPE s3verify_request_duration_seconds histogram\n")
+	for _, key := range durationKeys {
+		histogram := m.durations[key]
+		labels := fmt.Sprintf("operation=\"%s\"", escapeLabelValue(key.operation))
+		for i, bound := range requestDurationBuckets {
+			fmt.Fprintf(&buf, "s3verify_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n", labels, formatMetricFloat(bound), histogram.buckets[i])
+		}
+		fmt.Fprintf(&buf, "s3verify_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, histogram.count)
+		fmt.Fprintf(&buf, "s3verify_request_duration_seconds_sum{%s} %s\n", labels, formatMetricFloat(histogram.sum))
+		fmt.Fprintf(&buf, "s3verify_request_duration_seconds_count{%s} %d\n", labels, histogram.count)
+	}
+
+	results := map[string]int{"passed": 0, "failed": 0, "skipped": 0}
+	for _, record := range records {
+		results[record.Status]++
+	}
+	buf.WriteString("# HELP s3verify_tests Tests of the last run, by result.\n")
+	buf.WriteString("# TYPE s3verify_tests gauge\n")
+	for _, result := range []string{"failed", "passed", "skipped"} {
+		fmt.Fprintf(&buf, "s3verify_tests{result=\"%s\"} %d\n", result, results[result])
+	}
+	buf.WriteString("# HELP s3verify_last_run_timestamp_seconds When the last run finished.\n")
+	buf.WriteString("# TYPE s3verify_last_run_timestamp_seconds gauge\n")
+	fmt.Fprintf(&buf, "s3verify_last_run_timestamp_seconds %d\n", finished.Unix())
+	return buf.String()
+}
+
+// write - write the metrics to their path. The file is replaced in one step so a textfile
+// collector never reads it half written.
+func (m *runMetrics) write(records []testRecord) error {
+	tmpPath := m.path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, []byte(m.format(records, time.Now())), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, m.path)
+}
+
+// requestKeysByLabels - sorts request counts by operation and code.
+type requestKeysByLabels []requestKey
+
+func (k requestKeysByLabels) Len() int      { return len(k) }
+func (k requestKeysByLabels) Swap(i, j int) { k[i], k[j] = k[j], k[i] }
+func (k requestKeysByLabels) Less(i, j int) bool {
+	if k[i].operation != k[j].operation {
+		return k[i].operation < k[j].operation
+	}
+	return k[i].code < k[j].code
+}
+
+// durationKeysByLabels - sorts request durations by operation.
+type durationKeysByLabels []durationKey
+
+func (k durationKeysByLabels) Len() int           { return len(k) }
+func (k durationKeysByLabels) Swap(i, j int)      { k[i], k[j] = k[j], k[i] }
+func (k durationKeysByLabels) Less(i, j int) bool { return k[i].operation < k[j].operation }
+
+// metricsReporter - records the result of every test for the metrics written once the run is over.
+type metricsReporter struct {
+	reporter
+	recorder *testRecorder
+}
+
+func (r metricsReporter) start(area string) {
+	r.recorder.start(area)
+	r.reporter.start(area)
+}
+
+func (r metricsReporter) result(message string, err error) {
+	r.recorder.record(message, err)
+	r.reporter.result(message, err)
+}
+
+func (r metricsReporter) detail(detail string) {
+	r.recorder.addDetail(detail)
+	r.reporter.detail(detail)
+}
+
+func (r metricsReporter) fail(err error) {
+	r.recorder.failLast(err)
+	r.reporter.fail(err)
+}
@@ -0,0 +1,98 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test that requests are told apart by the S3 operation they perform.
+func TestRequestOperation(t *testing.T) {
+	testCases := []struct {
+		method    string
+		url       string
+		header    http.Header
+		operation string
+	}{
+		{"GET", "/", nil, "ListBuckets"},
+		{"PUT", "/bucket", nil, "CreateBucket"},
+		{"HEAD", "/bucket", nil, "HeadBucket"},
+		{"DELETE", "/bucket", nil, "DeleteBucket"},
+		{"GET", "/bucket", nil, "ListObjects"},
+		{"GET", "/bucket?list-type=2&prefix=s3verify", nil, "ListObjectsV2"},
+		{"GET", "/bucket?versions", nil, "ListObjectVersions"},
+		{"GET", "/bucket?uploads", nil, "ListMultipartUploads"},
+		{"POST", "/bucket?delete", nil, "DeleteObjects"},
+		{"GET", "/bucket?versioning", nil, "GetBucketVersioning"},
+		{"PUT", "/bucket?versioning", nil, "PutBucketVersioning"},
+		{"DELETE", "/bucket?policy", nil, "DeleteBucketPolicy"},
+		{"GET", "/bucket?location", nil, "GetBucketLocation"},
+		{"PUT", "/bucket?object-lock", nil, "PutObjectLockConfiguration"},
+		{"GET", "/bucket/object", nil, "GetObject"},
+		{"HEAD", "/bucket/object", nil, "HeadObject"},
+		{"PUT", "/bucket/object", nil, "PutObject"},
+		{"PUT", "/bucket/object", http.Header{"X-Amz-Copy-Source": {"bucket/source"}}, "CopyObject"},
+		{"DELETE", "/bucket/object?versionId=1", nil, "DeleteObject"},
+		{"GET", "/bucket/object?tagging", nil, "GetObjectTagging"},
+		{"PUT", "/bucket/object?retention&versionId=1", nil, "PutObjectRetention"},
+		{"GET", "/bucket/object?attributes", nil, "GetObjectAttributes"},
+		{"POST", "/bucket/object?uploads", nil, "CreateMultipartUpload"},
+		{"PUT", "/bucket/object?partNumber=1&uploadId=1", nil, "UploadPart"},
+		{"PUT", "/bucket/object?partNumber=1&uploadId=1", http.Header{"X-Amz-Copy-Source": {"bucket/source"}}, "UploadPartCopy"},
+		{"GET", "/bucket/object?uploadId=1", nil, "ListParts"},
+		{"POST", "/bucket/object?uploadId=1", nil, "CompleteMultipartUpload"},
+		{"DELETE", "/bucket/object?uploadId=1", nil, "AbortMultipartUpload"},
+		{"OPTIONS", "/bucket/object", nil, "OPTIONS"},
+	}
+	for i, testCase := range testCases {
+		req, err := http.NewRequest(testCase.method, "http://localhost:9000"+testCase.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range testCase.header {
+			req.Header[k] = v
+		}
+		if operation := requestOperation(req); operation != testCase.operation {
+			t.Errorf("Test %d: Expected %s %s to be %s, got %s", i+1, testCase.method, testCase.url, testCase.operation, operation)
+		}
+	}
+}
+
+// Test that requests are counted by operation in the written metrics.
+func TestRunMetricsFormat(t *testing.T) {
+	metrics := newRunMetrics("metrics.prom")
+	for _, url := range []string{"/bucket/object", "/bucket/object?partNumber=1&uploadId=1", "/bucket/object?partNumber=2&uploadId=1"} {
+		req, err := http.NewRequest("PUT", "http://localhost:9000"+url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		metrics.observe(req, &http.Response{StatusCode: http.StatusOK}, nil, time.Millisecond)
+	}
+	formatted := metrics.format(nil, time.Now())
+	for _, line := range []string{
+		`s3verify_requests_total{operation="PutObject",code="200"} 1`,
+		`s3verify_requests_total{operation="UploadPart",code="200"} 2`,
+		`s3verify_request_duration_seconds_count{operation="UploadPart"} 2`,
+	} {
+		if !strings.Contains(formatted, line+"\n") {
+			t.Errorf("Expected the metrics to contain %s, got:\n%s", line, formatted)
+		}
+	}
+}
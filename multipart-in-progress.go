@@ -0,0 +1,265 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	crand "crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// verifyBodyObjectNotFound - verify the body returned is a NoSuchKey error.
+func verifyBodyObjectNotFound(resBody io.Reader) error {
+	errResponse, err := parseErrorResponse(resBody)
+	if err != nil {
+		return err
+	}
+	if errResponse.Code != "NoSuchKey" {
+		err := fmt.Errorf("Unexpected Error Response: wanted NoSuchKey, got %v", errResponse.Code)
+		return err
+	}
+	return nil
+}
+
+// objectNotFoundVerify - verify that a GET on an object that should not be visible fails as expected.
+func objectNotFoundVerify(res *http.Response) error {
+	if err := verifyStatusGetObject(res.StatusCode, http.StatusNotFound); err != nil {
+		return err
+	}
+	if err := verifyBodyObjectNotFound(res.Body); err != nil {
+		return err
+	}
+	return nil
+}
+
+// verifyObjectListed - verify whether or not objectName is present in a ListObjects V1 response.
+func verifyObjectListed(res *http.Response, objectName string, shouldExist bool) error {
+	if err := verifyStatusListObjectsV1(res.StatusCode, http.StatusOK); err != nil {
+		return err
+	}
+	receivedList := listBucketResult{}
+	if err := xmlDecoder(res.Body, &receivedList); err != nil {
+		return err
+	}
+	found := false
+	for _, object := range receivedList.Contents {
+		if object.Key == objectName {
+			found = true
+			break
+		}
+	}
+	if found != shouldExist {
+		err := fmt.Errorf("Unexpected Listing for %s: wanted listed %v, got listed %v", objectName, shouldExist, found)
+		return err
+	}
+	return nil
+}
+
+// listObjectVisibility - list the bucket with objectName as prefix and verify its presence.
+func listObjectVisibility(config ServerConfig, bucketName, objectName string, shouldExist bool) error {
+	req, err := newListObjectsV1Req(bucketName, map[string]string{
+		"prefix": objectName,
+	})
+	if err != nil {
+		return err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	return verifyObjectListed(res, objectName, shouldExist)
+}
+
+// mainMultipartInProgressVisibility - verify that an incomplete multipart upload is not exposed as an object
+// to GET, HEAD or a listing until it is completed.
+func mainMultipartInProgressVisibility(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Multipart (In-Progress Visibility):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	// All multipart operations take place in the s3verify created buckets.
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key: "s3verify/multipart/in-progress",
+	}
+	// Initiate a new upload.
+	initiateReq, err := newInitiateMultipartUploadReq(bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	initiateRes, err := config.execRequest("POST", initiateReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(initiateRes)
+	uploadID, err := initiateMultipartUploadVerify(initiateRes, http.StatusOK)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	object.UploadID = uploadID
+	// Abort the upload if the test fails before completing it, so no upload is left in progress.
+	completed := false
+	defer func() {
+		if !completed {
+			abortMultipartUpload(config, bucketName, object.Key, object.UploadID)
+		}
+	}()
+	// Spin scanBar
+	scanBar(message)
+	// Upload a single part. The last part of an upload may be smaller than 5MB.
+	object.Body = make([]byte, 1024*1024)
+	if _, err := io.ReadFull(crand.Reader, object.Body); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	uploadPartReq, err := newUploadPartReq(bucketName, object.Key, object.UploadID, 1, object.Body)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	uploadPartRes, err := config.execRequest("PUT", uploadPartReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(uploadPartRes)
+	if err := uploadPartVerify(uploadPartRes, http.StatusOK, object.Body); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	complete := &completeMultipartUpload{
+		Parts: []completePart{
+			completePart{
+				PartNumber: 1,
+				ETag:       strings.Trim(uploadPartRes.Header.Get("ETag"), "\""),
+			},
+		},
+	}
+	// Spin scanBar
+	scanBar(message)
+	// The key must not be retrievable while the upload is in progress.
+	getReq, err := newGetObjectReq(bucketName, object.Key, nil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	getRes, err := config.execRequest("GET", getReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(getRes)
+	if err := objectNotFoundVerify(getRes); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Nor may HEAD find it.
+	headReq, err := newHeadObjectReq(bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	headRes, err := config.execRequest("HEAD", headReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(headRes)
+	if err := headObjectVerify(headRes, http.StatusNotFound); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// The key must not be listed while the upload is in progress.
+	if err := listObjectVisibility(config, bucketName, object.Key, false); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Complete the upload.
+	completeReq, err := newCompleteMultipartUploadReq(bucketName, object.Key, object.UploadID, complete)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	completeRes, err := config.execRequest("POST", completeReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(completeRes)
+	if err := completeMultipartUploadVerify(completeRes, http.StatusOK, ""); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	completed = true
+	// Spin scanBar
+	scanBar(message)
+	// Now the object must be retrievable in full.
+	getCompleteReq, err := newGetObjectReq(bucketName, object.Key, nil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	getCompleteRes, err := config.execRequest("GET", getCompleteReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(getCompleteRes)
+	if err := getObjectVerify(getCompleteRes, object.Body, http.StatusOK, nil); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// And it must now be listed.
+	if err := listObjectVisibility(config, bucketName, object.Key, true); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Remove the completed object so it does not interfere with future tests.
+	removeReq, err := newRemoveObjectReq(config, bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	removeRes, err := config.execRequest("DELETE", removeReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(removeRes)
+	if err := removeObjectVerify(removeRes, http.StatusNoContent); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
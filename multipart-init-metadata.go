@@ -0,0 +1,150 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	crand "crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// verifyHeaderInitMetadata - verify that the headers sent at initiate are returned for the completed object.
+func verifyHeaderInitMetadata(header, expectedHeader http.Header) error {
+	for k := range expectedHeader {
+		if header.Get(k) != expectedHeader.Get(k) {
+			err := fmt.Errorf("Unexpected Header Value Received for %s: wanted %s, got %s", k, expectedHeader.Get(k), header.Get(k))
+			return err
+		}
+	}
+	return nil
+}
+
+// mainMultipartInitMetadata - verify the Content-Type and metadata given at initiate are kept after completion.
+// Servers often build the completed object from the parts alone and drop what was given at initiate, so the
+// upload is made of more than one part and the headers are checked on both HEAD and GET.
+func mainMultipartInitMetadata(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Multipart (Initiate Metadata):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	// All multipart operations take place in the s3verify created buckets.
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/multipart/init-metadata",
+		Body: make([]byte, boundaryPartSize+1024*1024),
+	}
+	if _, err := io.ReadFull(crand.Reader, object.Body); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// The Content-Type and metadata of a multipart object can only be set when it is initiated.
+	initHeader := http.Header{}
+	initHeader.Set("Content-Type", "application/x-s3verify")
+	initHeader.Set("Cache-Control", "max-age=3600")
+	initHeader.Set("Content-Disposition", "attachment; filename=\"init-metadata\"")
+	initHeader.Set("X-Amz-Meta-S3verify-Test", "init-metadata")
+	initHeader.Set("X-Amz-Meta-S3verify-Parts", "2")
+	initiateReq, err := newInitiateMultipartUploadHeaderReq(bucketName, object.Key, initHeader)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	initiateRes, err := config.execRequest("POST", initiateReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(initiateRes)
+	uploadID, err := initiateMultipartUploadVerify(initiateRes, http.StatusOK)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	object.UploadID = uploadID
+	complete := &completeMultipartUpload{}
+	for i, part := range [][]byte{object.Body[:boundaryPartSize], object.Body[boundaryPartSize:]} {
+		// Spin scanBar
+		scanBar(message)
+		partETag, err := uploadPart(config, bucketName, object.Key, object.UploadID, i+1, part)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		complete.Parts = append(complete.Parts, completePart{
+			PartNumber: i + 1,
+			ETag:       partETag,
+		})
+	}
+	if _, err := completeMultipart(config, bucketName, object.Key, object.UploadID, complete); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// HEAD the completed object to check what was kept.
+	headReq, err := newHeadObjectReq(bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	headRes, err := config.execRequest("HEAD", headReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(headRes)
+	if err := headObjectVerify(headRes, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyHeaderInitMetadata(headRes.Header, initHeader); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// GET is served by a different path than HEAD on some servers, check it as well.
+	getReq, err := newGetObjectReq(bucketName, object.Key, nil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	getRes, err := config.execRequest("GET", getReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(getRes)
+	if err := getObjectVerify(getRes, object.Body, http.StatusOK, nil); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyHeaderInitMetadata(getRes.Header, initHeader); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Remove the completed object so it does not interfere with future tests.
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,140 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	crand "crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Size of every part but the last in the part boundary test, the smallest size S3 accepts for a non-final part.
+const boundaryPartSize = 5 * 1024 * 1024
+
+// boundaryRange - an inclusive byte range requested from the multipart object.
+type boundaryRange struct {
+	start int64
+	end   int64
+}
+
+// boundaryRanges - ranges straddling each seam between the given part sizes.
+func boundaryRanges(partSizes []int64) []boundaryRange {
+	var ranges []boundaryRange
+	var seam int64
+	for i, size := range partSizes[:len(partSizes)-1] {
+		seam += size
+		ranges = append(ranges,
+			// Last byte of one part through the first byte of the next.
+			boundaryRange{seam - 1, seam},
+			// A few bytes either side of the seam.
+			boundaryRange{seam - 16, seam + 15},
+			// Ending exactly on the last byte of a part.
+			boundaryRange{seam - 16, seam - 1},
+			// Starting exactly on the first byte of a part.
+			boundaryRange{seam, seam + 15},
+		)
+		if i+1 < len(partSizes)-1 {
+			// The whole of the next part plus one byte either side, crossing two seams.
+			ranges = append(ranges, boundaryRange{seam - 1, seam + partSizes[i+1]})
+		}
+	}
+	return ranges
+}
+
+// mainMultipartRangeBoundary - request ranges straddling the part boundaries of a multipart object
+// and verify the bytes returned match the uploaded content across each seam.
+func mainMultipartRangeBoundary(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Multipart (Range Across Parts):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	// All multipart operations take place in the s3verify created buckets.
+	bucketName := s3verifyBuckets[0].Name
+	partSizes := []int64{boundaryPartSize, boundaryPartSize, 1024 * 1024}
+	var objectSize int64
+	for _, size := range partSizes {
+		objectSize += size
+	}
+	object := &ObjectInfo{
+		Key:  "s3verify/multipart/range-boundary",
+		Body: make([]byte, objectSize),
+	}
+	if _, err := io.ReadFull(crand.Reader, object.Body); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	uploadID, err := initiateMultipartUpload(config, bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	object.UploadID = uploadID
+	complete := &completeMultipartUpload{}
+	var offset int64
+	for i, size := range partSizes {
+		// Spin scanBar
+		scanBar(message)
+		partNumber := i + 1
+		partETag, err := uploadPart(config, bucketName, object.Key, object.UploadID, partNumber, object.Body[offset:offset+size])
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		complete.Parts = append(complete.Parts, completePart{
+			PartNumber: partNumber,
+			ETag:       partETag,
+		})
+		offset += size
+	}
+	// Spin scanBar
+	scanBar(message)
+	if _, err := completeMultipart(config, bucketName, object.Key, object.UploadID, complete); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	for _, r := range boundaryRanges(partSizes) {
+		// Spin scanBar
+		scanBar(message)
+		req, err := newGetObjectRangeReq(bucketName, object.Key, r.start, r.end)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		res, err := config.execRequest("GET", req)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		err = getObjectVerify(res, object.Body[r.start:r.end+1], http.StatusPartialContent, nil)
+		drainAndClose(res)
+		if err != nil {
+			err = fmt.Errorf("Range bytes=%d-%d: %v", r.start, r.end, err)
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Remove the completed object so it does not interfere with future tests.
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,119 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Sizes of the parts of the object read back right after it is completed.
+var readAfterCompletePartSizes = []int{5 * 1024 * 1024, 1024 * 1024}
+
+// verifyCompletedObject - GET objectName and verify it holds body with the composite expectedETag.
+func verifyCompletedObject(config ServerConfig, bucketName, objectName string, body []byte, expectedETag string) error {
+	req, err := newGetObjectReq(bucketName, objectName, nil)
+	if err != nil {
+		return err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	if err := getObjectVerify(res, body, http.StatusOK, nil); err != nil {
+		return err
+	}
+	if eTag := strings.Trim(res.Header.Get("ETag"), "\""); eTag != expectedETag {
+		err := fmt.Errorf("Unexpected ETag Received: wanted %v, got %v", expectedETag, eTag)
+		return err
+	}
+	return nil
+}
+
+// mainMultipartReadAfterComplete - Test a multipart object can be read in full, with its composite ETag, as soon
+// as CompleteMultipartUpload returns. Eventually consistent servers are retried --list-retries times and the
+// delay until the object was readable is reported.
+func mainMultipartReadAfterComplete(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Multipart (Read After Complete):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	objectName := "s3verify/multipart/read-after-complete"
+	uploadID, err := initiateMultipartUpload(config, bucketName, objectName)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	seed := time.Now().UnixNano()
+	body := &bytes.Buffer{}
+	complete := &completeMultipartUpload{}
+	for i, size := range readAfterCompletePartSizes {
+		// Spin scanBar
+		scanBar(message)
+		partData := generatedBody(seed+int64(i), size)
+		partETag, err := uploadPart(config, bucketName, objectName, uploadID, i+1, partData)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		body.Write(partData)
+		complete.Parts = append(complete.Parts, completePart{PartNumber: i + 1, ETag: partETag})
+	}
+	expectedETag, err := completedETag(complete)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if _, err := completeMultipart(config, bucketName, objectName, uploadID, complete); err != nil {
+		abortMultipartUpload(config, bucketName, objectName, uploadID)
+		printMessage(message, err)
+		return false
+	}
+	completed := time.Now()
+	// Spin scanBar
+	scanBar(message)
+	attempts := 0
+	for range newRetryTimer(globalListRetries+1, time.Second, time.Second*30, MaxJitter, globalRandom) {
+		attempts++
+		if err = verifyCompletedObject(config, bucketName, objectName, body.Bytes(), expectedETag); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	delay := time.Since(completed)
+	// Spin scanBar
+	scanBar(message)
+	if err := removeObject(config, bucketName, objectName); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	if attempts > 1 {
+		printDetail(fmt.Sprintf("Warning: the completed object was only readable after %v and %d attempts", delay, attempts))
+	}
+	return true
+}
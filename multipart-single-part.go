@@ -0,0 +1,115 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"crypto/md5"
+	crand "crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// mainMultipartSinglePart - complete a multipart upload consisting of exactly one part and verify
+// the object is treated as a multipart object rather than a simple PUT.
+func mainMultipartSinglePart(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Multipart (Single Part):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	// All multipart operations take place in the s3verify created buckets.
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/multipart/single-part",
+		Body: make([]byte, 1024*1024),
+	}
+	if _, err := io.ReadFull(crand.Reader, object.Body); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	uploadID, err := initiateMultipartUpload(config, bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	object.UploadID = uploadID
+	// Spin scanBar
+	scanBar(message)
+	// Upload the one and only part.
+	partETag, err := uploadPart(config, bucketName, object.Key, object.UploadID, 1, object.Body)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	complete := &completeMultipartUpload{
+		Parts: []completePart{
+			completePart{
+				PartNumber: 1,
+				ETag:       partETag,
+			},
+		},
+	}
+	// Spin scanBar
+	scanBar(message)
+	result, err := completeMultipart(config, bucketName, object.Key, object.UploadID, complete)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// The ETag of a multipart object is never the plain MD5 of its content, even with one part.
+	partMD5 := md5.Sum(object.Body)
+	expectedETag := multipartETag([][]byte{partMD5[:]})
+	if etag := strings.Trim(result.ETag, "\""); etag != expectedETag {
+		err := fmt.Errorf("Unexpected ETag Received: wanted %v (simple PUT ETag would be %v), got %v", expectedETag, hex.EncodeToString(partMD5[:]), etag)
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// The completed object must return the same body and ETag.
+	getReq, err := newGetObjectReq(bucketName, object.Key, nil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	getRes, err := config.execRequest("GET", getReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(getRes)
+	if err := getObjectVerify(getRes, object.Body, http.StatusOK, nil); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if etag := strings.Trim(getRes.Header.Get("ETag"), "\""); etag != expectedETag {
+		err := fmt.Errorf("Unexpected ETag Received: wanted %v, got %v", expectedETag, etag)
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Remove the completed object so it does not interfere with future tests.
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,201 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// The values of ObjectInfo.Encryption.
+const (
+	encryptionSSES3  = "AES256"
+	encryptionSSEKMS = "aws:kms"
+	encryptionSSEC   = "SSE-C"
+)
+
+// etagIsMD5 - whether the ETag of object is the hex MD5 of its body. This holds for objects stored
+// unencrypted or with SSE-S3, the ETags of SSE-C and SSE-KMS objects are opaque.
+func etagIsMD5(object *ObjectInfo) bool {
+	return object.Encryption != encryptionSSEC && object.Encryption != encryptionSSEKMS
+}
+
+// verifyObjectETag - verify eTag, as returned for object, is the hex MD5 of its body where that is
+// expected and otherwise only that it is set.
+func verifyObjectETag(object *ObjectInfo, eTag string) error {
+	eTag = strings.Trim(eTag, "\"")
+	if !etagIsMD5(object) {
+		if eTag == "" {
+			err := HeaderMismatchError{Header: "ETag", Expected: "an opaque ETag", Got: eTag, Detail: object.Encryption + " object"}
+			return err
+		}
+		return nil
+	}
+	md5Sum, _, _, err := computeHash(bytes.NewReader(object.Body))
+	if err != nil {
+		return err
+	}
+	if expectedETag := hex.EncodeToString(md5Sum); eTag != expectedETag {
+		err := ChecksumMismatchError{Checksum: "ETag", Expected: expectedETag, Got: eTag}
+		return err
+	}
+	return nil
+}
+
+// verifyETagConsistency - verify GET and HEAD of object report putETag, the ETag of its upload,
+// and that it is the one verifyObjectETag expects. key is the SSE-C key, nil for other objects.
+func verifyETagConsistency(config ServerConfig, bucketName string, object *ObjectInfo, putETag string, key []byte) error {
+	if err := verifyObjectETag(object, putETag); err != nil {
+		return err
+	}
+	getReq, err := newGetObjectReq(bucketName, object.Key, nil)
+	if err != nil {
+		return err
+	}
+	headReq, err := newHeadObjectReq(bucketName, object.Key)
+	if err != nil {
+		return err
+	}
+	if key != nil {
+		setSSECustomerHeaders(getReq, key)
+		setSSECustomerHeaders(headReq, key)
+	}
+	getRes, err := config.execRequest("GET", getReq)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(getRes)
+	if err := getObjectVerify(getRes, object.Body, http.StatusOK, nil); err != nil {
+		return err
+	}
+	headRes, err := config.execRequest("HEAD", headReq)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(headRes)
+	if err := headObjectVerify(headRes, http.StatusOK); err != nil {
+		return err
+	}
+	surfaces := []struct {
+		method string
+		header http.Header
+	}{
+		{"GET", getRes.Header},
+		{"HEAD", headRes.Header},
+	}
+	for _, surface := range surfaces {
+		if got := surface.header.Get("ETag"); got != putETag {
+			err := HeaderMismatchError{Header: "ETag", Expected: putETag, Got: got, Detail: surface.method + " must report what the PUT response did"}
+			return err
+		}
+	}
+	return nil
+}
+
+// putEncryptedObject - upload object encrypted as object.Encryption says, with key for SSE-C, and
+// return the response to the upload for the caller to verify.
+func putEncryptedObject(config ServerConfig, bucketName string, object *ObjectInfo, key []byte) (*http.Response, error) {
+	req, err := newPutObjectReq(bucketName, object.Key, object.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch object.Encryption {
+	case encryptionSSEC:
+		setSSECustomerHeaders(req, key)
+	case encryptionSSEKMS:
+		setSSEKMSHeaders(req, globalKMSKeyID, "")
+	}
+	return config.execRequest("PUT", req)
+}
+
+// mainPutObjectEncryptedETag - Test SSE-KMS and SSE-C objects report one ETag on PUT, GET and HEAD
+// without requiring it to be the MD5 of the body. SSE-C is only tried on https endpoints.
+func mainPutObjectEncryptedETag(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] PutObject (Encrypted ETag):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	endpointURL, err := url.Parse(config.Endpoint)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	key, err := newSSECustomerKey()
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	bucketName := s3verifyBuckets[0].Name
+	encryptions := []string{encryptionSSEKMS}
+	if endpointURL.Scheme == "https" {
+		encryptions = append(encryptions, encryptionSSEC)
+	}
+	details := []string{}
+	for _, encryption := range encryptions {
+		// Spin scanBar
+		scanBar(message)
+		object := &ObjectInfo{
+			Key:        "s3verify/put/etag-" + strings.ToLower(strings.Replace(encryption, ":", "-", -1)),
+			Body:       generatedBody(time.Now().UnixNano(), 1024),
+			Encryption: encryption,
+		}
+		res, err := putEncryptedObject(config, bucketName, object, key)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		if isNotImplemented(res) {
+			drainAndClose(res)
+			details = append(details, "Skipped: server does not support "+encryption)
+			continue
+		}
+		err = putObjectVerify(res, http.StatusOK)
+		drainAndClose(res)
+		if err != nil {
+			printMessage(message, fmt.Errorf("%v: %v", encryption, err))
+			return false
+		}
+		// Spin scanBar
+		scanBar(message)
+		if err := verifyETagConsistency(config, bucketName, object, res.Header.Get("ETag"), key); err != nil {
+			printMessage(message, fmt.Errorf("%v: %v", encryption, err))
+			return false
+		}
+		// Whether the ETag still happens to be the MD5 is reported, not required.
+		plain := *object
+		plain.Encryption = ""
+		if verifyObjectETag(&plain, res.Header.Get("ETag")) == nil {
+			details = append(details, encryption+" ETag is the MD5 of the body")
+		} else {
+			details = append(details, encryption+" ETag is opaque")
+		}
+		if err := removeObject(config, bucketName, object.Key); err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Test passed.
+	printMessage(message, nil)
+	for _, detail := range details {
+		printDetail(detail)
+	}
+	return true
+}
@@ -0,0 +1,322 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// How long mainObjectLock retains its version. Long enough for the test to see the retention
+// enforced, short enough that the version can be removed soon after even if the test failed.
+const objectLockRetention = 30 * time.Second
+
+// newObjectLockReq - Create a new HTTP request on the retention or legal-hold subresource of a
+// version of objectName. The body is lockConfig marshalled as XML, none if lockConfig is nil.
+func newObjectLockReq(bucketName, objectName, versionID, subresource string, lockConfig interface{}) (Request, error) {
+	// objectLockReq - a new HTTP request on an object lock subresource.
+	var objectLockReq = Request{
+		customHeader: http.Header{},
+	}
+
+	// Set the bucketName and objectName.
+	objectLockReq.bucketName = bucketName
+	objectLockReq.objectName = objectName
+
+	// Set the query values.
+	urlValues := make(url.Values)
+	urlValues.Set(subresource, "")
+	if versionID != "" {
+		urlValues.Set("versionId", versionID)
+	}
+	objectLockReq.queryValues = urlValues
+
+	body := []byte{}
+	if lockConfig != nil {
+		var err error
+		body, err = xml.Marshal(lockConfig)
+		if err != nil {
+			return Request{}, err
+		}
+	}
+	reader := bytes.NewReader(body)
+	md5Sum, sha256Sum, contentLength, err := computeHash(reader)
+	if err != nil {
+		return Request{}, err
+	}
+
+	// Set the body, header and content length. Content-MD5 is required with a body.
+	if lockConfig != nil {
+		objectLockReq.contentBody = reader
+		objectLockReq.contentLength = contentLength
+		objectLockReq.customHeader.Set("Content-MD5", base64.StdEncoding.EncodeToString(md5Sum))
+	}
+	objectLockReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
+	objectLockReq.customHeader.Set("User-Agent", appUserAgent)
+
+	return objectLockReq, nil
+}
+
+// newPutObjectRetentionReq - Create a new HTTP request for the PutObjectRetention API.
+func newPutObjectRetentionReq(bucketName, objectName, versionID, mode string, retainUntil time.Time) (Request, error) {
+	retention := objectRetention{
+		Mode:            mode,
+		RetainUntilDate: retainUntil.UTC(),
+	}
+	return newObjectLockReq(bucketName, objectName, versionID, "retention", retention)
+}
+
+// newGetObjectRetentionReq - Create a new HTTP request for the GetObjectRetention API.
+func newGetObjectRetentionReq(bucketName, objectName, versionID string) (Request, error) {
+	return newObjectLockReq(bucketName, objectName, versionID, "retention", nil)
+}
+
+// newPutObjectLegalHoldReq - Create a new HTTP request for the PutObjectLegalHold API.
+func newPutObjectLegalHoldReq(bucketName, objectName, versionID, status string) (Request, error) {
+	return newObjectLockReq(bucketName, objectName, versionID, "legal-hold", objectLegalHold{Status: status})
+}
+
+// newGetObjectLegalHoldReq - Create a new HTTP request for the GetObjectLegalHold API.
+func newGetObjectLegalHoldReq(bucketName, objectName, versionID string) (Request, error) {
+	return newObjectLockReq(bucketName, objectName, versionID, "legal-hold", nil)
+}
+
+// newGetObjectLockConfigurationReq - Create a new HTTP request for the GetObjectLockConfiguration API.
+func newGetObjectLockConfigurationReq(bucketName string) (Request, error) {
+	return newObjectLockReq(bucketName, "", "", "object-lock", nil)
+}
+
+// objectLockVerify - Verify the response to a PutObjectRetention or PutObjectLegalHold request.
+func objectLockVerify(res *http.Response, expectedStatusCode int) error {
+	if res.StatusCode != expectedStatusCode {
+		err := StatusMismatchError{Expected: expectedStatusCode, Got: res.StatusCode}
+		return err
+	}
+	if err := verifyStandardHeaders(res.Header); err != nil {
+		return err
+	}
+	return nil
+}
+
+// getObjectLock - read a retention or legal-hold subresource of an object version into lockConfig.
+func getObjectLock(config ServerConfig, req Request, lockConfig interface{}) error {
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	if err := objectLockVerify(res, http.StatusOK); err != nil {
+		return err
+	}
+	return xmlDecoder(res.Body, lockConfig)
+}
+
+// putObjectLock - send a PutObjectRetention or PutObjectLegalHold request.
+func putObjectLock(config ServerConfig, req Request) error {
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	return objectLockVerify(res, http.StatusOK)
+}
+
+// bucketHasObjectLock - report whether bucketName was created with object lock enabled.
+// A server without object lock support has none.
+func bucketHasObjectLock(config ServerConfig, bucketName string) bool {
+	req, err := newGetObjectLockConfigurationReq(bucketName)
+	if err != nil {
+		return false
+	}
+	lockConfig := objectLockConfiguration{}
+	if err := getObjectLock(config, req, &lockConfig); err != nil {
+		return false
+	}
+	return lockConfig.ObjectLockEnabled == "Enabled"
+}
+
+// releaseLegalHolds - turn off the legal hold of every version in objects. Delete markers and versions
+// that were never held may refuse, a version still held fails when it is deleted.
+func releaseLegalHolds(config ServerConfig, bucketName string, objects []deleteObject) {
+	for _, object := range objects {
+		req, err := newPutObjectLegalHoldReq(bucketName, object.Key, object.VersionID, "OFF")
+		if err != nil {
+			continue
+		}
+		putObjectLock(config, req)
+	}
+}
+
+// removeLockedVersion - DELETE a version of objectName, bypassing governance retention if bypass is set.
+func removeLockedVersion(config ServerConfig, bucketName, objectName, versionID string, bypass bool) (*http.Response, error) {
+	req, err := newRemoveObjectVersionReq(config, bucketName, objectName, versionID)
+	if err != nil {
+		return nil, err
+	}
+	if bypass {
+		req.customHeader.Set("x-amz-bypass-governance-retention", "true")
+	}
+	return config.execRequest("DELETE", req)
+}
+
+// removeLockedObject - release the legal hold of a version of objectName and remove it bypassing
+// governance retention, so a test leaves nothing locked behind whichever way it ends. Errors are
+// ignored, the version may well be gone already.
+func removeLockedObject(config ServerConfig, bucketName, objectName, versionID string) {
+	releaseLegalHolds(config, bucketName, []deleteObject{{Key: objectName, VersionID: versionID}})
+	res, err := removeLockedVersion(config, bucketName, objectName, versionID, true)
+	if err == nil {
+		drainAndClose(res)
+	}
+}
+
+// verifyRemoveLockedDenied - verify a DELETE of a locked version is refused with AccessDenied.
+func verifyRemoveLockedDenied(config ServerConfig, bucketName, objectName, versionID string, bypass bool) error {
+	res, err := removeLockedVersion(config, bucketName, objectName, versionID, bypass)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	return verifyError(res, http.StatusForbidden, "AccessDenied")
+}
+
+// mainObjectLock - Test that GOVERNANCE retention and a legal hold each prevent a version from being
+// deleted, in the bucket created with object lock enabled by the bucket matrix.
+func mainObjectLock(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] ObjectLock (Retention and Legal Hold):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucket, err := findBucket(capObjectLock)
+	if err != nil {
+		printMessage(message, nil)
+		printDetail("Skipped: no bucket could be created with object lock enabled")
+		return true
+	}
+	bucketName := bucket.Name
+	object := &ObjectInfo{
+		Key:  "s3verify/object-lock/object",
+		Body: generatedBody(time.Now().UnixNano(), 60),
+	}
+	if err := putVersionedObject(config, bucketName, object); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Whatever happens the version must not stay locked.
+	defer removeLockedObject(config, bucketName, object.Key, object.VersionID)
+	// Spin scanBar
+	scanBar(message)
+	// Retain the version just long enough for the test, to the second as the date is stored.
+	retainUntil := time.Now().Add(objectLockRetention).UTC().Truncate(time.Second)
+	req, err := newPutObjectRetentionReq(bucketName, object.Key, object.VersionID, "GOVERNANCE", retainUntil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := putObjectLock(config, req); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	retention := objectRetention{}
+	getRetentionReq, err := newGetObjectRetentionReq(bucketName, object.Key, object.VersionID)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := getObjectLock(config, getRetentionReq, &retention); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if retention.Mode != "GOVERNANCE" || !retention.RetainUntilDate.Equal(retainUntil) {
+		err := fmt.Errorf("Unexpected Retention Received: wanted GOVERNANCE until %v, got %v until %v",
+			retainUntil.Format(time.RFC3339), retention.Mode, retention.RetainUntilDate.Format(time.RFC3339))
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// The retained version can not be deleted without bypassing governance retention.
+	if err := verifyRemoveLockedDenied(config, bucketName, object.Key, object.VersionID, false); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	legalHoldReq, err := newPutObjectLegalHoldReq(bucketName, object.Key, object.VersionID, "ON")
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := putObjectLock(config, legalHoldReq); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	legalHold := objectLegalHold{}
+	getLegalHoldReq, err := newGetObjectLegalHoldReq(bucketName, object.Key, object.VersionID)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := getObjectLock(config, getLegalHoldReq, &legalHold); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if legalHold.Status != "ON" {
+		err := fmt.Errorf("Unexpected Legal Hold Status Received: wanted ON, got %v", legalHold.Status)
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// A legal hold blocks deletion even when governance retention is bypassed.
+	if err := verifyRemoveLockedDenied(config, bucketName, object.Key, object.VersionID, true); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Release the legal hold and remove the version bypassing its retention.
+	releaseReq, err := newPutObjectLegalHoldReq(bucketName, object.Key, object.VersionID, "OFF")
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := putObjectLock(config, releaseReq); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	res, err := removeLockedVersion(config, bucketName, object.Key, object.VersionID, true)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if _, err := removeObjectVersionVerify(res, http.StatusNoContent, object.VersionID, false); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
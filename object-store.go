@@ -0,0 +1,49 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import "sync"
+
+// objectStore - a list of objects that tests running concurrently can add to and read.
+type objectStore struct {
+	mutex   sync.RWMutex
+	objects []*ObjectInfo
+}
+
+// add - append objects to the store.
+func (s *objectStore) add(objects ...*ObjectInfo) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.objects = append(s.objects, objects...)
+}
+
+// snapshot - a copy of the objects in the store in the order they were added.
+// Objects added after the call are not visible in the returned slice.
+func (s *objectStore) snapshot() []*ObjectInfo {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	objects := make([]*ObjectInfo, len(s.objects))
+	copy(objects, s.objects)
+	return objects
+}
+
+// len - the number of objects in the store.
+func (s *objectStore) len() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.objects)
+}
@@ -0,0 +1,219 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// newGetObjectTaggingReq - Create a new HTTP request for the GetObjectTagging API.
+// An empty versionID asks for the tags of the latest version.
+func newGetObjectTaggingReq(bucketName, objectName, versionID string) (Request, error) {
+	// getObjectTaggingReq - a new HTTP request for GetObjectTagging.
+	var getObjectTaggingReq = Request{
+		customHeader: http.Header{},
+	}
+
+	// Set the bucketName and objectName.
+	getObjectTaggingReq.bucketName = bucketName
+	getObjectTaggingReq.objectName = objectName
+
+	// Set the query values.
+	urlValues := make(url.Values)
+	urlValues.Set("tagging", "")
+	if versionID != "" {
+		urlValues.Set("versionId", versionID)
+	}
+	getObjectTaggingReq.queryValues = urlValues
+
+	// No body is sent with GET requests.
+	reader := bytes.NewReader([]byte{})
+	_, sha256Sum, _, err := computeHash(reader)
+	if err != nil {
+		return Request{}, err
+	}
+
+	// Set the headers.
+	getObjectTaggingReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
+	getObjectTaggingReq.customHeader.Set("User-Agent", appUserAgent)
+
+	return getObjectTaggingReq, nil
+}
+
+// getObjectTagging - the tags of versionID of objectName. Returns false without an error if the
+// server does not implement object tagging.
+func getObjectTagging(config ServerConfig, bucketName, objectName, versionID string) ([]objectTag, bool, error) {
+	req, err := newGetObjectTaggingReq(bucketName, objectName, versionID)
+	if err != nil {
+		return nil, false, err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer drainAndClose(res)
+	if res.StatusCode != http.StatusOK {
+		if isNotImplemented(res) {
+			return nil, false, nil
+		}
+		err := fmt.Errorf("Unexpected Status Received: wanted %v, got %v", http.StatusOK, res.StatusCode)
+		return nil, false, err
+	}
+	if err := verifyStandardHeaders(res.Header); err != nil {
+		return nil, false, err
+	}
+	result := tagging{}
+	if err := xmlDecoder(res.Body, &result); err != nil {
+		return nil, false, err
+	}
+	return result.TagSet, true, nil
+}
+
+// putObjectTagged - upload object with the given tags set through the x-amz-tagging header.
+func putObjectTagged(config ServerConfig, bucketName string, object *ObjectInfo, tags map[string]string) (http.Header, error) {
+	req, err := newPutObjectReq(bucketName, object.Key, object.Body)
+	if err != nil {
+		return nil, err
+	}
+	tagQuery := url.Values{}
+	for key, value := range tags {
+		tagQuery.Set(key, value)
+	}
+	req.customHeader.Set("x-amz-tagging", tagQuery.Encode())
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(res)
+	if err := putObjectVerify(res, http.StatusOK); err != nil {
+		return nil, err
+	}
+	return res.Header, nil
+}
+
+// verifyTagSet - verify tags holds exactly the expected tags, in any order.
+func verifyTagSet(tags []objectTag, expected map[string]string) error {
+	received := []string{}
+	for _, tag := range tags {
+		received = append(received, tag.Key+"="+tag.Value)
+	}
+	wanted := []string{}
+	for key, value := range expected {
+		wanted = append(wanted, key+"="+value)
+	}
+	sort.Strings(received)
+	sort.Strings(wanted)
+	if strings.Join(received, "&") != strings.Join(wanted, "&") {
+		err := fmt.Errorf("Unexpected Tags Received: wanted [%s], got [%s]", strings.Join(wanted, " "), strings.Join(received, " "))
+		return err
+	}
+	return nil
+}
+
+// mainObjectTaggingVersions - Test that every version of an object has its own tags: a version uploaded
+// with tags keeps them after it is overwritten by a version uploaded without any.
+func mainObjectTaggingVersions(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] ObjectTagging (Versions):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucket, err := findBucket(capVersioned)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	bucketName := bucket.Name
+	seed := time.Now().UnixNano()
+	taggedVersion := &ObjectInfo{
+		Key:  "s3verify/tagging/versions",
+		Body: generatedBody(seed, 60),
+	}
+	untaggedVersion := &ObjectInfo{
+		Key:  taggedVersion.Key,
+		Body: generatedBody(seed+1, 60),
+	}
+	tags := map[string]string{"s3verify": "tagged", "version": "first"}
+	header, err := putObjectTagged(config, bucketName, taggedVersion, tags)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	taggedVersion.VersionID = header.Get("x-amz-version-id")
+	if taggedVersion.VersionID == "" {
+		err := fmt.Errorf("Missing Header: x-amz-version-id was not returned for an object in a versioned bucket")
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := putVersionedObject(config, bucketName, untaggedVersion); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	receivedTags, supported, err := getObjectTagging(config, bucketName, taggedVersion.Key, taggedVersion.VersionID)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if !supported {
+		for _, version := range []*ObjectInfo{taggedVersion, untaggedVersion} {
+			if _, err := removeObjectVersion(config, bucketName, version.Key, version.VersionID, false); err != nil {
+				printMessage(message, err)
+				return false
+			}
+		}
+		printMessage(message, nil)
+		printDetail("Skipped: server does not support object tagging")
+		return true
+	}
+	if err := verifyTagSet(receivedTags, tags); err != nil {
+		err = fmt.Errorf("Overwritten version %s: %v", taggedVersion.VersionID, err)
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	receivedTags, _, err = getObjectTagging(config, bucketName, untaggedVersion.Key, untaggedVersion.VersionID)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyTagSet(receivedTags, nil); err != nil {
+		err = fmt.Errorf("Latest version %s: %v", untaggedVersion.VersionID, err)
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	for _, version := range []*ObjectInfo{taggedVersion, untaggedVersion} {
+		if _, err := removeObjectVersion(config, bucketName, version.Key, version.VersionID, false); err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
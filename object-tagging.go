@@ -0,0 +1,211 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// newPutObjectTaggingReq - Create a new HTTP request for the PutObjectTagging API.
+func newPutObjectTaggingReq(bucketName, objectName string, tags map[string]string) (Request, error) {
+	// putObjectTaggingReq - a new HTTP request for PutObjectTagging.
+	var putObjectTaggingReq = Request{
+		customHeader: http.Header{},
+	}
+
+	// Set the bucketName and objectName.
+	putObjectTaggingReq.bucketName = bucketName
+	putObjectTaggingReq.objectName = objectName
+
+	// Set the query values.
+	urlValues := make(url.Values)
+	urlValues.Set("tagging", "")
+	putObjectTaggingReq.queryValues = urlValues
+
+	// Sort the keys so the same tags always produce the same body.
+	keys := []string{}
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	tagSet := tagging{}
+	for _, key := range keys {
+		tagSet.TagSet = append(tagSet.TagSet, objectTag{Key: key, Value: tags[key]})
+	}
+	tagSetBytes, err := xml.Marshal(tagSet)
+	if err != nil {
+		return Request{}, err
+	}
+	reader := bytes.NewReader(tagSetBytes)
+	md5Sum, sha256Sum, contentLength, err := computeHash(reader)
+	if err != nil {
+		return Request{}, err
+	}
+
+	// Set the body, header and content length.
+	putObjectTaggingReq.contentBody = reader
+	putObjectTaggingReq.contentLength = contentLength
+	putObjectTaggingReq.customHeader.Set("Content-MD5", base64.StdEncoding.EncodeToString(md5Sum))
+	putObjectTaggingReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
+	putObjectTaggingReq.customHeader.Set("User-Agent", appUserAgent)
+
+	return putObjectTaggingReq, nil
+}
+
+// newDeleteObjectTaggingReq - Create a new HTTP request for the DeleteObjectTagging API.
+func newDeleteObjectTaggingReq(bucketName, objectName string) (Request, error) {
+	// deleteObjectTaggingReq - a new HTTP request for DeleteObjectTagging.
+	var deleteObjectTaggingReq = Request{
+		customHeader: http.Header{},
+	}
+
+	// Set the bucketName and objectName.
+	deleteObjectTaggingReq.bucketName = bucketName
+	deleteObjectTaggingReq.objectName = objectName
+
+	// Set the query values.
+	urlValues := make(url.Values)
+	urlValues.Set("tagging", "")
+	deleteObjectTaggingReq.queryValues = urlValues
+
+	// No body is sent with DELETE requests.
+	reader := bytes.NewReader([]byte{})
+	_, sha256Sum, _, err := computeHash(reader)
+	if err != nil {
+		return Request{}, err
+	}
+
+	// Set the headers.
+	deleteObjectTaggingReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
+	deleteObjectTaggingReq.customHeader.Set("User-Agent", appUserAgent)
+
+	return deleteObjectTaggingReq, nil
+}
+
+// objectTaggingVerify - Verify the response to a PutObjectTagging or DeleteObjectTagging request.
+func objectTaggingVerify(res *http.Response, expectedStatusCode int) error {
+	if res.StatusCode != expectedStatusCode {
+		err := StatusMismatchError{Expected: expectedStatusCode, Got: res.StatusCode}
+		return err
+	}
+	if err := verifyStandardHeaders(res.Header); err != nil {
+		return err
+	}
+	return nil
+}
+
+// mainObjectTagging - Test setting, reading back and deleting the tags of an object.
+func mainObjectTagging(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] ObjectTagging:", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/tagging/object",
+		Body: generatedBody(time.Now().UnixNano(), 60),
+	}
+	if _, err := putObject(config, bucketName, object); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// The second value holds characters that are reserved in URLs and must survive the XML body intact.
+	tags := map[string]string{
+		"s3verify": "tagging",
+		"reserved": "a/b?c=d&e+f",
+	}
+	// Spin scanBar
+	scanBar(message)
+	req, err := newPutObjectTaggingReq(bucketName, object.Key, tags)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if res.StatusCode != http.StatusOK && isNotImplemented(res) {
+		if err := removeObject(config, bucketName, object.Key); err != nil {
+			printMessage(message, err)
+			return false
+		}
+		printMessage(message, nil)
+		printDetail("Skipped: server does not support object tagging")
+		return true
+	}
+	if err := objectTaggingVerify(res, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	receivedTags, _, err := getObjectTagging(config, bucketName, object.Key, "")
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyTagSet(receivedTags, tags); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	deleteReq, err := newDeleteObjectTaggingReq(bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	deleteRes, err := config.execRequest("DELETE", deleteReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(deleteRes)
+	if err := objectTaggingVerify(deleteRes, http.StatusNoContent); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// The tag set must now be empty.
+	receivedTags, _, err = getObjectTagging(config, bucketName, object.Key, "")
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyTagSet(receivedTags, nil); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
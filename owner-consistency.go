@@ -0,0 +1,199 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ownerSurface - an owner ID and the operation it was returned by.
+type ownerSurface struct {
+	Operation string
+	ID        string
+}
+
+// verifyOwnersConsistent - verify every operation returned the same owner ID, listing the
+// distinct IDs and where they were seen otherwise.
+func verifyOwnersConsistent(surfaces []ownerSurface) error {
+	seenBy := make(map[string][]string)
+	for _, surface := range surfaces {
+		seenBy[surface.ID] = append(seenBy[surface.ID], surface.Operation)
+	}
+	if len(seenBy) == 1 {
+		return nil
+	}
+	distinct := []string{}
+	for id, operations := range seenBy {
+		distinct = append(distinct, fmt.Sprintf("%q from %s", id, strings.Join(operations, ", ")))
+	}
+	sort.Strings(distinct)
+	err := fmt.Errorf("Inconsistent Owner IDs Received: %s", strings.Join(distinct, "; "))
+	return err
+}
+
+// listBucketsOwner - the owner ID returned by ListBuckets.
+func listBucketsOwner(config ServerConfig) (string, error) {
+	req, err := newListBucketsReq()
+	if err != nil {
+		return "", err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return "", err
+	}
+	defer drainAndClose(res)
+	if err := verifyStatusListBuckets(res.StatusCode, http.StatusOK); err != nil {
+		return "", err
+	}
+	result := listAllMyBucketsResult{}
+	if err := xmlDecoder(res.Body, &result); err != nil {
+		return "", err
+	}
+	return result.Owner.ID, nil
+}
+
+// listObjectsOwner - the owner ID returned for objectName by ListObjects V2 with fetch-owner set.
+func listObjectsOwner(config ServerConfig, bucketName, objectName string) (string, error) {
+	req, err := newListObjectsV2Req(bucketName, map[string]string{
+		"prefix":      objectName,
+		"fetch-owner": "true",
+	})
+	if err != nil {
+		return "", err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return "", err
+	}
+	defer drainAndClose(res)
+	if err := verifyStatusListObjectsV2(res.StatusCode, http.StatusOK); err != nil {
+		return "", err
+	}
+	result := listBucketV2Result{}
+	if err := xmlDecoder(res.Body, &result); err != nil {
+		return "", err
+	}
+	for _, object := range result.Contents {
+		if object.Key == objectName {
+			return object.Owner.ID, nil
+		}
+	}
+	err = fmt.Errorf("Object Not Listed: %v was not found with fetch-owner set", objectName)
+	return "", err
+}
+
+// objectACLOwner - the owner ID returned by GetObjectACL.
+func objectACLOwner(config ServerConfig, bucketName, objectName string) (string, error) {
+	req, err := newGetObjectACLReq(bucketName, objectName)
+	if err != nil {
+		return "", err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return "", err
+	}
+	defer drainAndClose(res)
+	policy, err := getObjectACLVerify(res, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	return policy.Owner.ID, nil
+}
+
+// bucketACLOwner - the owner ID returned by GetBucketACL.
+func bucketACLOwner(config ServerConfig, bucketName string) (string, error) {
+	req, err := newGetBucketACLReq(bucketName)
+	if err != nil {
+		return "", err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return "", err
+	}
+	defer drainAndClose(res)
+	policy, err := getBucketACLVerify(res, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	return policy.Owner.ID, nil
+}
+
+// mainOwnerConsistency - verify the same owner ID is returned by every operation that reports one.
+func mainOwnerConsistency(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Owner (Consistency):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/owner/object",
+		Body: []byte("s3verify owner"),
+	}
+	if _, err := putObject(config, bucketName, object); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	surfaces := []ownerSurface{}
+	// Spin scanBar
+	scanBar(message)
+	id, err := listBucketsOwner(config)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	surfaces = append(surfaces, ownerSurface{Operation: "ListBuckets", ID: id})
+	// Spin scanBar
+	scanBar(message)
+	id, err = listObjectsOwner(config, bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	surfaces = append(surfaces, ownerSurface{Operation: "ListObjectsV2", ID: id})
+	// Spin scanBar
+	scanBar(message)
+	id, err = objectACLOwner(config, bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	surfaces = append(surfaces, ownerSurface{Operation: "GetObjectACL", ID: id})
+	// Spin scanBar
+	scanBar(message)
+	id, err = bucketACLOwner(config, bucketName)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	surfaces = append(surfaces, ownerSurface{Operation: "GetBucketACL", ID: id})
+	// Spin scanBar
+	scanBar(message)
+	if err := verifyOwnersConsistent(surfaces); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Remove the object so it does not interfere with future tests.
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -28,8 +28,6 @@ import (
 	"github.com/minio/minio-go"
 )
 
-const numTestObjects = 101
-
 // prepareBucket - Uses minio-go library to create new testing bucket for use by s3verify.
 func prepareBuckets(region string, client *minio.Client) (string, error) {
 	message := "Creating test bucket"
@@ -50,25 +48,27 @@ func prepareBuckets(region string, client *minio.Client) (string, error) {
 
 // TODO: see if parallelization has a place here.
 
-// prepareObjects - Uses minio-go library to create 1001 new testing objects for use by s3verify.
-func prepareObjects(client *minio.Client, bucketName string) error {
+// prepareObjects - Uses minio-go library to create numObjects new testing objects of objectSize bytes for use by s3verify.
+// The objects are uploaded concurrently with uploadBatch, transient failures are retried by minio-go itself.
+func prepareObjects(config ServerConfig, client *minio.Client, bucketName string, numObjects, objectSize int) error {
 	message := "Creating test objects"
-	// TODO: update this to 1001...for testing purposes it is OK to leave it at 101 for now.
-	// Upload 1001 objects specifically for the list-objects tests.
-	for i := 0; i < numTestObjects; i++ {
-		// Spin scanBar
-		scanBar(message)
-		randomData := randString(60, rand.NewSource(time.Now().UnixNano()), "")
-		objectKey := "s3verify/put/object/" + globalSuffix + strconv.Itoa(i)
-		// Create 60 bytes worth of random data for each object.
-		reader := bytes.NewReader([]byte(randomData))
-		_, err := client.PutObject(bucketName, objectKey, reader, "application/octet-stream")
-		if err != nil {
-			printMessage(message, err)
-			return err
+	// Every object is generated from its own seed so no two bodies are the same.
+	seed := time.Now().UnixNano()
+	// Upload the objects specifically for the list-objects tests.
+	objects := make([]*ObjectInfo, numObjects)
+	for i := range objects {
+		objects[i] = &ObjectInfo{
+			Key:  "s3verify/put/object/" + globalSuffix + strconv.Itoa(i),
+			Body: generatedBody(seed+int64(i), objectSize),
 		}
-		// Spin scanBar
-		scanBar(message)
+	}
+	results := uploadBatch(config, objects, func(object *ObjectInfo) error {
+		_, err := client.PutObject(bucketName, object.Key, bytes.NewReader(object.Body), "application/octet-stream")
+		return err
+	}, message)
+	if err := uploadBatchError(results); err != nil {
+		printMessage(message, err)
+		return err
 	}
 	randomData := randString(60, rand.NewSource(time.Now().UnixNano()), "")
 	objectKey := "s3verify/list/" + globalSuffix
@@ -119,11 +119,11 @@ func validateBucket(config ServerConfig, bucketName string) error {
 			ETag:         objectInfo.ETag,
 			LastModified: objectInfo.LastModified,
 		}
-		preparedObjects = append(preparedObjects, object)
+		preparedObjects.add(object)
 	}
 	// Make sure that enough objects were actually found with the right prefix.
-	if len(preparedObjects) < numTestObjects {
-		err := fmt.Errorf("Not enough test objects found: need at least %d, only found %d", numTestObjects, len(preparedObjects))
+	if preparedObjects.len() < config.objectCount() {
+		err := fmt.Errorf("Not enough test objects found: need at least %d, only found %d", config.objectCount(), preparedObjects.len())
 		return err
 	}
 	return nil
@@ -131,7 +131,7 @@ func validateBucket(config ServerConfig, bucketName string) error {
 
 // TODO: Create function using minio-go to upload 1001 parts of a multipart operation.
 
-// mainPrepareS3Verify - Create one new buckets and 1001 objects for s3verify to use in the test.
+// mainPrepareS3Verify - Create one new buckets and --object-count objects for s3verify to use in the test.
 func mainPrepareS3Verify(config ServerConfig) (string, error) {
 	// Extract necessary values from the config.
 	hostURL, err := url.Parse(config.Endpoint)
@@ -153,7 +153,7 @@ func mainPrepareS3Verify(config ServerConfig) (string, error) {
 		return "", err
 	}
 	// Use the first newly created bucket to store all the objects.
-	if err := prepareObjects(client, validBucketName); err != nil {
+	if err := prepareObjects(config, client, validBucketName, config.objectCount(), config.objectSize()); err != nil {
 		return "", err
 	}
 	return validBucketName, nil
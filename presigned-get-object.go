@@ -17,7 +17,6 @@
 package main
 
 import (
-	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -95,11 +94,7 @@ func verifyBodyGetObjectPresigned(resBody io.Reader, expectedBody []byte, expect
 	if err != nil {
 		return err
 	}
-	if !bytes.Equal(receivedBody, expectedBody) {
-		err := fmt.Errorf("Unexpected Body Received: wanted %q, got %q", expectedBody, receivedBody)
-		return err
-	}
-	return nil
+	return verifyBodyEqual(expectedBody, receivedBody)
 }
 
 // verifyStatusGetObjectPresigned - verify the status returned matches what is expected.
@@ -129,7 +124,7 @@ func mainGetObjectPresigned(config ServerConfig, curTest int) bool {
 	// Presigned getobject will only be tested in s3verify created buckets
 	// on s3verify created objects.
 	bucketName := s3verifyBuckets[0].Name
-	for i, object := range s3verifyObjects {
+	for i, object := range s3verifyObjects.snapshot() {
 		// Spin scanBar
 		scanBar(message)
 		// Create a new presigned GetObject req.
@@ -149,9 +144,10 @@ func mainGetObjectPresigned(config ServerConfig, curTest int) bool {
 			printMessage(message, err)
 			return false
 		}
-		defer closeResponse(res)
 		// Verify the response.
-		if err := getObjectPresignedVerify(res, http.StatusOK, object.Body, ErrorResponse{}); err != nil {
+		err = getObjectPresignedVerify(res, http.StatusOK, object.Body, ErrorResponse{})
+		drainAndClose(res)
+		if err != nil {
 			printMessage(message, err)
 			return false
 		}
@@ -174,9 +170,9 @@ func mainGetObjectPresigned(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(badRes)
+	defer drainAndClose(badRes)
 	// Verify that this badRes failed as expected.
-	if err := getObjectPresignedVerify(badRes, http.StatusForbidden, s3verifyObjects[0].Body, expectedError); err != nil {
+	if err := getObjectPresignedVerify(badRes, http.StatusForbidden, s3verifyObjects.snapshot()[0].Body, expectedError); err != nil {
 		printMessage(message, err)
 		return false
 	}
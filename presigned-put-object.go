@@ -29,29 +29,11 @@ import (
 
 // newPresignedPutObjectReq - Create a new Request for PUT object requests using presigned URLs.
 func newPresignedPutObjectReq(config ServerConfig, bucketName, objectName string, expires time.Duration) (*url.URL, error) {
-	// presignedPutObjectReq - a new request with presigned URL for PUT object requests.
-	var presignedPutObjectReq = Request{
-		customHeader: http.Header{},
-	}
-
-	// Indicate presigned.
-	presignedPutObjectReq.presignURL = true
-
-	// Set the bucketName and objectName.
-	presignedPutObjectReq.bucketName = bucketName
-	presignedPutObjectReq.objectName = objectName
-
-	// Set the expiry.
-	expireSeconds := int64(expires / time.Second)
-	presignedPutObjectReq.expires = expireSeconds
-
-	// Extract the url from the Request.
-	req, err := config.newRequest("PUT", presignedPutObjectReq)
+	presignedURL, err := presignRequest(config, "PUT", bucketName, objectName, expires)
 	if err != nil {
 		return nil, err
 	}
-
-	return req.URL, nil
+	return url.Parse(presignedURL)
 }
 
 // presignedPutObjectVerify - verify the response returned matches what is expected.
@@ -71,7 +53,7 @@ func presignedPutObjectVerify(res *http.Response, expectedStatusCode int, expect
 // verifyStatusPresignedPutObject - verify the status returned matches what is expected.
 func verifyStatusPresignedPutObject(respStatusCode int, expectedStatusCode int) error {
 	if respStatusCode != expectedStatusCode {
-		err := fmt.Errorf("Unexpected Status Code Received: wanted %d, got %d", expectedStatusCode, respStatusCode)
+		err := StatusMismatchError{Expected: expectedStatusCode, Got: respStatusCode}
 		return err
 	}
 	return nil
@@ -103,11 +85,7 @@ func verifyBodyPresignedPutObject(resBody io.Reader, expectedError ErrorResponse
 	if err != nil {
 		return err
 	}
-	if !bytes.Equal(receivedBody, []byte{}) {
-		err := fmt.Errorf("Unexpected Body Received: %q", receivedBody)
-		return err
-	}
-	return nil
+	return verifyBodyEqual([]byte{}, receivedBody)
 }
 
 // THIS MIGHT CAUSE PROBLEMS HAVE TO CHECK LIST OBJECTS AFTER THIS IS DONE.
@@ -155,7 +133,7 @@ func mainPresignedPutObject(config ServerConfig, curTest int) bool {
 	}
 
 	// Store the newly created object.
-	s3verifyObjects = append(s3verifyObjects, presignedObject)
+	s3verifyObjects.add(presignedObject)
 
 	// Test passed.
 	printMessage(message, nil)
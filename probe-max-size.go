@@ -0,0 +1,116 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/minio/mc/pkg/console"
+)
+
+// defaultProbeCeiling - the largest object S3 accepts in a single PUT, 5GiB.
+const defaultProbeCeiling = 5 * 1024 * 1024 * 1024
+
+// probeResolution - the search stops once the limit is known to within this many bytes.
+const probeResolution = 1024 * 1024
+
+// probeBucket - the temporary bucket probe objects are uploaded to.
+func probeBucket() string {
+	return "s3verify-" + globalSuffix + "-probe"
+}
+
+// probePut - upload a generated object of size bytes and remove it again straight away.
+// Returns false without an error if the server rejected it with EntityTooLarge.
+func probePut(config ServerConfig, size int64) (bool, error) {
+	objectName := fmt.Sprintf("s3verify/probe/%d", size)
+	req, err := newPutObjectStreamReq(probeBucket(), objectName, newGeneratedReader(time.Now().UnixNano(), size))
+	if err != nil {
+		return false, err
+	}
+	// Every probe is sent once only, a reset connection must not send gigabytes again.
+	res, err := config.execRequestOnce("PUT", req)
+	if err != nil {
+		return false, err
+	}
+	defer drainAndClose(res)
+	if res.StatusCode == http.StatusBadRequest {
+		errResponse := ErrorResponse{}
+		if err := xmlDecoder(res.Body, &errResponse); err != nil {
+			return false, err
+		}
+		if errResponse.Code == "EntityTooLarge" {
+			return false, nil
+		}
+		err := fmt.Errorf("Unexpected Error Response: wanted EntityTooLarge for %d bytes, got %v", size, errResponse.Code)
+		return false, err
+	}
+	if err := putObjectVerify(res, http.StatusOK); err != nil {
+		return false, err
+	}
+	if err := removeObject(config, probeBucket(), objectName); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// mainProbeMaxSize - binary search for the largest object a single PUT is accepted for, up to ceiling bytes,
+// and report it. The bounds found so far are reported even if the search is cut short by an error,
+// such as running out of --max-upload-bytes.
+func mainProbeMaxSize(config ServerConfig, ceiling int64) error {
+	if ceiling < 1 {
+		err := fmt.Errorf("Invalid Probe: --probe-ceiling must be positive")
+		return err
+	}
+	if err := putBucket(config, probeBucket()); err != nil {
+		return err
+	}
+	defer func() {
+		if err := removeBucketWithContents(config, probeBucket()); err != nil {
+			console.Errorln(err)
+		}
+	}()
+	globalReporter.info(fmt.Sprintf("Probing the largest single PUT accepted, up to %d bytes.", ceiling))
+	// Check the ceiling itself first, most servers accept it and the search ends there.
+	accepted, err := probePut(config, ceiling)
+	if err != nil {
+		return err
+	}
+	if accepted {
+		globalReporter.info(fmt.Sprintf("Objects of %d bytes, the ceiling, are accepted. Raise --probe-ceiling to search further.", ceiling))
+		return nil
+	}
+	// The largest size known to be accepted and the smallest known to be rejected.
+	var lo int64
+	hi := ceiling
+	for hi-lo > probeResolution {
+		mid := lo + (hi-lo)/2
+		accepted, err := probePut(config, mid)
+		if err != nil {
+			globalReporter.info(fmt.Sprintf("Probe stopped at %d bytes: accepted up to %d bytes, rejected from %d bytes.", mid, lo, hi))
+			return err
+		}
+		if accepted {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	globalReporter.info(fmt.Sprintf("Largest single PUT accepted: between %d and %d bytes, %d bytes is rejected with EntityTooLarge.", lo, hi-1, hi))
+	return nil
+}
@@ -0,0 +1,125 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// newPutBucketVersioningReq - Create a new HTTP request for the PutBucketVersioning API.
+func newPutBucketVersioningReq(bucketName, status string) (Request, error) {
+	// putBucketVersioningReq - a new HTTP request for PutBucketVersioning.
+	var putBucketVersioningReq = Request{
+		customHeader: http.Header{},
+	}
+
+	// Set the bucketName.
+	putBucketVersioningReq.bucketName = bucketName
+
+	// Set the query values.
+	urlValues := make(url.Values)
+	urlValues.Set("versioning", "")
+	putBucketVersioningReq.queryValues = urlValues
+
+	versioningConfig := versioningConfiguration{
+		Status: status,
+	}
+	versioningConfigBytes, err := xml.Marshal(versioningConfig)
+	if err != nil {
+		return Request{}, err
+	}
+	reader := bytes.NewReader(versioningConfigBytes)
+	md5Sum, sha256Sum, contentLength, err := computeHash(reader)
+	if err != nil {
+		return Request{}, err
+	}
+
+	// Set the body, header and content length.
+	putBucketVersioningReq.contentBody = reader
+	putBucketVersioningReq.contentLength = contentLength
+	putBucketVersioningReq.customHeader.Set("Content-MD5", base64.StdEncoding.EncodeToString(md5Sum))
+	putBucketVersioningReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
+	putBucketVersioningReq.customHeader.Set("User-Agent", appUserAgent)
+
+	return putBucketVersioningReq, nil
+}
+
+// putBucketVersioningVerify - Verify the response returned matches what is expected.
+func putBucketVersioningVerify(res *http.Response, expectedStatusCode int) error {
+	if err := verifyStatusPutBucketVersioning(res.StatusCode, expectedStatusCode); err != nil {
+		return err
+	}
+	if err := verifyHeaderPutBucketVersioning(res.Header); err != nil {
+		return err
+	}
+	if err := verifyBodyPutBucketVersioning(res.Body); err != nil {
+		return err
+	}
+	return nil
+}
+
+// verifyStatusPutBucketVersioning - verify the status returned matches what is expected.
+func verifyStatusPutBucketVersioning(respStatusCode, expectedStatusCode int) error {
+	if respStatusCode != expectedStatusCode {
+		err := fmt.Errorf("Unexpected Status Received: wanted %v, got %v", expectedStatusCode, respStatusCode)
+		return err
+	}
+	return nil
+}
+
+// verifyHeaderPutBucketVersioning - verify the header returned matches what is expected.
+func verifyHeaderPutBucketVersioning(header http.Header) error {
+	if err := verifyStandardHeaders(header); err != nil {
+		return err
+	}
+	return nil
+}
+
+// verifyBodyPutBucketVersioning - verify the body returned is empty.
+func verifyBodyPutBucketVersioning(resBody io.Reader) error {
+	body, err := ioutil.ReadAll(resBody)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(body, []byte{}) {
+		err := fmt.Errorf("Unexpected Body Received: %v", string(body))
+		return err
+	}
+	return nil
+}
+
+// putBucketVersioning - set the versioning status of bucketName and verify the response.
+func putBucketVersioning(config ServerConfig, bucketName, status string) error {
+	req, err := newPutBucketVersioningReq(bucketName, status)
+	if err != nil {
+		return err
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	return putBucketVersioningVerify(res, http.StatusOK)
+}
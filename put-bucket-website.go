@@ -0,0 +1,108 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// newPutBucketWebsiteReq - Create a new HTTP request for the PutBucketWebsite API.
+func newPutBucketWebsiteReq(bucketName string, websiteConfig websiteConfiguration) (Request, error) {
+	// putBucketWebsiteReq - a new HTTP request for PutBucketWebsite.
+	var putBucketWebsiteReq = Request{
+		customHeader: http.Header{},
+	}
+
+	// Set the bucketName.
+	putBucketWebsiteReq.bucketName = bucketName
+
+	// Set the query values.
+	urlValues := make(url.Values)
+	urlValues.Set("website", "")
+	putBucketWebsiteReq.queryValues = urlValues
+
+	websiteConfigBytes, err := xml.Marshal(websiteConfig)
+	if err != nil {
+		return Request{}, err
+	}
+	reader := bytes.NewReader(websiteConfigBytes)
+	md5Sum, sha256Sum, contentLength, err := computeHash(reader)
+	if err != nil {
+		return Request{}, err
+	}
+
+	// Set the body, header and content length.
+	putBucketWebsiteReq.contentBody = reader
+	putBucketWebsiteReq.contentLength = contentLength
+	putBucketWebsiteReq.customHeader.Set("Content-MD5", base64.StdEncoding.EncodeToString(md5Sum))
+	putBucketWebsiteReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
+	putBucketWebsiteReq.customHeader.Set("User-Agent", appUserAgent)
+
+	return putBucketWebsiteReq, nil
+}
+
+// putBucketWebsiteVerify - Verify the response returned matches what is expected.
+func putBucketWebsiteVerify(res *http.Response, expectedStatusCode int) error {
+	if err := verifyStatusPutBucketWebsite(res.StatusCode, expectedStatusCode); err != nil {
+		return err
+	}
+	if err := verifyHeaderPutBucketWebsite(res.Header); err != nil {
+		return err
+	}
+	if err := verifyBodyPutBucketWebsite(res.Body); err != nil {
+		return err
+	}
+	return nil
+}
+
+// verifyStatusPutBucketWebsite - verify the status returned matches what is expected.
+func verifyStatusPutBucketWebsite(respStatusCode, expectedStatusCode int) error {
+	if respStatusCode != expectedStatusCode {
+		err := fmt.Errorf("Unexpected Status Received: wanted %v, got %v", expectedStatusCode, respStatusCode)
+		return err
+	}
+	return nil
+}
+
+// verifyHeaderPutBucketWebsite - verify the header returned matches what is expected.
+func verifyHeaderPutBucketWebsite(header http.Header) error {
+	if err := verifyStandardHeaders(header); err != nil {
+		return err
+	}
+	return nil
+}
+
+// verifyBodyPutBucketWebsite - verify the body returned is empty.
+func verifyBodyPutBucketWebsite(resBody io.Reader) error {
+	body, err := ioutil.ReadAll(resBody)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(body, []byte{}) {
+		err := fmt.Errorf("Unexpected Body Received: %v", string(body))
+		return err
+	}
+	return nil
+}
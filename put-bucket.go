@@ -169,6 +169,20 @@ func verifyHeaderPutBucket(header http.Header, bucketName string, expectedStatus
 	return nil
 }
 
+// putBucket - create bucketName in the configured region and verify the response.
+func putBucket(config ServerConfig, bucketName string) error {
+	req, err := newPutBucketReq(config.Region, bucketName)
+	if err != nil {
+		return err
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	return putBucketVerify(res, bucketName, http.StatusOK, ErrorResponse{})
+}
+
 // mainPutBucket- entry point for the putBucket test with valid names.
 func mainPutBucket(config ServerConfig, curTest int) bool {
 	message := fmt.Sprintf("[%02d/%d] PutBucket (Valid Names):", curTest, globalTotalNumTest)
@@ -194,11 +208,12 @@ func mainPutBucket(config ServerConfig, curTest int) bool {
 			printMessage(message, err)
 			return false
 		}
-		defer closeResponse(res)
 		// Spin the scanBar
 		scanBar(message)
 		// Check the responses Body, Status, Header.
-		if err := putBucketVerify(res, validBucket.Name, http.StatusOK, ErrorResponse{}); err != nil {
+		err = putBucketVerify(res, validBucket.Name, http.StatusOK, ErrorResponse{})
+		drainAndClose(res)
+		if err != nil {
 			printMessage(message, err)
 			return false
 		}
@@ -236,11 +251,12 @@ func mainPutBucketInvalid(config ServerConfig, curTest int) bool {
 			printMessage(message, err)
 			return false
 		}
-		defer closeResponse(res)
 		// Spin scanBar
 		scanBar(message)
 		// Verify that the request failed as predicted.
-		if err := putBucketVerify(res, bucket.Name, 400, expectedError); err != nil {
+		err = putBucketVerify(res, bucket.Name, 400, expectedError)
+		drainAndClose(res)
+		if err != nil {
 			printMessage(message, err)
 			return false
 		}
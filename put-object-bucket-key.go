@@ -0,0 +1,170 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// The header asking for, and reporting, an S3 Bucket Key to encrypt SSE-KMS objects with.
+const sseBucketKeyEnabledHeader = "x-amz-server-side-encryption-bucket-key-enabled"
+
+// verifyHeaderBucketKeyEnabled - verify the response reports SSE-KMS with a bucket key.
+func verifyHeaderBucketKeyEnabled(header http.Header) error {
+	if err := verifyHeaderSSEKMS(header, globalKMSKeyID); err != nil {
+		return err
+	}
+	if enabled := header.Get(sseBucketKeyEnabledHeader); enabled != "true" {
+		err := HeaderMismatchError{Header: sseBucketKeyEnabledHeader, Expected: "true", Got: enabled}
+		return err
+	}
+	return nil
+}
+
+// putBucketKeyEncryption - make bucketName encrypt new objects with SSE-KMS and a bucket key by default.
+// Returns false without an error if the server does not implement it.
+func putBucketKeyEncryption(config ServerConfig, bucketName string) (bool, error) {
+	encryptionConfig := sseConfiguration{
+		Rules: []sseRule{
+			sseRule{
+				ApplyServerSideEncryptionByDefault: applySSEByDefault{
+					SSEAlgorithm:   "aws:kms",
+					KMSMasterKeyID: globalKMSKeyID,
+				},
+				BucketKeyEnabled: true,
+			},
+		},
+	}
+	req, err := newPutBucketEncryptionReq(bucketName, encryptionConfig)
+	if err != nil {
+		return false, err
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		return false, err
+	}
+	defer drainAndClose(res)
+	if res.StatusCode != http.StatusOK && isNotImplemented(res) {
+		return false, nil
+	}
+	return true, putBucketEncryptionVerify(res, http.StatusOK)
+}
+
+// putObjectBucketKey - upload object, asking for SSE-KMS with a bucket key if requested, and verify the
+// upload and a HEAD of it both report the bucket key. Returns false without an error if the server
+// does not implement it.
+func putObjectBucketKey(config ServerConfig, bucketName string, object *ObjectInfo, requested bool) (bool, error) {
+	req, err := newPutObjectReq(bucketName, object.Key, object.Body)
+	if err != nil {
+		return false, err
+	}
+	if requested {
+		setSSEKMSHeaders(req, globalKMSKeyID, "")
+		req.customHeader.Set(sseBucketKeyEnabledHeader, "true")
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		return false, err
+	}
+	defer drainAndClose(res)
+	if res.StatusCode != http.StatusOK && isNotImplemented(res) {
+		return false, nil
+	}
+	if err := putObjectVerify(res, http.StatusOK); err != nil {
+		return true, err
+	}
+	if err := verifyHeaderBucketKeyEnabled(res.Header); err != nil {
+		return true, err
+	}
+	header, err := headObject(config, bucketName, object.Key)
+	if err != nil {
+		return true, err
+	}
+	if err := verifyHeaderBucketKeyEnabled(header); err != nil {
+		err = fmt.Errorf("HEAD: %v", err)
+		return true, err
+	}
+	return true, nil
+}
+
+// mainPutObjectBucketKey - Test the S3 Bucket Key of SSE-KMS is honored and reported, both when asked for
+// on PutObject and when set in the default encryption of a bucket.
+func mainPutObjectBucketKey(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] PutObject (SSE-KMS, Bucket Key):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	seed := time.Now().UnixNano()
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/put/bucket-key",
+		Body: generatedBody(seed, 1024),
+	}
+	supported, err := putObjectBucketKey(config, bucketName, object, true)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if !supported {
+		printMessage(message, nil)
+		printDetail("Skipped: server does not support SSE-KMS")
+		return true
+	}
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// A bucket whose default encryption enables the bucket key applies it to objects uploaded without asking.
+	defaultBucketName := "s3verify-" + globalSuffix + "-bucket-key"
+	if err := putBucket(config, defaultBucketName); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	supported, err = putBucketKeyEncryption(config, defaultBucketName)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if supported {
+		// Spin scanBar
+		scanBar(message)
+		defaultObject := &ObjectInfo{
+			Key:  "s3verify/put/bucket-key-default",
+			Body: generatedBody(seed+1, 1024),
+		}
+		if _, err := putObjectBucketKey(config, defaultBucketName, defaultObject, false); err != nil {
+			err = fmt.Errorf("Default encryption of %s: %v", defaultBucketName, err)
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := removeBucketWithContents(config, defaultBucketName); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	if !supported {
+		printDetail("Skipped: server does not support default bucket encryption")
+	}
+	return true
+}
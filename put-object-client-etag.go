@@ -0,0 +1,93 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// The ETag request header sent with the upload, a well-formed MD5 that is not that of the body.
+const bogusClientETag = "\"0123456789abcdef0123456789abcdef\""
+
+// newPutObjectClientETagReq - Create a new HTTP request for PUT object that also sends an ETag request header.
+func newPutObjectClientETagReq(bucketName, objectName string, objectData []byte, eTag string) (Request, error) {
+	putObjectReq, err := newPutObjectReq(bucketName, objectName, objectData)
+	if err != nil {
+		return Request{}, err
+	}
+	// ETag is a response header, a server must compute its own rather than store this one.
+	putObjectReq.customHeader.Set("ETag", eTag)
+
+	return putObjectReq, nil
+}
+
+// mainPutObjectClientETag - Test an ETag request header sent with an upload is ignored: the PUT and a later
+// HEAD both report the MD5 of the body, not the value the client sent.
+func mainPutObjectClientETag(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] PutObject (Client ETag):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/put/client-etag",
+		Body: generatedBody(time.Now().UnixNano(), 1024),
+	}
+	req, err := newPutObjectClientETagReq(bucketName, object.Key, object.Body, bogusClientETag)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if err := putObjectVerify(res, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyObjectETag(object, res.Header.Get("ETag")); err != nil {
+		err = fmt.Errorf("PUT with ETag %v: %v", bogusClientETag, err)
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// What is stored must not be the client's value either.
+	headHeader, err := headObject(config, bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyObjectETag(object, headHeader.Get("ETag")); err != nil {
+		err = fmt.Errorf("HEAD after PUT with ETag %v: %v", bogusClientETag, err)
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,97 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// verifyHeaderEmptyMetadata - verify the metadata key emptyKey was returned with an empty value. A server
+// dropping the key and one replacing the value are told apart in the error.
+func verifyHeaderEmptyMetadata(header http.Header, emptyKey string) error {
+	headerName := "x-amz-meta-" + emptyKey
+	values, ok := header[http.CanonicalHeaderKey(headerName)]
+	if !ok {
+		err := fmt.Errorf("Missing Metadata Header: %v was sent with an empty value and dropped", headerName)
+		return err
+	}
+	if len(values) != 1 || values[0] != "" {
+		err := HeaderMismatchError{Header: headerName, Expected: "an empty value", Got: fmt.Sprintf("%q", values)}
+		return err
+	}
+	return nil
+}
+
+// mainPutObjectEmptyMetadata - verify a user metadata key sent with an empty value is returned by HEAD with an
+// empty value rather than dropped. A key with a value is sent alongside so losing all metadata is told apart.
+func mainPutObjectEmptyMetadata(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] PutObject (Empty Metadata):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/put/empty-metadata",
+		Body: []byte("s3verify empty metadata"),
+	}
+	emptyKey := "s3verify-empty"
+	metadata := map[string]string{
+		emptyKey:       "",
+		"s3verify-set": "set",
+	}
+	req, err := newPutObjectMetadataReq(bucketName, object.Key, object.Body, "", metadata)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if err := putObjectVerify(res, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	headHeader, err := headObject(config, bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if received := headHeader.Get("x-amz-meta-s3verify-set"); received != "set" {
+		err := HeaderMismatchError{Header: "x-amz-meta-s3verify-set", Expected: "set", Got: received}
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyHeaderEmptyMetadata(headHeader, emptyKey); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	printDetail("x-amz-meta-" + emptyKey + " was kept with an empty value")
+	return true
+}
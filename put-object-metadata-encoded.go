@@ -0,0 +1,139 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+)
+
+// Header holding the non-ASCII user metadata value.
+const encodedMetadataHeader = "X-Amz-Meta-S3verify-Encoded"
+
+// A metadata value that can only be sent in a header once RFC 2047 encoded.
+const encodedMetadataValue = "s3verify: héllo wörld, ☃"
+
+// verifyHeaderEncodedMetadata - verify the metadata value returned decodes to the original string.
+//
+// S3 decodes RFC 2047 encoded-words before storing a metadata value and encodes it again
+// when returning it, so the value received may be in either Q or B encoding but it must
+// always decode to exactly what was sent.
+func verifyHeaderEncodedMetadata(header http.Header, expectedValue string) error {
+	received := header.Get(encodedMetadataHeader)
+	if received == "" {
+		err := fmt.Errorf("Missing Metadata Header: %v was not returned", encodedMetadataHeader)
+		return err
+	}
+	decoder := new(mime.WordDecoder)
+	decoded, err := decoder.DecodeHeader(received)
+	if err != nil {
+		err = fmt.Errorf("Unexpected Metadata Value Received: %q is not a valid RFC 2047 value: %v", received, err)
+		return err
+	}
+	if decoded != expectedValue {
+		err := HeaderMismatchError{
+			Header:   encodedMetadataHeader,
+			Expected: expectedValue,
+			Got:      decoded,
+			Detail:   "decoded from " + received,
+		}
+		return err
+	}
+	return nil
+}
+
+// mainPutObjectEncodedMetadata - verify a non-ASCII metadata value sent RFC 2047 encoded round-trips.
+func mainPutObjectEncodedMetadata(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] PutObject (Encoded Metadata):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/put/encoded-metadata",
+		Body: []byte("s3verify encoded metadata"),
+	}
+	req, err := newPutObjectReq(bucketName, object.Key, object.Body)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	req.customHeader.Set(encodedMetadataHeader, mime.QEncoding.Encode("UTF-8", encodedMetadataValue))
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if err := putObjectVerify(res, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Both HEAD and GET return the metadata.
+	headReq, err := newHeadObjectReq(bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	headRes, err := config.execRequest("HEAD", headReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(headRes)
+	if err := headObjectVerify(headRes, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyHeaderEncodedMetadata(headRes.Header, encodedMetadataValue); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	getReq, err := newGetObjectReq(bucketName, object.Key, nil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	getRes, err := config.execRequest("GET", getReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(getRes)
+	if err := getObjectVerify(getRes, object.Body, http.StatusOK, nil); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyHeaderEncodedMetadata(getRes.Header, encodedMetadataValue); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Remove the object so it does not interfere with future tests.
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
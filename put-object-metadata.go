@@ -0,0 +1,146 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"unicode/utf8"
+)
+
+// newPutObjectMetadataReq - Create a new HTTP request for PUT object with a Content-Type and user metadata.
+// Metadata keys are given without the x-amz-meta- prefix. Values that are not ASCII are sent RFC 2047 encoded
+// since headers can only carry ASCII.
+func newPutObjectMetadataReq(bucketName, objectName string, objectData []byte, contentType string, metadata map[string]string) (Request, error) {
+	putObjectReq, err := newPutObjectReq(bucketName, objectName, objectData)
+	if err != nil {
+		return Request{}, err
+	}
+	if contentType != "" {
+		putObjectReq.customHeader.Set("Content-Type", contentType)
+	}
+	for key, value := range metadata {
+		if !isASCII(value) {
+			value = mime.QEncoding.Encode("UTF-8", value)
+		}
+		putObjectReq.customHeader.Set("x-amz-meta-"+key, value)
+	}
+	return putObjectReq, nil
+}
+
+// isASCII - report whether s only holds ASCII characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// verifyHeaderObjectMetadata - verify the Content-Type and every metadata value returned match what was sent.
+// Header names are compared case-insensitively, a key re-cased by the server is still found.
+func verifyHeaderObjectMetadata(header http.Header, contentType string, metadata map[string]string) error {
+	if received := header.Get("Content-Type"); received != contentType {
+		err := HeaderMismatchError{Header: "Content-Type", Expected: contentType, Got: received}
+		return err
+	}
+	decoder := new(mime.WordDecoder)
+	for key, value := range metadata {
+		headerName := "x-amz-meta-" + key
+		if _, ok := header[http.CanonicalHeaderKey(headerName)]; !ok {
+			err := fmt.Errorf("Missing Metadata Header: %v was not returned", headerName)
+			return err
+		}
+		received := header.Get(headerName)
+		decoded, err := decoder.DecodeHeader(received)
+		if err != nil {
+			err = fmt.Errorf("Unexpected Metadata Value Received for %v: %q is not a valid RFC 2047 value: %v", headerName, received, err)
+			return err
+		}
+		if decoded != value {
+			err := HeaderMismatchError{Header: headerName, Expected: value, Got: decoded}
+			return err
+		}
+	}
+	return nil
+}
+
+// mainPutObjectMetadata - verify the Content-Type and user metadata an object is uploaded with are returned
+// unchanged by HEAD, including values with spaces or non-ASCII characters and keys sent in mixed case.
+func mainPutObjectMetadata(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] PutObject (Metadata):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/put/metadata",
+		Body: []byte("s3verify metadata"),
+	}
+	contentType := "application/custom"
+	metadata := map[string]string{
+		"s3verify-spaces":    "a value with spaces",
+		"S3Verify-MixedCase": "mixed case key",
+		"s3verify-unicode":   "héllo wörld",
+	}
+	req, err := newPutObjectMetadataReq(bucketName, object.Key, object.Body, contentType, metadata)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if err := putObjectVerify(res, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	headReq, err := newHeadObjectReq(bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	headRes, err := config.execRequest("HEAD", headReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(headRes)
+	if err := headObjectVerify(headRes, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyHeaderObjectMetadata(headRes.Header, contentType, metadata); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,192 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// sendWithoutLength - send a signed request with neither Content-Length nor Transfer-Encoding.
+// net/http always adds one of the two to a PUT so the request is written by hand.
+func sendWithoutLength(config ServerConfig, method string, customReq Request) (*http.Response, error) {
+	req, err := config.newRequest(method, customReq)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := dialRaw(config, req.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	writer := bufio.NewWriter(conn)
+	fmt.Fprintf(writer, "%s %s HTTP/1.1\r\nHost: %s\r\n", method, req.URL.RequestURI(), req.URL.Host)
+	if err := req.Header.Write(writer); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(writer, "Connection: close\r\n\r\n")
+	if err := writer.Flush(); err != nil {
+		return nil, err
+	}
+	res, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return nil, err
+	}
+	// Read the body before the connection is closed.
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return res, nil
+}
+
+// verifyBodyMissingContentLength - verify the body returned is a MissingContentLength error.
+func verifyBodyMissingContentLength(resBody io.Reader) error {
+	errResponse := ErrorResponse{}
+	if err := xmlDecoder(resBody, &errResponse); err != nil {
+		return err
+	}
+	if errResponse.Code != "MissingContentLength" {
+		err := fmt.Errorf("Unexpected Error Response: wanted MissingContentLength, got %v", errResponse.Code)
+		return err
+	}
+	return nil
+}
+
+// putObjectNoLengthVerify - verify a PUT without a length was refused with 411 Length Required.
+func putObjectNoLengthVerify(res *http.Response) error {
+	if err := verifyStatusPutObject(res.StatusCode, http.StatusLengthRequired); err != nil {
+		return err
+	}
+	if err := verifyBodyMissingContentLength(res.Body); err != nil {
+		return err
+	}
+	return nil
+}
+
+// newPutObjectChunkedReq - Create a new HTTP request for PUT object that sends objectData with chunked
+// transfer encoding instead of a Content-Length.
+func newPutObjectChunkedReq(bucketName, objectName string, objectData []byte) (Request, error) {
+	putObjectReq, err := newPutObjectReq(bucketName, objectName, objectData)
+	if err != nil {
+		return Request{}, err
+	}
+	putObjectReq.chunked = true
+	return putObjectReq, nil
+}
+
+// putObjectChunkedVerify - verify a chunked PUT was either stored, or refused for its missing length
+// with 411 Length Required or as not implemented.
+func putObjectChunkedVerify(res *http.Response) (stored bool, err error) {
+	switch res.StatusCode {
+	case http.StatusOK:
+		return true, verifyStandardHeaders(res.Header)
+	case http.StatusLengthRequired:
+		return false, putObjectNoLengthVerify(res)
+	case http.StatusNotImplemented:
+		return false, verifyStandardHeaders(res.Header)
+	}
+	err = fmt.Errorf("Unexpected Response Status Code: wanted 200, 411 or 501, got %v", res.StatusCode)
+	return false, err
+}
+
+// putObjectChunked - send objectData with chunked transfer encoding and verify it was either stored
+// intact or refused without storing anything.
+func putObjectChunked(config ServerConfig, bucketName, objectName string, objectData []byte) error {
+	req, err := newPutObjectChunkedReq(bucketName, objectName, objectData)
+	if err != nil {
+		return err
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		return err
+	}
+	stored, err := putObjectChunkedVerify(res)
+	drainAndClose(res)
+	if err != nil {
+		return err
+	}
+	if !stored {
+		return listObjectVisibility(config, bucketName, objectName, false)
+	}
+	getReq, err := newGetObjectReq(bucketName, objectName, nil)
+	if err != nil {
+		return err
+	}
+	getRes, err := config.execRequest("GET", getReq)
+	if err != nil {
+		return err
+	}
+	err = getObjectVerify(getRes, objectData, http.StatusOK, nil)
+	drainAndClose(getRes)
+	if err != nil {
+		return err
+	}
+	return removeObject(config, bucketName, objectName)
+}
+
+// mainPutObjectNoLength - verify a PUT with neither Content-Length nor chunked encoding is refused,
+// and a PUT with chunked encoding instead of Content-Length is stored intact or refused.
+func mainPutObjectNoLength(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] PutObject (No Content-Length):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	objectName := "s3verify/put/no-length"
+	// The request is signed for an empty body, none is sent.
+	req, err := newPutObjectReq(bucketName, objectName, []byte{})
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	res, err := sendWithoutLength(config, "PUT", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	// Spin scanBar
+	scanBar(message)
+	if err := putObjectNoLengthVerify(res); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Nothing may have been stored.
+	if err := listObjectVisibility(config, bucketName, objectName, false); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := putObjectChunked(config, bucketName, "s3verify/put/chunked", []byte("s3verify chunked body")); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,90 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Test that the chunked PUT is sent without a Content-Length and that a server refusing it with
+// 411 Length Required passes only when the refusal is a MissingContentLength error and nothing was stored.
+func TestPutObjectChunked(t *testing.T) {
+	testCases := []struct {
+		status int
+		code   string
+		listed bool   // Whether the listing after the refusal shows the object.
+		err    string // The prefix of the error expected, empty if the outcome is accepted.
+	}{
+		// The documented outcome.
+		{http.StatusLengthRequired, "MissingContentLength", false, ""},
+		{http.StatusNotImplemented, "NotImplemented", false, ""},
+		// Refused for another reason.
+		{http.StatusLengthRequired, "InvalidRequest", false, "Unexpected Error Response: wanted MissingContentLength, got InvalidRequest"},
+		{http.StatusBadRequest, "InvalidRequest", false, "Unexpected Response Status Code: wanted 200, 411 or 501, got 400"},
+		// Refused but stored anyway.
+		{http.StatusLengthRequired, "MissingContentLength", true, "Unexpected Listing for s3verify/put/chunked"},
+	}
+	for i, testCase := range testCases {
+		var transferEncoding []string
+		var contentLength int64
+		var body []byte
+		puts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "PUT" {
+				puts++
+				transferEncoding = r.TransferEncoding
+				contentLength = r.ContentLength
+				body, _ = ioutil.ReadAll(r.Body)
+				writeS3Error(w, testCase.status, testCase.code, "")
+				return
+			}
+			contents := ""
+			if testCase.listed {
+				contents = "<Contents><Key>s3verify/put/chunked</Key></Contents>"
+			}
+			writeS3Headers(w)
+			fmt.Fprintf(w, `<ListBucketResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><IsTruncated>false</IsTruncated>%s</ListBucketResult>`, contents)
+		}))
+
+		config := newTestConfig(server.URL)
+		err := putObjectChunked(config, "s3verify-test", "s3verify/put/chunked", []byte("s3verify chunked body"))
+		server.Close()
+		if testCase.err == "" && err != nil {
+			t.Errorf("Test %d: Expected no error, got %v", i+1, err)
+		}
+		if testCase.err != "" && (err == nil || !strings.HasPrefix(err.Error(), testCase.err)) {
+			t.Errorf("Test %d: Expected error %q, got %v", i+1, testCase.err, err)
+		}
+		if puts != 1 {
+			t.Fatalf("Test %d: Expected one PUT, got %d", i+1, puts)
+		}
+		if strings.Join(transferEncoding, ",") != "chunked" {
+			t.Errorf("Test %d: Expected Transfer-Encoding [chunked], got %v", i+1, transferEncoding)
+		}
+		if contentLength != -1 {
+			t.Errorf("Test %d: Expected no Content-Length, got %d", i+1, contentLength)
+		}
+		if string(body) != "s3verify chunked body" {
+			t.Errorf("Test %d: Expected the body to arrive intact, got %q", i+1, body)
+		}
+	}
+}
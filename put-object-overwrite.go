@@ -0,0 +1,176 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	crand "crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// verifyOverwriteModified - verify a second identical PUT moved Last-Modified forward and kept the ETag.
+func verifyOverwriteModified(first, second http.Header) error {
+	if first.Get("ETag") != second.Get("ETag") {
+		err := HeaderMismatchError{
+			Header:   "ETag",
+			Expected: first.Get("ETag"),
+			Got:      second.Get("ETag"),
+			Detail:   "identical content was written twice",
+		}
+		return err
+	}
+	firstModified, err := time.Parse(http.TimeFormat, first.Get("Last-Modified"))
+	if err != nil {
+		return err
+	}
+	secondModified, err := time.Parse(http.TimeFormat, second.Get("Last-Modified"))
+	if err != nil {
+		return err
+	}
+	if !secondModified.After(firstModified) {
+		err := HeaderMismatchError{
+			Header:   "Last-Modified",
+			Expected: "later than " + first.Get("Last-Modified"),
+			Got:      second.Get("Last-Modified"),
+			Detail:   "the object was overwritten",
+		}
+		return err
+	}
+	return nil
+}
+
+// mainPutObjectOverwrite - PUT identical content to the same key twice and verify Last-Modified
+// records the time of the latest write rather than of the latest change in content.
+func mainPutObjectOverwrite(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] PutObject (Overwrite Last-Modified):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/put/overwrite",
+		Body: []byte("s3verify overwrite"),
+	}
+	if _, err := putObject(config, bucketName, object); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	first, err := headObject(config, bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Last-Modified has a resolution of one second, wait long enough for it to change.
+	for i := 0; i < 4; i++ {
+		// Spin scanBar
+		scanBar(message)
+		time.Sleep(500 * time.Millisecond)
+	}
+	if _, err := putObject(config, bucketName, object); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	second, err := headObject(config, bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := verifyOverwriteModified(first, second); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Remove the object so it does not interfere with future tests.
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	printDetail("Last-Modified moved from " + first.Get("Last-Modified") + " to " + second.Get("Last-Modified"))
+	return true
+}
+
+// mainPutObjectOverwriteSmaller - overwrite a large object with a much smaller one and verify
+// GET returns exactly the smaller object, without stale bytes left over from the larger one.
+func mainPutObjectOverwriteSmaller(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] PutObject (Overwrite Smaller):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	large := &ObjectInfo{
+		Key:  "s3verify/put/overwrite-smaller",
+		Body: make([]byte, 2*1024*1024),
+	}
+	if _, err := io.ReadFull(crand.Reader, large.Body); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// A server that only overwrites in place returns these bytes followed by the old tail.
+	small := &ObjectInfo{
+		Key:  large.Key,
+		Body: []byte("s3verify overwrite smaller"),
+	}
+	if _, err := putObject(config, bucketName, large); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if _, err := putObject(config, bucketName, small); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	req, err := newGetObjectReq(bucketName, small.Key, nil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if contentLength := res.Header.Get("Content-Length"); contentLength != strconv.Itoa(len(small.Body)) {
+		err := HeaderMismatchError{
+			Header:   "Content-Length",
+			Expected: strconv.Itoa(len(small.Body)),
+			Got:      contentLength,
+			Detail:   "the object was overwritten",
+		}
+		printMessage(message, err)
+		return false
+	}
+	if err := getObjectVerify(res, small.Body, http.StatusOK, nil); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Remove the object so it does not interfere with future tests.
+	if err := removeObject(config, bucketName, small.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,135 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Keys that are legal in S3 but are changed by servers that normalize them as paths.
+var slashKeys = []string{
+	"/s3verify/slash/leading",
+	"s3verify/slash/double//slash",
+	"s3verify/slash/trailing/",
+}
+
+// Prefixes under which all slashKeys, and anything a normalizing server stores them as, are listed.
+var slashKeyPrefixes = []string{
+	"/s3verify/slash/",
+	"s3verify/slash/",
+}
+
+// verifySlashKeysListed - verify exactly the keys uploaded are listed, describing any the server
+// stored under a different name.
+func verifySlashKeysListed(listedKeys, expectedKeys []string) error {
+	expected := make(map[string]bool)
+	for _, key := range expectedKeys {
+		expected[key] = true
+	}
+	listed := make(map[string]bool)
+	unexpected := []string{}
+	for _, key := range listedKeys {
+		listed[key] = true
+		if !expected[key] {
+			unexpected = append(unexpected, fmt.Sprintf("%q", key))
+		}
+	}
+	missing := []string{}
+	for _, key := range expectedKeys {
+		if !listed[key] {
+			missing = append(missing, fmt.Sprintf("%q", key))
+		}
+	}
+	if len(missing) == 0 && len(unexpected) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	sort.Strings(unexpected)
+	err := fmt.Errorf("Keys Normalized: uploaded %s but listed %s instead", strings.Join(missing, ", "), strings.Join(unexpected, ", "))
+	return err
+}
+
+// mainPutObjectSlashKeys - verify keys with leading, repeated and trailing slashes round-trip exactly.
+func mainPutObjectSlashKeys(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] PutObject (Slashes in Keys):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	for _, key := range slashKeys {
+		object := &ObjectInfo{
+			Key:  key,
+			Body: []byte(key),
+		}
+		if _, err := putObject(config, bucketName, object); err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Spin scanBar
+	scanBar(message)
+	// List everything a server might have stored the keys as.
+	listedKeys := []string{}
+	for _, prefix := range slashKeyPrefixes {
+		keys, err := listKeysWithPrefix(config, bucketName, prefix)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		listedKeys = append(listedKeys, keys...)
+	}
+	if err := verifySlashKeysListed(listedKeys, slashKeys); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Each object must be retrievable by the exact key it was uploaded with.
+	for _, key := range slashKeys {
+		req, err := newGetObjectReq(bucketName, key, nil)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		res, err := config.execRequest("GET", req)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		err = getObjectVerify(res, []byte(key), http.StatusOK, nil)
+		drainAndClose(res)
+		if err != nil {
+			err = fmt.Errorf("GET %q: %v", key, err)
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Remove the objects so they do not interfere with future tests.
+	for _, key := range slashKeys {
+		if err := removeObject(config, bucketName, key); err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,184 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// The encryption context header, a base64 encoded JSON object bound to the data key.
+const sseContextHeader = "x-amz-server-side-encryption-context"
+
+// sseKMSKeyNotFoundCodes - the error codes servers use to reject an upload naming a KMS key that does not exist.
+var sseKMSKeyNotFoundCodes = []string{"KMS.NotFoundException", "KMSKeyNotFoundException"}
+
+// setSSEKMSHeaders - ask for the object to be encrypted with keyID, or the default KMS key if keyID is empty.
+func setSSEKMSHeaders(req Request, keyID, context string) {
+	req.customHeader.Set(sseHeader, "aws:kms")
+	if keyID != "" {
+		req.customHeader.Set(sseKMSKeyIDHeader, keyID)
+	}
+	if context != "" {
+		req.customHeader.Set(sseContextHeader, base64.StdEncoding.EncodeToString([]byte(context)))
+	}
+}
+
+// verifyHeaderSSEKMS - verify the response reports aws:kms and, if one was asked for, the key used.
+// A key id may be echoed back as its full ARN.
+func verifyHeaderSSEKMS(header http.Header, keyID string) error {
+	if algorithm := header.Get(sseHeader); algorithm != "aws:kms" {
+		err := HeaderMismatchError{Header: sseHeader, Expected: "aws:kms", Got: algorithm}
+		return err
+	}
+	if keyID == "" {
+		return nil
+	}
+	if got := header.Get(sseKMSKeyIDHeader); got != keyID && !strings.HasSuffix(got, "/"+keyID) {
+		err := HeaderMismatchError{Header: sseKMSKeyIDHeader, Expected: keyID, Got: got}
+		return err
+	}
+	return nil
+}
+
+// verifyKMSKeyNotFound - verify an upload naming a KMS key that does not exist was rejected.
+func verifyKMSKeyNotFound(res *http.Response) error {
+	if err := verifyStatusPutObject(res.StatusCode, http.StatusBadRequest); err != nil {
+		return err
+	}
+	errResponse, err := parseErrorResponse(res.Body)
+	if err != nil {
+		return err
+	}
+	for _, code := range sseKMSKeyNotFoundCodes {
+		if errResponse.Code == code {
+			return nil
+		}
+	}
+	err = fmt.Errorf("Unexpected Error Response: wanted one of %v, got %v", strings.Join(sseKMSKeyNotFoundCodes, ", "), errResponse.Code)
+	return err
+}
+
+// newMissingKMSKeyID - a well-formed key id that no KMS will have.
+func newMissingKMSKeyID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:]), nil
+}
+
+// mainSSEKMSObject - Test an object uploaded with SSE-KMS reports its key and reads back as plaintext.
+// The key is the one given with --kms-key-id, or the default KMS key of the server.
+func mainSSEKMSObject(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] PutObject (SSE-KMS):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/put/sse-kms",
+		Body: generatedBody(time.Now().UnixNano(), 1024),
+	}
+	req, err := newPutObjectReq(bucketName, object.Key, object.Body)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	setSSEKMSHeaders(req, globalKMSKeyID, `{"s3verify":"sse-kms"}`)
+	// Spin scanBar
+	scanBar(message)
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if res.StatusCode != http.StatusOK && isNotImplemented(res) {
+		printMessage(message, nil)
+		printDetail("Skipped: server does not support SSE-KMS")
+		return true
+	}
+	if err := putObjectVerify(res, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyHeaderSSEKMS(res.Header, globalKMSKeyID); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := verifySSEConsistency(config, bucketName, object.Key, res.Header); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// The object is decrypted transparently.
+	getReq, err := newGetObjectReq(bucketName, object.Key, nil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	getRes, err := config.execRequest("GET", getReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(getRes)
+	if err := getObjectVerify(getRes, object.Body, http.StatusOK, nil); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// A key that does not exist must be rejected rather than silently replaced by another.
+	missingKeyID, err := newMissingKMSKeyID()
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	badReq, err := newPutObjectReq(bucketName, object.Key+"-missing-key", object.Body)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	setSSEKMSHeaders(badReq, missingKeyID, "")
+	badRes, err := config.execRequest("PUT", badReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(badRes)
+	if err := verifyKMSKeyNotFound(badRes); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,129 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// The headers describing how the server encrypted an object with its own keys.
+const (
+	sseHeader         = "x-amz-server-side-encryption"
+	sseKMSKeyIDHeader = "x-amz-server-side-encryption-aws-kms-key-id"
+)
+
+// sseHeaders - the encryption headers that must be reported identically for an object on PUT, HEAD and GET.
+var sseHeaders = []string{sseHeader, sseKMSKeyIDHeader}
+
+// verifySSEConsistency - verify HEAD and GET of objectName report the same encryption headers as putHeader,
+// the response to its upload. A difference means the encryption state was not persisted with the object.
+func verifySSEConsistency(config ServerConfig, bucketName, objectName string, putHeader http.Header) error {
+	headHeader, err := headObject(config, bucketName, objectName)
+	if err != nil {
+		return err
+	}
+	req, err := newGetObjectReq(bucketName, objectName, nil)
+	if err != nil {
+		return err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	if err := verifyStatusGetObject(res.StatusCode, http.StatusOK); err != nil {
+		return err
+	}
+	surfaces := []struct {
+		method string
+		header http.Header
+	}{
+		{"HEAD", headHeader},
+		{"GET", res.Header},
+	}
+	for _, surface := range surfaces {
+		for _, name := range sseHeaders {
+			if got := surface.header.Get(name); got != putHeader.Get(name) {
+				err := HeaderMismatchError{
+					Header:   name,
+					Expected: putHeader.Get(name),
+					Got:      got,
+					Detail:   surface.method + " must report what the PUT response did",
+				}
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mainPutObjectSSES3 - Test an object uploaded with SSE-S3 reports the same encryption on PUT, HEAD and GET.
+func mainPutObjectSSES3(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] PutObject (SSE-S3):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/put/sse-s3",
+		Body: generatedBody(time.Now().UnixNano(), 1024),
+	}
+	req, err := newPutObjectReq(bucketName, object.Key, object.Body)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	req.customHeader.Set(sseHeader, "AES256")
+	// Spin scanBar
+	scanBar(message)
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if res.StatusCode != http.StatusOK && isNotImplemented(res) {
+		printMessage(message, nil)
+		printDetail("Skipped: server does not support SSE-S3")
+		return true
+	}
+	if err := putObjectVerify(res, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if algorithm := res.Header.Get(sseHeader); algorithm != "AES256" {
+		err := HeaderMismatchError{Header: sseHeader, Expected: "AES256", Got: algorithm}
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := verifySSEConsistency(config, bucketName, object.Key, res.Header); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,196 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// The headers carrying a customer-provided encryption key.
+const (
+	sseCustomerAlgorithmHeader = "x-amz-server-side-encryption-customer-algorithm"
+	sseCustomerKeyHeader       = "x-amz-server-side-encryption-customer-key"
+	sseCustomerKeyMD5Header    = "x-amz-server-side-encryption-customer-key-MD5"
+)
+
+// newSSECustomerKey - generate a random 256-bit key for SSE-C.
+func newSSECustomerKey() ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// sseCustomerKeyMD5 - the base64 encoded MD5 of key, as sent and returned in the key-MD5 header.
+func sseCustomerKeyMD5(key []byte) string {
+	md5Sum := md5.Sum(key)
+	return base64.StdEncoding.EncodeToString(md5Sum[:])
+}
+
+// setSSECustomerHeaders - attach the SSE-C headers for key to a request.
+func setSSECustomerHeaders(req Request, key []byte) {
+	req.customHeader.Set(sseCustomerAlgorithmHeader, "AES256")
+	req.customHeader.Set(sseCustomerKeyHeader, base64.StdEncoding.EncodeToString(key))
+	req.customHeader.Set(sseCustomerKeyMD5Header, sseCustomerKeyMD5(key))
+}
+
+// verifyHeaderSSECustomer - verify the response names the algorithm and echoes the MD5 of key.
+func verifyHeaderSSECustomer(header http.Header, key []byte) error {
+	if algorithm := header.Get(sseCustomerAlgorithmHeader); algorithm != "AES256" {
+		err := HeaderMismatchError{Header: sseCustomerAlgorithmHeader, Expected: "AES256", Got: algorithm}
+		return err
+	}
+	if keyMD5 := header.Get(sseCustomerKeyMD5Header); keyMD5 != sseCustomerKeyMD5(key) {
+		err := HeaderMismatchError{Header: sseCustomerKeyMD5Header, Expected: sseCustomerKeyMD5(key), Got: keyMD5}
+		return err
+	}
+	return nil
+}
+
+// mainPutObjectSSEC - Test an object uploaded with a customer-provided key can only be read with that key.
+// SSE-C is only accepted over https so the test is skipped for http endpoints.
+func mainPutObjectSSEC(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] PutObject (SSE-C):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	endpointURL, err := url.Parse(config.Endpoint)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if endpointURL.Scheme != "https" {
+		printMessage(message, nil)
+		printDetail("Skipped: SSE-C requires an https endpoint")
+		return true
+	}
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/put/sse-c",
+		Body: generatedBody(time.Now().UnixNano(), 1024),
+	}
+	key, err := newSSECustomerKey()
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	putReq, err := newPutObjectReq(bucketName, object.Key, object.Body)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	setSSECustomerHeaders(putReq, key)
+	putRes, err := config.execRequest("PUT", putReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(putRes)
+	if isNotImplemented(putRes) {
+		printMessage(message, nil)
+		printDetail("Skipped: server does not support SSE-C")
+		return true
+	}
+	if err := putObjectVerify(putRes, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyHeaderSSECustomer(putRes.Header, key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// With the key the original bytes are returned.
+	getReq, err := newGetObjectReq(bucketName, object.Key, nil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	setSSECustomerHeaders(getReq, key)
+	getRes, err := config.execRequest("GET", getReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(getRes)
+	if err := getObjectVerify(getRes, object.Body, http.StatusOK, nil); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyHeaderSSECustomer(getRes.Header, key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Without the key the object can not be read.
+	noKeyReq, err := newGetObjectReq(bucketName, object.Key, nil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	noKeyRes, err := config.execRequest("GET", noKeyReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(noKeyRes)
+	if err := verifyStatusGetObject(noKeyRes.StatusCode, http.StatusBadRequest); err != nil {
+		err = fmt.Errorf("GET without the key: %v", err)
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// A wrong key must be refused rather than decrypt to garbage.
+	wrongKey, err := newSSECustomerKey()
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	headReq, err := newHeadObjectReq(bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	setSSECustomerHeaders(headReq, wrongKey)
+	headRes, err := config.execRequest("HEAD", headReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(headRes)
+	if headRes.StatusCode != http.StatusForbidden && headRes.StatusCode != http.StatusBadRequest {
+		err := fmt.Errorf("HEAD with the wrong key: Unexpected Status Received: wanted %v or %v, got %v", http.StatusForbidden, http.StatusBadRequest, headRes.StatusCode)
+		printMessage(message, err)
+		return false
+	}
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
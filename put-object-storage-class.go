@@ -0,0 +1,126 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// uploadStorageClasses - the non-STANDARD storage classes objects are uploaded to.
+var uploadStorageClasses = []string{"STANDARD_IA", "REDUCED_REDUNDANCY", "GLACIER"}
+
+// invalidStorageClass - a storage class no server knows.
+const invalidStorageClass = "S3VERIFY_INVALID"
+
+// newPutObjectStorageClassReq - Create a new HTTP request for PUT object stored in storageClass.
+func newPutObjectStorageClassReq(bucketName, objectName string, objectData []byte, storageClass string) (Request, error) {
+	req, err := newPutObjectReq(bucketName, objectName, objectData)
+	if err != nil {
+		return Request{}, err
+	}
+	req.customHeader.Set("x-amz-storage-class", storageClass)
+	return req, nil
+}
+
+// putObjectInvalidStorageClass - verify an upload to an unknown storage class is rejected with
+// InvalidStorageClass. Returns false without an error if the server does not implement storage classes.
+func putObjectInvalidStorageClass(config ServerConfig, bucketName, objectName string) (bool, error) {
+	req, err := newPutObjectStorageClassReq(bucketName, objectName, generatedBody(time.Now().UnixNano(), 1024), invalidStorageClass)
+	if err != nil {
+		return false, err
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		return false, err
+	}
+	defer drainAndClose(res)
+	if res.StatusCode == http.StatusOK {
+		// Remove the object so a failed run leaves nothing behind.
+		if err := removeObject(config, bucketName, objectName); err != nil {
+			return false, err
+		}
+		err := fmt.Errorf("Unexpected Response Status Code: wanted %v, got %v for storage class %v", http.StatusBadRequest, res.StatusCode, invalidStorageClass)
+		return false, err
+	}
+	if res.StatusCode == http.StatusNotImplemented {
+		return false, nil
+	}
+	if err := verifyError(res, http.StatusBadRequest, "InvalidStorageClass"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// mainPutObjectStorageClass - Verify objects uploaded to a non-STANDARD storage class are reported
+// in it by HEAD, and that an unknown storage class is rejected.
+func mainPutObjectStorageClass(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] PutObject (Storage Class):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	var unsupported []string
+	for _, storageClass := range uploadStorageClasses {
+		object := &ObjectInfo{
+			Key:  "s3verify/put/storage-class/" + strings.ToLower(storageClass),
+			Body: generatedBody(time.Now().UnixNano(), 1024),
+		}
+		supported, err := putObjectStorageClass(config, bucketName, object, storageClass)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		if !supported {
+			unsupported = append(unsupported, storageClass)
+			continue
+		}
+		// Spin scanBar
+		scanBar(message)
+		// An accepted storage class must be the one the object is stored in.
+		if err := verifyStorageClass(config, bucketName, object.Key, storageClass); err != nil {
+			printMessage(message, err)
+			return false
+		}
+		if err := removeObject(config, bucketName, object.Key); err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	if len(unsupported) == len(uploadStorageClasses) {
+		printMessage(message, nil)
+		printDetail("Skipped: server supports none of the storage classes " + strings.Join(uploadStorageClasses, ", "))
+		return true
+	}
+	// Spin scanBar
+	scanBar(message)
+	implemented, err := putObjectInvalidStorageClass(config, bucketName, "s3verify/put/storage-class/invalid")
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	if len(unsupported) > 0 {
+		printDetail("Skipped: server does not support the storage classes " + strings.Join(unsupported, ", "))
+	}
+	if !implemented {
+		printDetail("Warning: server answered an unknown storage class with NotImplemented instead of InvalidStorageClass")
+	}
+	return true
+}
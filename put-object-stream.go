@@ -0,0 +1,189 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Size of the generated object uploaded by the streaming PutObject test.
+const streamObjectSize = 256 * 1024 * 1024
+
+// mainPutObjectStream - Test a PUT object request with a large generated body that is never held in memory.
+func mainPutObjectStream(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] PutObject (Streamed):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	objectName := "s3verify/put/stream"
+	seed := time.Now().UnixNano()
+	// Create a new request, the body is hashed in a first pass and sent in a second.
+	req, err := newPutObjectStreamReq(bucketName, objectName, newGeneratedReader(seed, streamObjectSize))
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Execute the request. A Content-MD5 mismatch here means the body sent differs from the body hashed.
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if err := putObjectVerify(res, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Regenerate the body to find the expected ETag.
+	md5Sum, _, _, err := computeHash(newGeneratedReader(seed, streamObjectSize))
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if eTag := strings.Trim(res.Header.Get("ETag"), "\""); eTag != hex.EncodeToString(md5Sum) {
+		err := ChecksumMismatchError{Checksum: "ETag", Expected: hex.EncodeToString(md5Sum), Got: eTag}
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Download the object and compare it against the regenerated body.
+	getReq, err := newGetObjectReq(bucketName, objectName, nil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Time the download to tell streaming servers from those that buffer the whole object first.
+	timing, trace := newResponseTiming()
+	getReq.trace = trace
+	getRes, err := config.execRequest("GET", getReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(getRes)
+	timing.track(getRes)
+	if err := verifyStatusGetObject(getRes.StatusCode, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyBodyStream(getRes.Body, newGeneratedReader(seed, streamObjectSize)); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Remove the streamed object.
+	if err := removeObject(config, bucketName, objectName); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	printDetail("GET " + timing.String())
+	return true
+}
+
+// mainPutObjectReaderStream - Test a PUT object request whose body is read exactly once while it is sent.
+// The payload cannot be hashed before it is signed so it is sent unsigned, which requires an https endpoint.
+func mainPutObjectReaderStream(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] PutObject (Streamed, Unsigned):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	endpointURL, err := url.Parse(config.Endpoint)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if endpointURL.Scheme != "https" {
+		printMessage(message, nil)
+		printDetail("Skipped: unsigned payloads require an https endpoint")
+		return true
+	}
+	bucketName := s3verifyBuckets[0].Name
+	objectName := "s3verify/put/stream-unsigned"
+	seed := time.Now().UnixNano()
+	// Hash the body as it is sent to find the expected ETag without a second pass.
+	hasher := md5.New()
+	body := io.TeeReader(newGeneratedReader(seed, streamObjectSize), hasher)
+	req, err := newPutObjectReaderReq(bucketName, objectName, body, streamObjectSize)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if err := putObjectVerify(res, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	expectedETag := hex.EncodeToString(hasher.Sum(nil))
+	if eTag := strings.Trim(res.Header.Get("ETag"), "\""); eTag != expectedETag {
+		err := ChecksumMismatchError{Checksum: "ETag", Expected: expectedETag, Got: eTag}
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Download the object and compare it against the regenerated body.
+	getReq, err := newGetObjectReq(bucketName, objectName, nil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	getRes, err := config.execRequest("GET", getReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(getRes)
+	if err := verifyStatusGetObject(getRes.StatusCode, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyBodyStream(getRes.Body, newGeneratedReader(seed, streamObjectSize)); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Remove the streamed object.
+	if err := removeObject(config, bucketName, objectName); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
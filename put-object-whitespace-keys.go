@@ -0,0 +1,153 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Keys with leading and trailing spaces, legal in S3 but trimmed by some gateways.
+var whitespaceKeys = []string{
+	"  s3verify/whitespace/leading",
+	"s3verify/whitespace/trailing  ",
+	"s3verify/whitespace/  spaced key  ",
+}
+
+// Prefixes under which all whitespaceKeys, and anything a trimming server stores them as, are listed.
+var whitespaceKeyPrefixes = []string{
+	"  s3verify/whitespace/",
+	"s3verify/whitespace/",
+}
+
+// listKeysURLEncoded - list every key under prefix with encoding-type=url, following markers, and
+// return the decoded keys so whitespace is compared exactly rather than as XML normalized it.
+func listKeysURLEncoded(config ServerConfig, bucketName, prefix string) ([]string, error) {
+	listedKeys := []string{}
+	marker := ""
+	for {
+		parameters := map[string]string{
+			"prefix":        prefix,
+			"encoding-type": "url",
+		}
+		if marker != "" {
+			parameters["marker"] = marker
+		}
+		receivedList, err := listObjectsPage(config, bucketName, parameters)
+		if err != nil {
+			return nil, err
+		}
+		if receivedList.EncodingType != "url" {
+			err := fmt.Errorf("Unexpected EncodingType Received: wanted url, got %q", receivedList.EncodingType)
+			return nil, err
+		}
+		for _, object := range receivedList.Contents {
+			key, err := url.QueryUnescape(object.Key)
+			if err != nil {
+				err = fmt.Errorf("Invalid URL Encoded Key Received: %q: %v", object.Key, err)
+				return nil, err
+			}
+			listedKeys = append(listedKeys, key)
+		}
+		if !receivedList.IsTruncated || len(receivedList.Contents) == 0 {
+			return listedKeys, nil
+		}
+		marker = listedKeys[len(listedKeys)-1]
+	}
+}
+
+// mainPutObjectWhitespaceKeys - verify keys with leading and trailing spaces round-trip exactly
+// through PUT, HEAD, GET and LIST.
+func mainPutObjectWhitespaceKeys(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] PutObject (Whitespace in Keys):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	for _, key := range whitespaceKeys {
+		object := &ObjectInfo{
+			Key:  key,
+			Body: []byte(key),
+		}
+		if _, err := putObject(config, bucketName, object); err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Spin scanBar
+	scanBar(message)
+	// List everything a server might have stored the keys as.
+	listedKeys := []string{}
+	for _, prefix := range whitespaceKeyPrefixes {
+		keys, err := listKeysURLEncoded(config, bucketName, prefix)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		listedKeys = append(listedKeys, keys...)
+	}
+	if err := verifySlashKeysListed(listedKeys, whitespaceKeys); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Each object must be retrievable by the exact key it was uploaded with.
+	for _, key := range whitespaceKeys {
+		header, err := headObject(config, bucketName, key)
+		if err != nil {
+			err = fmt.Errorf("HEAD %q: %v", key, err)
+			printMessage(message, err)
+			return false
+		}
+		if contentLength := header.Get("Content-Length"); contentLength != strconv.Itoa(len(key)) {
+			err := fmt.Errorf("HEAD %q: Unexpected Content-Length Received: wanted %v, got %v", key, len(key), contentLength)
+			printMessage(message, err)
+			return false
+		}
+		req, err := newGetObjectReq(bucketName, key, nil)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		res, err := config.execRequest("GET", req)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		err = getObjectVerify(res, []byte(key), http.StatusOK, nil)
+		drainAndClose(res)
+		if err != nil {
+			err = fmt.Errorf("GET %q: %v", key, err)
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Remove the objects so they do not interfere with future tests.
+	for _, key := range whitespaceKeys {
+		if err := removeObject(config, bucketName, key); err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -18,8 +18,6 @@ package main
 
 import (
 	"bytes"
-	"encoding/base64"
-	"encoding/hex"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -30,16 +28,22 @@ import (
 )
 
 // Store all objects that are uploaded by s3verify tests.
-var s3verifyObjects = []*ObjectInfo{}
+var s3verifyObjects = &objectStore{}
 
 // Store all objects that are uploaded through the preparing operation.
-var preparedObjects = []*ObjectInfo{}
+var preparedObjects = &objectStore{}
 
 // Store all objects that were copied.
-var copyObjects = []*ObjectInfo{}
+var copyObjects = &objectStore{}
 
 // newPutObjectReq - Create a new HTTP request for PUT object.
 func newPutObjectReq(bucketName, objectName string, objectData []byte) (Request, error) {
+	return newPutObjectStreamReq(bucketName, objectName, bytes.NewReader(objectData))
+}
+
+// newPutObjectStreamReq - Create a new HTTP request for PUT object from a seekable body.
+// The body is hashed in a first pass and then rewound to be sent, so it is never buffered.
+func newPutObjectStreamReq(bucketName, objectName string, body io.ReadSeeker) (Request, error) {
 	// An HTTP request for a PUT object.
 	var putObjectReq = Request{
 		customHeader: http.Header{},
@@ -50,19 +54,39 @@ func newPutObjectReq(bucketName, objectName string, objectData []byte) (Request,
 	putObjectReq.objectName = objectName
 
 	// Compute md5Sum and sha256Sum from the input data.
-	reader := bytes.NewReader(objectData)
-	md5Sum, sha256Sum, contentLength, err := computeHash(reader)
+	md5Sum, sha256Sum, contentLength, err := computeHashWithStrategy(body, globalHashStrategy)
 	if err != nil {
 		return Request{}, err
 	}
 
-	putObjectReq.customHeader.Set("Content-MD5", base64.StdEncoding.EncodeToString(md5Sum))
-	putObjectReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
+	setBodyHashHeaders(putObjectReq.customHeader, md5Sum, sha256Sum)
 	putObjectReq.customHeader.Set("User-Agent", appUserAgent)
 
 	putObjectReq.contentLength = contentLength
-	// Set the body to the data held in objectData.
-	putObjectReq.contentBody = reader
+	// Set the body to the rewound input.
+	putObjectReq.contentBody = body
+
+	return putObjectReq, nil
+}
+
+// newPutObjectReaderReq - Create a new HTTP request for PUT object from a body of known size that can only be read once.
+// Nothing is hashed up front so the payload is sent unsigned, which is only safe over https, and the request is never retried.
+func newPutObjectReaderReq(bucketName, objectName string, body io.Reader, size int64) (Request, error) {
+	// An HTTP request for a PUT object.
+	var putObjectReq = Request{
+		customHeader: http.Header{},
+	}
+
+	// Set the bucketName and objectName.
+	putObjectReq.bucketName = bucketName
+	putObjectReq.objectName = objectName
+
+	setBodyHashHeaders(putObjectReq.customHeader, nil, nil)
+	putObjectReq.customHeader.Set("User-Agent", appUserAgent)
+
+	putObjectReq.contentLength = size
+	// Hide any Seek method so the body is read exactly once.
+	putObjectReq.contentBody = struct{ io.Reader }{body}
 
 	return putObjectReq, nil
 }
@@ -84,7 +108,7 @@ func putObjectVerify(res *http.Response, expectedStatusCode int) error {
 // verifyStatusPutObject - Verify that the res.StatusCode code matches what is expected.
 func verifyStatusPutObject(respStatusCode, expectedStatusCode int) error {
 	if respStatusCode != expectedStatusCode {
-		err := fmt.Errorf("Unexpected Response Status Code: wanted %v, got %v", expectedStatusCode, respStatusCode)
+		err := StatusMismatchError{Expected: expectedStatusCode, Got: respStatusCode}
 		return err
 	}
 	return nil
@@ -97,11 +121,7 @@ func verifyBodyPutObject(resBody io.Reader) error {
 		return err
 	}
 	// A PUT request should give back an empty body.
-	if !bytes.Equal(body, []byte{}) {
-		err := fmt.Errorf("Unexpected Body Recieved: expected empty body but recieved: %v", string(body))
-		return err
-	}
-	return nil
+	return verifyBodyEmpty(body)
 }
 
 // verifyHeaderPutObject - Verify that the header returned matches what is expected.
@@ -112,6 +132,23 @@ func verifyHeaderPutObject(header http.Header) error {
 	return nil
 }
 
+// putObject - upload object to bucketName, verify the response and return its headers.
+func putObject(config ServerConfig, bucketName string, object *ObjectInfo) (http.Header, error) {
+	req, err := newPutObjectReq(bucketName, object.Key, object.Body)
+	if err != nil {
+		return nil, err
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(res)
+	if err := putObjectVerify(res, http.StatusOK); err != nil {
+		return nil, err
+	}
+	return res.Header, nil
+}
+
 // TODO: need mainPutObjectPrepared and mainPutObjectUnPrepared.
 func mainPutObjectPrepared(config ServerConfig, curTest int) bool {
 	message := fmt.Sprintf("[%02d/%d] PutObject:", curTest, globalTotalNumTest)
@@ -143,14 +180,14 @@ func mainPutObjectPrepared(config ServerConfig, curTest int) bool {
 	}
 	// Spin scanBar
 	scanBar(message)
-	defer closeResponse(res)
+	defer drainAndClose(res)
 	// Verify the response.
 	if err := putObjectVerify(res, http.StatusOK); err != nil {
 		printMessage(message, err)
 		return false
 	}
 	// Store this object in the global objects list.
-	s3verifyObjects = append(s3verifyObjects, object)
+	s3verifyObjects.add(object)
 	// Spin scanBar
 	scanBar(message)
 	// Test passed.
@@ -165,42 +202,40 @@ func mainPutObjectUnPrepared(config ServerConfig, curTest int) bool {
 	bucket := s3verifyBuckets[0]
 	// Spin scanBar
 	scanBar(message)
-	// TODO: need to update to 1001 once this is production ready.
-	// Upload 1001 objects with 1 byte each to check the ListObjects API with.
-	for i := 0; i < 101; i++ {
-		// Spin scanBar
-		scanBar(message)
-		object := &ObjectInfo{}
-		object.Key = "s3verify/put/object/" + strconv.Itoa(i)
-		// Create 60 bytes worth of random data for each object.
-		body := randString(60, rand.NewSource(time.Now().UnixNano()), "")
-		object.Body = []byte(body)
-		// Create a new request.
-		req, err := newPutObjectReq(bucket.Name, object.Key, object.Body)
-		if err != nil {
-			printMessage(message, err)
-			return false
-		}
-		// Execute the request.
-		res, err := config.execRequest("PUT", req)
-		if err != nil {
-			printMessage(message, err)
-			return false
-		}
-		defer closeResponse(res)
-		// Verify the response.
-		if err := putObjectVerify(res, http.StatusOK); err != nil {
-			printMessage(message, err)
-			return false
-		}
-		// Add the new object to the list of objects.
-		s3verifyObjects = append(s3verifyObjects, object)
-		// Spin scanBar
-		scanBar(message)
+	// Upload enough objects to check the ListObjects API with, 101 unless --object-count asks for more.
+	// Only over 1000 do listings cross a page boundary.
+	objects, err := putObjectsConcurrently(config, bucket.Name, config.objectCount(), message)
+	if err != nil {
+		printMessage(message, err)
+		return false
 	}
+	// Add the new objects to the list of objects in upload order.
+	s3verifyObjects.add(objects...)
 	// Spin scanBar
 	scanBar(message)
 	// Test passed.
 	printMessage(message, nil)
 	return true
 }
+
+// putObjectsConcurrently - upload numObjects objects to bucketName with uploadBatch.
+// The objects are returned in index order, which is not the order their keys sort in. An error is returned if any object could not be uploaded.
+func putObjectsConcurrently(config ServerConfig, bucketName string, numObjects int, message string) ([]*ObjectInfo, error) {
+	// Every object is generated from its own seed so no two bodies are the same.
+	seed := time.Now().UnixNano()
+	objects := make([]*ObjectInfo, numObjects)
+	for i := range objects {
+		objects[i] = &ObjectInfo{
+			Key:  "s3verify/put/object/" + strconv.Itoa(i),
+			Body: generatedBody(seed+int64(i), config.objectSize()),
+		}
+	}
+	results := uploadBatch(config, objects, func(object *ObjectInfo) error {
+		_, err := putObject(config, bucketName, object)
+		return err
+	}, message)
+	if err := uploadBatchError(results); err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
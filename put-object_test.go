@@ -0,0 +1,201 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// bodyCounter - a transport that counts the response bodies not closed yet, and the most that were open at once.
+type bodyCounter struct {
+	transport http.RoundTripper
+	mutex     sync.Mutex
+	open      int
+	maxOpen   int
+}
+
+func (c *bodyCounter) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := c.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	c.mutex.Lock()
+	c.open++
+	if c.open > c.maxOpen {
+		c.maxOpen = c.open
+	}
+	c.mutex.Unlock()
+	res.Body = &countedBody{ReadCloser: res.Body, counter: c}
+	return res, nil
+}
+
+// countedBody - a response body that is counted out by its bodyCounter once closed.
+type countedBody struct {
+	io.ReadCloser
+	counter *bodyCounter
+	once    sync.Once
+}
+
+func (b *countedBody) Close() error {
+	b.once.Do(func() {
+		b.counter.mutex.Lock()
+		b.counter.open--
+		b.counter.mutex.Unlock()
+	})
+	return b.ReadCloser.Close()
+}
+
+// newObjectServer - a server that stores the objects PUT to it and returns them on GET.
+func newObjectServer() *httptest.Server {
+	var mutex sync.Mutex
+	objects := map[string][]byte{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		switch r.Method {
+		case "PUT":
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				writeS3Error(w, http.StatusBadRequest, "IncompleteBody", "")
+				return
+			}
+			objects[r.URL.Path] = body
+			writeS3Headers(w)
+			w.WriteHeader(http.StatusOK)
+		case "GET":
+			body, ok := objects[r.URL.Path]
+			if !ok {
+				writeS3Error(w, http.StatusNotFound, "NoSuchKey", "")
+				return
+			}
+			writeS3Headers(w)
+			w.Write(body)
+		default:
+			writeS3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "")
+		}
+	}))
+}
+
+// Test that uploading many objects and reading them back keeps no more response bodies open than
+// there are requests in flight, rather than one for every object until the test returns.
+func TestBoundedOpenBodies(t *testing.T) {
+	server := newObjectServer()
+	defer server.Close()
+
+	config := newTestConfig(server.URL)
+	config.Workers = 4
+	config.ObjectCount = 500
+	counter := &bodyCounter{transport: &http.Transport{}}
+	config.Client = &http.Client{Transport: counter}
+	s3verifyBuckets = []BucketInfo{{Name: "s3verify-bounded"}}
+	s3verifyObjects = &objectStore{}
+
+	testCases := []struct {
+		name    string
+		test    func(ServerConfig, int) bool
+		maxOpen int
+	}{
+		{"PutObject", mainPutObjectUnPrepared, config.Workers},
+		{"GetObject (Accept-Encoding: identity)", mainGetObjectIdentity, 1},
+	}
+	for _, testCase := range testCases {
+		counter.maxOpen = 0
+		if !testCase.test(config, 1) {
+			t.Fatalf("%s: Expected the test to pass", testCase.name)
+		}
+		if counter.open != 0 {
+			t.Errorf("%s: Expected every response body to be closed, %d are open", testCase.name, counter.open)
+		}
+		if counter.maxOpen > testCase.maxOpen {
+			t.Errorf("%s: Expected at most %d response bodies open at once, got %d", testCase.name, testCase.maxOpen, counter.maxOpen)
+		}
+	}
+	if uploaded := len(s3verifyObjects.snapshot()); uploaded != config.ObjectCount {
+		t.Fatalf("Expected %d objects to be uploaded, got %d", config.ObjectCount, uploaded)
+	}
+}
+
+// Test that an upload failing with a retryable error is retried by execRequest alone, not once
+// more for each of its retries by uploadBatch.
+func TestUploadBatchRetries(t *testing.T) {
+	server, attempts := newFlakyServer(100)
+	defer server.Close()
+
+	config := newTestConfig(server.URL)
+	config.MaxRetries = 2
+	objects := []*ObjectInfo{{Key: "s3verify/batch/object", Body: []byte("s3verify")}}
+	results := uploadBatch(config, objects, func(object *ObjectInfo) error {
+		_, err := putObject(config, "s3verify-batch", object)
+		return err
+	}, "")
+	if err := uploadBatchError(results); err == nil {
+		t.Fatal("Expected the upload to fail")
+	}
+	if attempts() != config.MaxRetries+1 {
+		t.Fatalf("Expected %d attempts, got %d", config.MaxRetries+1, attempts())
+	}
+}
+
+// Test that the first failed upload of a batch stops the remaining uploads and is the reported cause.
+func TestUploadBatchStopsOnFailure(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if strings.HasSuffix(r.URL.Path, "/object-0") {
+			writeS3Error(w, http.StatusForbidden, "AccessDenied", "")
+			return
+		}
+		writeS3Headers(w)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	config := newTestConfig(server.URL)
+	config.Workers = 2
+	config.MaxRetries = 0
+	objects := make([]*ObjectInfo, 20)
+	for i := range objects {
+		objects[i] = &ObjectInfo{Key: fmt.Sprintf("s3verify/batch/object-%d", i), Body: []byte("s3verify")}
+	}
+	results := uploadBatch(config, objects, func(object *ObjectInfo) error {
+		_, err := putObject(config, "s3verify-batch", object)
+		return err
+	}, "")
+	err := uploadBatchError(results)
+	if err == nil {
+		t.Fatal("Expected the upload to fail")
+	}
+	if !strings.Contains(err.Error(), "s3verify/batch/object-0 failed") {
+		t.Fatalf("Expected the first failed upload to be reported, got %v", err)
+	}
+	if got := int(atomic.LoadInt32(&requests)); got >= len(objects) {
+		t.Fatalf("Expected fewer than %d requests after the first failure, got %d", len(objects), got)
+	}
+	for _, result := range results {
+		if result.object == nil {
+			t.Fatal("Expected a result for every object")
+		}
+	}
+}
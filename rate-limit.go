@@ -0,0 +1,55 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter - spaces requests evenly so no more than a given number are sent per second.
+type rateLimiter struct {
+	mutex    sync.Mutex
+	interval time.Duration // The time between two requests.
+	next     time.Time     // The earliest time the next request may be sent.
+}
+
+// newRateLimiter - create a limiter allowing rps requests per second, nil if rps is 0.
+func newRateLimiter(rps int) *rateLimiter {
+	if rps <= 0 {
+		return nil
+	}
+	return &rateLimiter{
+		interval: time.Second / time.Duration(rps),
+	}
+}
+
+// wait - block until another request may be sent. Safe to call on a nil limiter, which never blocks.
+func (l *rateLimiter) wait() {
+	if l == nil {
+		return
+	}
+	l.mutex.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	sleep := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mutex.Unlock()
+	time.Sleep(sleep)
+}
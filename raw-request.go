@@ -0,0 +1,66 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// dialRaw - open a connection of its own to the host of targetURL, for requests that net/http
+// would not send as they are or responses it would not return as received. The connection is
+// dialed the way the tests do, including through any proxy and with the same TLS settings, and
+// times out after 30 seconds.
+func dialRaw(config ServerConfig, targetURL *url.URL) (net.Conn, error) {
+	if config.DryRun {
+		err := fmt.Errorf("Dry Run: no raw connection is made to %v", targetURL.Host)
+		return nil, err
+	}
+	dial := (&net.Dialer{Timeout: config.dialTimeout()}).DialContext
+	tlsConfig := &tls.Config{}
+	if transport, ok := config.Client.Transport.(*http.Transport); ok {
+		if transport.DialContext != nil {
+			dial = transport.DialContext
+		}
+		if transport.TLSClientConfig != nil {
+			tlsConfig = transport.TLSClientConfig.Clone()
+		}
+	}
+	host := targetURL.Host
+	if targetURL.Port() == "" {
+		port := "80"
+		if targetURL.Scheme == "https" {
+			port = "443"
+		}
+		host = net.JoinHostPort(targetURL.Hostname(), port)
+	}
+	conn, err := dial(context.Background(), "tcp", host)
+	if err != nil {
+		return nil, err
+	}
+	if targetURL.Scheme == "https" {
+		tlsConfig.ServerName = targetURL.Hostname()
+		conn = tls.Client(conn, tlsConfig)
+	}
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+	return conn, nil
+}
@@ -0,0 +1,140 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const (
+	// The number of existing objects the read-only tests are run on.
+	readOnlyNumObjects = 10
+	// Objects larger than this are not used by the read-only tests, every test downloads each object at least once.
+	readOnlyMaxObjectSize = 16 * 1024 * 1024
+)
+
+// splitReadOnlyTarget - split the bucket[/prefix] given with --read-only.
+func splitReadOnlyTarget(target string) (bucketName, prefix string) {
+	parts := strings.SplitN(strings.TrimPrefix(target, "/"), "/", 2)
+	if len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return parts[0], ""
+}
+
+// prepareReadOnly - use existing objects under prefix in bucketName for the read-only tests.
+// Only HEAD, LIST and GET requests are made: the objects are sampled from a listing and downloaded
+// so the GET tests have a body to compare against.
+func prepareReadOnly(config ServerConfig, bucketName, prefix string) error {
+	headReq, err := newHeadBucketReq(bucketName)
+	if err != nil {
+		return err
+	}
+	headRes, err := config.execRequest("HEAD", headReq)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(headRes)
+	if err := headBucketVerify(headRes, http.StatusOK); err != nil {
+		return fmt.Errorf("Bucket %s can not be used with --read-only: %v", bucketName, err)
+	}
+	objects := []*ObjectInfo{}
+	marker := ""
+	for len(objects) < readOnlyNumObjects {
+		parameters := map[string]string{
+			"prefix": prefix,
+		}
+		if marker != "" {
+			parameters["marker"] = marker
+		}
+		receivedList, err := listObjectsPage(config, bucketName, parameters)
+		if err != nil {
+			return err
+		}
+		for _, object := range receivedList.Contents {
+			// Empty objects can not be read with a Range and large ones would take too long.
+			if object.Size == 0 || object.Size > readOnlyMaxObjectSize || len(objects) == readOnlyNumObjects {
+				continue
+			}
+			objects = append(objects, &ObjectInfo{
+				Key:  object.Key,
+				Size: object.Size,
+			})
+		}
+		if !receivedList.IsTruncated || len(receivedList.Contents) == 0 {
+			break
+		}
+		marker = receivedList.Contents[len(receivedList.Contents)-1].Key
+	}
+	if len(objects) == 0 {
+		err := fmt.Errorf("No objects of 1 to %d bytes found under %s/%s for --read-only", readOnlyMaxObjectSize, bucketName, prefix)
+		return err
+	}
+	for _, object := range objects {
+		body, err := readOnlyGetBody(config, bucketName, object.Key)
+		if err != nil {
+			return err
+		}
+		object.Body = body
+	}
+	s3verifyBuckets = []BucketInfo{
+		BucketInfo{
+			Name: bucketName,
+		},
+	}
+	s3verifyObjects.add(objects...)
+	return nil
+}
+
+// readOnlyGetBody - download the body of an existing object.
+func readOnlyGetBody(config ServerConfig, bucketName, objectName string) ([]byte, error) {
+	req, err := newGetObjectReq(bucketName, objectName, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(res)
+	if err := verifyStatusGetObject(res.StatusCode, http.StatusOK); err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(res.Body)
+}
+
+// mainListObjectsReadOnly - verify every object used by the read-only tests is listed under its prefix.
+func mainListObjectsReadOnly(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] ListObjects (Read Only):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucketName := s3verifyBuckets[0].Name
+	for _, object := range s3verifyObjects.snapshot() {
+		// Spin scanBar
+		scanBar(message)
+		if err := listObjectVisibility(config, bucketName, object.Key, true); err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
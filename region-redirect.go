@@ -0,0 +1,61 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import "sync"
+
+// regionHint - the signing region learned from the server, safe for concurrent use.
+type regionHint struct {
+	mutex  sync.Mutex
+	region string
+}
+
+// get - the learned region, empty if none has been learned.
+func (h *regionHint) get() string {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.region
+}
+
+// set - remember region for all following requests.
+func (h *regionHint) set(region string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.region = region
+}
+
+// globalLearnedRegion - the region the server said requests must be signed for.
+var globalLearnedRegion = &regionHint{}
+
+// malformedAuthorizationRegion - the region an AuthorizationHeaderMalformed error asks for,
+// or empty if the error is of a different kind, names no region or names the one already used.
+// A request signed for the wrong region is answered with e.g.
+//
+//	<Error>
+//	  <Code>AuthorizationHeaderMalformed</Code>
+//	  <Message>... the region 'us-east-1' is wrong; expecting 'eu-west-1'</Message>
+//	  <Region>eu-west-1</Region>
+//	</Error>
+func malformedAuthorizationRegion(errResponse ErrorResponse, signingRegion string) string {
+	if errResponse.Code != "AuthorizationHeaderMalformed" {
+		return ""
+	}
+	if errResponse.Region == signingRegion {
+		return ""
+	}
+	return errResponse.Region
+}
@@ -0,0 +1,133 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Test that a request signed for the wrong region is re-signed for the region the server names
+// and sent again exactly once, and that the region is remembered for the requests that follow.
+func TestRegionResign(t *testing.T) {
+	var mutex sync.Mutex
+	var scopes []string // The credential scope of each request received.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		scopes = append(scopes, r.Header.Get("Authorization"))
+		mutex.Unlock()
+		if !strings.Contains(r.Header.Get("Authorization"), "/eu-west-1/s3/") {
+			writeS3Error(w, http.StatusBadRequest, "AuthorizationHeaderMalformed", "<Region>eu-west-1</Region>")
+			return
+		}
+		writeS3Headers(w)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	config := newTestConfig(server.URL)
+	req, err := newGetObjectReq("s3verify-region", "object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer drainAndClose(res)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status %d after re-signing, got %d", http.StatusOK, res.StatusCode)
+	}
+	if len(scopes) != 2 {
+		t.Fatalf("Expected the request to be sent twice, it was sent %d times", len(scopes))
+	}
+	if !strings.Contains(scopes[0], "/us-east-1/s3/") {
+		t.Errorf("Expected the first request to be signed for us-east-1, got %q", scopes[0])
+	}
+	if region := globalLearnedRegion.get(); region != "eu-west-1" {
+		t.Errorf("Expected region eu-west-1 to be learned, got %q", region)
+	}
+}
+
+// Test that a server asking for yet another region after the re-signed retry is not chased further.
+func TestRegionResignOnce(t *testing.T) {
+	var mutex sync.Mutex
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		attempts++
+		region := []string{"eu-west-1", "ap-south-1"}[(attempts-1)%2]
+		mutex.Unlock()
+		writeS3Error(w, http.StatusBadRequest, "AuthorizationHeaderMalformed", "<Region>"+region+"</Region>")
+	}))
+	defer server.Close()
+
+	config := newTestConfig(server.URL)
+	req, err := newGetObjectReq("s3verify-region", "object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer drainAndClose(res)
+	if res.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Expected status %d to be returned, got %d", http.StatusBadRequest, res.StatusCode)
+	}
+	if attempts != 2 {
+		t.Fatalf("Expected the request to be sent twice, it was sent %d times", attempts)
+	}
+}
+
+// Test that a request whose body cannot be rewound is not re-signed and sent again.
+func TestRegionResignNonSeekableBody(t *testing.T) {
+	var mutex sync.Mutex
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		attempts++
+		mutex.Unlock()
+		writeS3Error(w, http.StatusBadRequest, "AuthorizationHeaderMalformed", "<Region>eu-west-1</Region>")
+	}))
+	defer server.Close()
+
+	config := newTestConfig(server.URL)
+	body := []byte("s3verify")
+	req, err := newPutObjectReq("s3verify-region", "object", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Hide the Seek method of the body.
+	req.contentBody = struct{ io.Reader }{bytes.NewReader(body)}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer drainAndClose(res)
+	if attempts != 1 {
+		t.Fatalf("Expected the request to be sent once, it was sent %d times", attempts)
+	}
+	if region := globalLearnedRegion.get(); region != "" {
+		t.Errorf("Expected no region to be learned, got %q", region)
+	}
+}
@@ -98,6 +98,20 @@ func verifyStatusRemoveBucket(respStatusCode, expectedStatusCode int) error {
 	return nil
 }
 
+// removeBucket - remove the empty bucketName and verify the response.
+func removeBucket(config ServerConfig, bucketName string) error {
+	req, err := newRemoveBucketReq(bucketName)
+	if err != nil {
+		return err
+	}
+	res, err := config.execRequest("DELETE", req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	return removeBucketVerify(res, http.StatusNoContent, ErrorResponse{})
+}
+
 // mainRemoveBucketExists - test the removebucket API.
 func mainRemoveBucketExists(config ServerConfig, curTest int) bool {
 	message := fmt.Sprintf("[%02d/%d] RemoveBucket (Bucket Exists):", curTest, globalTotalNumTest)
@@ -105,6 +119,13 @@ func mainRemoveBucketExists(config ServerConfig, curTest int) bool {
 	for _, bucket := range s3verifyBuckets {
 		// Spin the scanBar
 		scanBar(message)
+		// Versions and delete markers left in the configured buckets must be removed first.
+		if bucket.Capabilities != 0 {
+			if err := emptyBucket(config, bucket.Name); err != nil {
+				printMessage(message, err)
+				return false
+			}
+		}
 		// Generate the new DELETE bucket request.
 		req, err := newRemoveBucketReq(bucket.Name)
 		if err != nil {
@@ -119,10 +140,11 @@ func mainRemoveBucketExists(config ServerConfig, curTest int) bool {
 			printMessage(message, err)
 			return false
 		}
-		defer closeResponse(res)
 		// Spin the scanBar
 		scanBar(message)
-		if err := removeBucketVerify(res, 204, ErrorResponse{}); err != nil {
+		err = removeBucketVerify(res, 204, ErrorResponse{})
+		drainAndClose(res)
+		if err != nil {
 			printMessage(message, err)
 			return false
 		}
@@ -161,7 +183,7 @@ func mainRemoveBucketDNE(config ServerConfig, curTest int) bool {
 		printMessage(message, err)
 		return false
 	}
-	defer closeResponse(res)
+	defer drainAndClose(res)
 	// Spin scanBar
 	scanBar(message)
 	if err := removeBucketVerify(res, http.StatusNotFound, errResponse); err != nil {
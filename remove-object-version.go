@@ -0,0 +1,133 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// removeObjectVersion - DELETE objectName, or one version of it if versionID is set, from a versioned bucket.
+func removeObjectVersion(config ServerConfig, bucketName, objectName, versionID string, expectDeleteMarker bool) (string, error) {
+	req, err := newRemoveObjectReq(config, bucketName, objectName)
+	if versionID != "" {
+		req, err = newRemoveObjectVersionReq(config, bucketName, objectName, versionID)
+	}
+	if err != nil {
+		return "", err
+	}
+	res, err := config.execRequest("DELETE", req)
+	if err != nil {
+		return "", err
+	}
+	defer drainAndClose(res)
+	return removeObjectVersionVerify(res, http.StatusNoContent, versionID, expectDeleteMarker)
+}
+
+// getObjectDeleteMarkerVerify - verify a GET of a key whose current version is a delete marker
+// reports it as deleted, rather than as a key that never existed.
+func getObjectDeleteMarkerVerify(res *http.Response, markerVersionID string) error {
+	if err := verifyStatusGetObject(res.StatusCode, http.StatusNotFound); err != nil {
+		return err
+	}
+	if res.Header.Get("x-amz-delete-marker") != "true" {
+		err := fmt.Errorf("Unexpected x-amz-delete-marker Received: wanted true, got %q", res.Header.Get("x-amz-delete-marker"))
+		return err
+	}
+	if versionID := res.Header.Get("x-amz-version-id"); versionID != markerVersionID {
+		err := fmt.Errorf("Unexpected x-amz-version-id Received: wanted the delete marker's %v, got %q", markerVersionID, versionID)
+		return err
+	}
+	errResponse := ErrorResponse{}
+	if err := xmlDecoder(res.Body, &errResponse); err != nil {
+		return err
+	}
+	if errResponse.Code != "NoSuchKey" {
+		err := fmt.Errorf("Unexpected Error Response: wanted NoSuchKey, got %v", errResponse.Code)
+		return err
+	}
+	return nil
+}
+
+// mainRemoveObjectVersioned - RemoveObject API test in a versioned bucket, verifying the version headers returned.
+func mainRemoveObjectVersioned(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] RemoveObject (Versioned):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	bucket, err := findBucket(capVersioned)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	bucketName := bucket.Name
+	object := &ObjectInfo{
+		Key:  "s3verify/delete/versioned",
+		Body: []byte("s3verify versioned delete"),
+	}
+	if err := putVersionedObject(config, bucketName, object); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// A DELETE without a version id creates a delete marker.
+	markerVersionID, err := removeObjectVersion(config, bucketName, object.Key, "", true)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if markerVersionID == object.VersionID {
+		err := fmt.Errorf("Unexpected x-amz-version-id Received: the delete marker reused the object's version id %v", markerVersionID)
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// With the marker current a GET of the key must say it was deleted.
+	getReq, err := newGetObjectReq(bucketName, object.Key, nil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	getRes, err := config.execRequest("GET", getReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(getRes)
+	if err := getObjectDeleteMarkerVerify(getRes, markerVersionID); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Deleting the object's version removes it for good.
+	if _, err := removeObjectVersion(config, bucketName, object.Key, object.VersionID, false); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Deleting the marker's version removes the marker.
+	if _, err := removeObjectVersion(config, bucketName, object.Key, markerVersionID, true); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -23,6 +23,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 )
 
 // newRemoveObjectReq - Create a new DELETE object HTTP request.
@@ -48,6 +49,20 @@ func newRemoveObjectReq(config ServerConfig, bucketName, objectName string) (Req
 	return removeObjectReq, nil
 }
 
+// newRemoveObjectVersionReq - Create a new DELETE object HTTP request for a specific object version.
+func newRemoveObjectVersionReq(config ServerConfig, bucketName, objectName, versionID string) (Request, error) {
+	removeObjectVersionReq, err := newRemoveObjectReq(config, bucketName, objectName)
+	if err != nil {
+		return Request{}, err
+	}
+	// Set the versionId to be removed.
+	urlValues := make(url.Values)
+	urlValues.Set("versionId", versionID)
+	removeObjectVersionReq.queryValues = urlValues
+
+	return removeObjectVersionReq, nil
+}
+
 // removeObjectVerify - Verify that the response returned matches what is expected.
 func removeObjectVerify(res *http.Response, expectedStatusCode int) error {
 	if err := verifyHeaderRemoveObject(res.Header); err != nil {
@@ -92,6 +107,53 @@ func verifyStatusRemoveObject(respStatusCode, expectedStatusCode int) error {
 	return nil
 }
 
+// removeObjectVersionVerify - Verify the response to a DELETE in a versioned bucket and return the version id received.
+//
+// With a versionID the version deleted must be echoed back, with x-amz-delete-marker set only if
+// that version was a delete marker. Without one a new delete marker is created and its version id is returned.
+func removeObjectVersionVerify(res *http.Response, expectedStatusCode int, versionID string, expectDeleteMarker bool) (string, error) {
+	if err := removeObjectVerify(res, expectedStatusCode); err != nil {
+		return "", err
+	}
+	if err := verifyHeaderRemoveObjectVersion(res.Header, versionID, expectDeleteMarker); err != nil {
+		return "", err
+	}
+	return res.Header.Get("x-amz-version-id"), nil
+}
+
+// verifyHeaderRemoveObjectVersion - Verify the version headers returned match what is expected.
+func verifyHeaderRemoveObjectVersion(header http.Header, versionID string, expectDeleteMarker bool) error {
+	receivedVersionID := header.Get("x-amz-version-id")
+	if versionID != "" && receivedVersionID != versionID {
+		err := fmt.Errorf("Unexpected x-amz-version-id Received: wanted %v, got %v", versionID, receivedVersionID)
+		return err
+	}
+	if versionID == "" && receivedVersionID == "" {
+		err := fmt.Errorf("Missing Header: x-amz-version-id of the new delete marker was not returned")
+		return err
+	}
+	isDeleteMarker := header.Get("x-amz-delete-marker") == "true"
+	if isDeleteMarker != expectDeleteMarker {
+		err := fmt.Errorf("Unexpected x-amz-delete-marker Received: wanted %v, got %q", expectDeleteMarker, header.Get("x-amz-delete-marker"))
+		return err
+	}
+	return nil
+}
+
+// removeObject - remove objectName from bucketName and verify the response.
+func removeObject(config ServerConfig, bucketName, objectName string) error {
+	req, err := newRemoveObjectReq(config, bucketName, objectName)
+	if err != nil {
+		return err
+	}
+	res, err := config.execRequest("DELETE", req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	return removeObjectVerify(res, http.StatusNoContent)
+}
+
 // mainRemoveObjectExists - RemoveObject API test when object exists.
 func mainRemoveObjectExists(config ServerConfig, curTest int) bool {
 	message := fmt.Sprintf("[%d/%d] RemoveObject:", curTest, globalTotalNumTest)
@@ -100,7 +162,7 @@ func mainRemoveObjectExists(config ServerConfig, curTest int) bool {
 	// Only remove objects from s3verify created buckets.
 	// Only remove s3verify created objects.
 	for _, bucket := range s3verifyBuckets {
-		for _, object := range s3verifyObjects {
+		for _, object := range s3verifyObjects.snapshot() {
 			// Spin scanBar
 			scanBar(message)
 			// Create a new request.
@@ -115,9 +177,10 @@ func mainRemoveObjectExists(config ServerConfig, curTest int) bool {
 				printMessage(message, err)
 				return false
 			}
-			defer closeResponse(res)
 			// Verify the response.
-			if err := removeObjectVerify(res, http.StatusNoContent); err != nil {
+			err = removeObjectVerify(res, http.StatusNoContent)
+			drainAndClose(res)
+			if err != nil {
 				printMessage(message, err)
 				return false
 			}
@@ -125,7 +188,7 @@ func mainRemoveObjectExists(config ServerConfig, curTest int) bool {
 			scanBar(message)
 
 		}
-		for _, object := range copyObjects {
+		for _, object := range copyObjects.snapshot() {
 			// Spin scanBar
 			scanBar(message)
 			// Create a new request.
@@ -140,9 +203,10 @@ func mainRemoveObjectExists(config ServerConfig, curTest int) bool {
 				printMessage(message, err)
 				return false
 			}
-			defer closeResponse(res)
 			// Verify the response.
-			if err := removeObjectVerify(res, http.StatusNoContent); err != nil {
+			err = removeObjectVerify(res, http.StatusNoContent)
+			drainAndClose(res)
+			if err != nil {
 				printMessage(message, err)
 				return false
 			}
@@ -164,9 +228,10 @@ func mainRemoveObjectExists(config ServerConfig, curTest int) bool {
 				printMessage(message, err)
 				return false
 			}
-			defer closeResponse(res)
 			// Verify the response.
-			if err := removeObjectVerify(res, http.StatusNoContent); err != nil {
+			err = removeObjectVerify(res, http.StatusNoContent)
+			drainAndClose(res)
+			if err != nil {
 				printMessage(message, err)
 				return false
 			}
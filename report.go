@@ -0,0 +1,353 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/mc/pkg/console"
+)
+
+// The formats test results can be reported in.
+const (
+	outputHuman = "human"
+	outputJSON  = "json"
+	outputJUnit = "junit"
+)
+
+// reporter - presents the result of every test.
+type reporter interface {
+	// start - a test of the feature area is about to run.
+	start(area string)
+	// result - the test described by message finished, it failed if err is not nil.
+	result(message string, err error)
+	// detail - extra information about the last result.
+	detail(detail string)
+	// fail - the test of the last result failed after all with err, e.g. in its Teardown.
+	fail(err error)
+	// info - information about the run as a whole.
+	info(info string)
+	// finish - all tests have run.
+	finish()
+}
+
+// newReporter - the reporter for format.
+func newReporter(format string) (reporter, error) {
+	switch format {
+	case outputHuman:
+		return &humanReporter{}, nil
+	case outputJSON, outputJUnit:
+		return &recordReporter{format: format}, nil
+	}
+	err := fmt.Errorf("Invalid Output Format: wanted %v, %v or %v, got %q", outputHuman, outputJSON, outputJUnit, format)
+	return nil, err
+}
+
+// humanReporter - prints a line per test for a person watching the run and a summary at the end.
+type humanReporter struct {
+	testRecorder
+}
+
+func (r *humanReporter) result(message string, err error) {
+	record := r.record(message, err)
+	if globalVerbosity < verbosityNormal {
+		return
+	}
+	// Erase the old progress line.
+	console.Eraseline()
+	if err != nil {
+		message += strings.Repeat(" ", messageWidth-len([]rune(message))) + "[FAIL]\n" + err.Error()
+		console.Println(message)
+	} else {
+		message += strings.Repeat(" ", messageWidth-len([]rune(message))) + "[OK]"
+		if globalShowDurations {
+			message += " " + formatDurationMs(record.DurationMs)
+		}
+		console.Println(message)
+	}
+}
+
+func (r *humanReporter) detail(detail string) {
+	r.addDetail(detail)
+	if globalVerbosity < verbosityNormal {
+		return
+	}
+	console.Println("\t" + detail)
+}
+
+func (r *humanReporter) fail(err error) {
+	r.failLast(err)
+	if globalVerbosity < verbosityNormal {
+		return
+	}
+	console.Println("\t[FAIL] " + err.Error())
+}
+
+func (r *humanReporter) info(info string) {
+	// Erase the progress line a request may be logged in the middle of.
+	console.Eraseline()
+	console.Println(info)
+}
+
+func (r *humanReporter) finish() {
+	console.Println(r.summary())
+}
+
+// testRecord - the result of a single test.
+type testRecord struct {
+	TestName   string   `json:"testName"`
+	Area       string   `json:"area,omitempty"` // The feature area of the test.
+	Status     string   `json:"status"`         // One of passed, failed or skipped.
+	DurationMs int64    `json:"durationMs"`
+	Error      string   `json:"error,omitempty"`
+	Category   string   `json:"category,omitempty"` // The kind of mismatch the test failed on, e.g. status.
+	Details    []string `json:"details,omitempty"`
+}
+
+// testNumberPrefix - the [NN/NN] counter at the start of a test message.
+var testNumberPrefix = regexp.MustCompile(`^\[\d+/\d+\] `)
+
+// testRecorder - records the result and duration of every test as it is reported.
+type testRecorder struct {
+	started    time.Time // When the current test started.
+	runStarted time.Time // When the first test started.
+	area       string    // The feature area of the current test.
+	records    []testRecord
+}
+
+func (r *testRecorder) start(area string) {
+	r.started = time.Now()
+	r.area = area
+	if r.runStarted.IsZero() {
+		r.runStarted = r.started
+	}
+}
+
+// record - records the result of the current test.
+func (r *testRecorder) record(message string, err error) testRecord {
+	record := testRecord{
+		TestName:   strings.TrimSuffix(testNumberPrefix.ReplaceAllString(message, ""), ":"),
+		Area:       r.area,
+		Status:     "passed",
+		DurationMs: int64(time.Since(r.started) / time.Millisecond),
+	}
+	if err != nil {
+		record.Status = "failed"
+		record.Error = err.Error()
+		record.Category = errorCategory(err)
+	}
+	r.records = append(r.records, record)
+	return record
+}
+
+// addDetail - adds detail to the result recorded last.
+func (r *testRecorder) addDetail(detail string) {
+	if len(r.records) == 0 {
+		return
+	}
+	last := &r.records[len(r.records)-1]
+	// A test that passes without checking anything reports why with a Skipped detail.
+	if strings.HasPrefix(detail, "Skipped: ") && last.Status == "passed" {
+		last.Status = "skipped"
+	}
+	last.Details = append(last.Details, detail)
+}
+
+// failLast - marks the result recorded last as failed with err, keeping any error it already had.
+func (r *testRecorder) failLast(err error) {
+	if len(r.records) == 0 {
+		return
+	}
+	last := &r.records[len(r.records)-1]
+	last.Status = "failed"
+	if last.Error != "" {
+		last.Error += "\n" + err.Error()
+	} else {
+		last.Error = err.Error()
+	}
+	if last.Category == "" {
+		last.Category = errorCategory(err)
+	}
+}
+
+// slowestTests - the number of slowest tests named in the summary.
+const slowestTests = 3
+
+// summary - the number of tests that passed and failed, the time they took, the slowest of them
+// and the time spent in every feature area.
+func (r *testRecorder) summary() string {
+	var passed, failed, skipped int
+	for _, record := range r.records {
+		switch record.Status {
+		case "passed":
+			passed++
+		case "failed":
+			failed++
+		case "skipped":
+			skipped++
+		}
+	}
+	var elapsed time.Duration
+	if !r.runStarted.IsZero() {
+		elapsed = time.Since(r.runStarted)
+	}
+	summary := fmt.Sprintf("Tests: %d, passed: %d, failed: %d, skipped: %d, time: %s",
+		len(r.records), passed, failed, skipped, formatDurationMs(int64(elapsed/time.Millisecond)))
+	slowest := make([]testRecord, len(r.records))
+	copy(slowest, r.records)
+	sort.Stable(slowestFirst(slowest))
+	if len(slowest) > slowestTests {
+		slowest = slowest[:slowestTests]
+	}
+	for i, record := range slowest {
+		if i == 0 {
+			summary += "\nSlowest:"
+		}
+		summary += fmt.Sprintf("\n\t%s %s", record.TestName, formatDurationMs(record.DurationMs))
+	}
+	if areas := areaSummary(r.records); areas != "" {
+		summary += "\n" + areas
+	}
+	return summary
+}
+
+// slowestFirst - sorts test records by descending duration.
+type slowestFirst []testRecord
+
+func (s slowestFirst) Len() int           { return len(s) }
+func (s slowestFirst) Less(i, j int) bool { return s[i].DurationMs > s[j].DurationMs }
+func (s slowestFirst) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// formatDurationMs - a duration in milliseconds as seconds to one decimal, e.g. 1.5s.
+func formatDurationMs(ms int64) string {
+	return fmt.Sprintf("%.1fs", float64(ms)/1000)
+}
+
+// recordReporter - collects the result of every test and writes them all as JSON or JUnit XML
+// to stdout once the run is over. Anything else, the summary included, is written to stderr so
+// stdout stays parseable.
+type recordReporter struct {
+	testRecorder
+	format string
+}
+
+func (r *recordReporter) result(message string, err error) {
+	r.record(message, err)
+}
+
+func (r *recordReporter) detail(detail string) {
+	r.addDetail(detail)
+}
+
+func (r *recordReporter) fail(err error) {
+	r.failLast(err)
+}
+
+func (r *recordReporter) info(info string) {
+	fmt.Fprintln(os.Stderr, info)
+}
+
+func (r *recordReporter) finish() {
+	fmt.Fprintln(os.Stderr, r.summary())
+	var output []byte
+	var err error
+	if r.format == outputJSON {
+		records := r.records
+		if records == nil {
+			records = []testRecord{}
+		}
+		output, err = json.MarshalIndent(records, "", "  ")
+	} else {
+		output, err = xml.MarshalIndent(newJUnitSuite(r.records), "", "  ")
+		output = append([]byte(xml.Header), output...)
+	}
+	if err != nil {
+		console.Fatalln(err)
+	}
+	os.Stdout.Write(append(output, '\n'))
+}
+
+// jUnitSuite - a JUnit XML testsuite as read by CI servers.
+type jUnitSuite struct {
+	XMLName  xml.Name    `xml:"testsuite"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []jUnitCase `xml:"testcase"`
+}
+
+// jUnitCase - a JUnit XML testcase.
+type jUnitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *jUnitMessage `xml:"failure,omitempty"`
+	Skipped   *jUnitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// jUnitMessage - the reason a testcase failed or was skipped.
+type jUnitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// jUnitSeconds - a duration in milliseconds as JUnit seconds.
+func jUnitSeconds(ms int64) string {
+	return fmt.Sprintf("%.3f", float64(ms)/1000)
+}
+
+// newJUnitSuite - the JUnit testsuite of records.
+func newJUnitSuite(records []testRecord) jUnitSuite {
+	suite := jUnitSuite{
+		Name:  "s3verify",
+		Tests: len(records),
+	}
+	var totalMs int64
+	for _, record := range records {
+		testCase := jUnitCase{
+			Name:      record.TestName,
+			ClassName: "s3verify",
+			Time:      jUnitSeconds(record.DurationMs),
+			SystemOut: strings.Join(record.Details, "\n"),
+		}
+		switch record.Status {
+		case "failed":
+			suite.Failures++
+			// The first line of an error is a summary, the rest are details.
+			summary := strings.SplitN(record.Error, "\n", 2)[0]
+			testCase.Failure = &jUnitMessage{Message: summary, Type: record.Category, Body: record.Error}
+		case "skipped":
+			suite.Skipped++
+			testCase.Skipped = &jUnitMessage{Message: record.Details[0]}
+		}
+		totalMs += record.DurationMs
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	suite.Time = jUnitSeconds(totalMs)
+	return suite
+}
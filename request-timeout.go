@@ -0,0 +1,103 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// The connection settings used when they are not configured.
+const (
+	defaultRequestTimeout      = 30 * time.Second
+	defaultDialTimeout         = 10 * time.Second
+	defaultMaxIdleConnsPerHost = 100
+)
+
+// requestTimeout - how long a request may take, from sending it until its response body is closed.
+func (c ServerConfig) requestTimeout() time.Duration {
+	if c.RequestTimeout <= 0 {
+		return defaultRequestTimeout
+	}
+	return c.RequestTimeout
+}
+
+// dialTimeout - how long opening a connection may take.
+func (c ServerConfig) dialTimeout() time.Duration {
+	if c.DialTimeout <= 0 {
+		return defaultDialTimeout
+	}
+	return c.DialTimeout
+}
+
+// maxIdleConnsPerHost - the number of idle connections kept open for reuse.
+func (c ServerConfig) maxIdleConnsPerHost() int {
+	if c.MaxIdleConnsPerHost <= 0 {
+		return defaultMaxIdleConnsPerHost
+	}
+	return c.MaxIdleConnsPerHost
+}
+
+// timedOut - replace an error caused by the deadline of ctx passing with one saying why.
+func timedOut(ctx context.Context, timeout time.Duration, err error) error {
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("Request Timed Out: no complete response within %v", timeout)
+	}
+	return err
+}
+
+// deadlineBody - a response body read under the deadline of its request, which is released once it is closed.
+type deadlineBody struct {
+	io.ReadCloser
+	ctx     context.Context
+	cancel  context.CancelFunc
+	timeout time.Duration
+}
+
+func (b deadlineBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		return n, err
+	}
+	return n, timedOut(b.ctx, b.timeout, err)
+}
+
+func (b deadlineBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// do - send req, canceling it if it has not completed within the request timeout.
+func (c ServerConfig) do(req *http.Request) (*http.Response, error) {
+	timeout := c.requestTimeout()
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	req = req.WithContext(ctx)
+	started := time.Now()
+	res, err := c.Client.Do(req)
+	elapsed := time.Since(started)
+	logRequest(req, res, err, elapsed)
+	globalMetrics.observe(req, res, err, elapsed)
+	if err != nil {
+		cancel()
+		return nil, timedOut(ctx, timeout, err)
+	}
+	res.Body = deadlineBody{ReadCloser: res.Body, ctx: ctx, cancel: cancel, timeout: timeout}
+	return res, nil
+}
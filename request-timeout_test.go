@@ -0,0 +1,102 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test that a request is canceled once the request timeout passes, both when the server never
+// answers and when it answers but keeps trickling the response body.
+func TestRequestTimeout(t *testing.T) {
+	timeout := 500 * time.Millisecond
+	testCases := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"no response", func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}},
+		{"trickling body", func(w http.ResponseWriter, r *http.Request) {
+			writeS3Headers(w)
+			w.WriteHeader(http.StatusOK)
+			// Progress every so often, but never complete.
+			for {
+				select {
+				case <-r.Context().Done():
+					return
+				case <-time.After(timeout / 10):
+				}
+				w.Write([]byte("s"))
+				w.(http.Flusher).Flush()
+			}
+		}},
+	}
+	for _, testCase := range testCases {
+		server := httptest.NewServer(testCase.handler)
+		config := newTestConfig(server.URL)
+		config.RequestTimeout = timeout
+		config.MaxRetries = 0
+		req, err := newGetObjectReq("s3verify-request-timeout", "object", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		started := time.Now()
+		res, err := config.execRequest("GET", req)
+		if err == nil {
+			err = verifyBodyGetObject(res.Body, nil)
+			drainAndClose(res)
+		}
+		elapsed := time.Since(started)
+		server.Close()
+		if err == nil || !strings.HasPrefix(err.Error(), "Request Timed Out:") {
+			t.Errorf("%s: Expected the request to time out, got %v", testCase.name, err)
+		}
+		// The request must be canceled soon after the timeout, not when the server gives up.
+		if elapsed > 3*timeout {
+			t.Errorf("%s: Expected the request canceled after about %v, took %v", testCase.name, timeout, elapsed)
+		}
+	}
+}
+
+// Test that a request completing within the request timeout is not affected by it.
+func TestRequestTimeoutNotReached(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeS3Headers(w)
+		w.Write([]byte("s3verify"))
+	}))
+	defer server.Close()
+
+	config := newTestConfig(server.URL)
+	config.RequestTimeout = time.Second
+	req, err := newGetObjectReq("s3verify-request-timeout", "object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer drainAndClose(res)
+	if err := getObjectVerify(res, []byte("s3verify"), http.StatusOK, nil); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -18,12 +18,15 @@ package main
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"time"
 
+	"github.com/minio/s3verify/signv2"
 	"github.com/minio/s3verify/signv4"
 )
 
@@ -42,19 +45,36 @@ type Request struct {
 	queryValues url.Values
 
 	contentLength int64
+	chunked       bool // Send the body with chunked transfer encoding, Content-Length is then omitted.
+
+	trace *httptrace.ClientTrace // Optional hooks to instrument the request with.
 }
 
 // execRequest - Executes an HTTP request creating an HTTP response and implements retry logic for predefined retryable errors.
 func (c ServerConfig) execRequest(method string, customReq Request) (resp *http.Response, err error) {
 	var isRetryable bool     // Indicates if request can be retried.
 	var bodySeeker io.Seeker // io.Seeking for seeking.
+	var regionRetried bool   // Indicates if the request was already re-signed for a region the server asked for.
 	if customReq.contentBody != nil {
 		// Check if body is seekable then it is retryable.
 		bodySeeker, isRetryable = customReq.contentBody.(io.Seeker)
 	}
 
-	// Do not need the index.
-	for _ = range newRetryTimer(MaxRetry, time.Second, time.Second*30, MaxJitter, globalRandom) {
+	// Non-idempotent requests are only sent again if the server certainly did not act on them.
+	maxRetries := c.MaxRetries
+	if !isIdempotent(method, customReq) {
+		maxRetries = 0
+	}
+	// A body that cannot be rewound has been consumed by the first attempt.
+	if customReq.contentBody != nil && !isRetryable {
+		maxRetries = 0
+	}
+	var wait time.Duration // How long to wait before the next attempt.
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(wait)
+		}
+		wait = exponentialBackoffWait(attempt, c.RetryDelay, retryCap, MaxJitter, globalRandom)
 		if isRetryable {
 			// Seek back to beginning for each attempt.
 			if _, err := bodySeeker.Seek(0, 0); err != nil {
@@ -62,6 +82,14 @@ func (c ServerConfig) execRequest(method string, customReq Request) (resp *http.
 				return resp, err
 			}
 		}
+		// Every attempt is a request of its own as far as the rate limit is concerned.
+		globalRateLimiter.wait()
+		// Every attempt sends the body again so every attempt counts against the upload budget.
+		if method == "PUT" && customReq.contentLength > 0 {
+			if err := globalUploadBudget.reserve(customReq.contentLength); err != nil {
+				return nil, err
+			}
+		}
 		// Create a new request.
 		var req *http.Request
 		req, err = c.newRequest(method, customReq)
@@ -72,7 +100,7 @@ func (c ServerConfig) execRequest(method string, customReq Request) (resp *http.
 			}
 			return nil, err
 		}
-		resp, err = c.Client.Do(req)
+		resp, err = c.do(req)
 		if err != nil {
 			// For supported network errors verify.
 			if isNetErrorRetryable(err) {
@@ -84,11 +112,13 @@ func (c ServerConfig) execRequest(method string, customReq Request) (resp *http.
 		// For any known successful http status, return quickly.
 		for _, httpStatus := range successStatus {
 			if httpStatus == resp.StatusCode {
+				globalManifest.record(method, customReq, resp)
 				return resp, nil
 			}
 		}
 		// Read the body to be saved later.
 		errBodyBytes, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
 		if err != nil {
 			return resp, err
 		}
@@ -99,29 +129,63 @@ func (c ServerConfig) execRequest(method string, customReq Request) (resp *http.
 		// For errors verify if its retryable otherwise fail quickly.
 		errResponse := ToErrorResponse(httpRespToErrorResponse(resp, customReq.bucketName, customReq.objectName))
 
-		//Verify if error response code is retryable.
-		if isS3CodeRetryable(errResponse.Code) {
+		// Save the body back again, the last response is returned as is once retries run out.
+		errBodySeeker.Seek(0, 0) // Seek back to starting point.
+
+		// A request signed for the wrong region is re-signed for the one the server names, once.
+		// The server rejected it before acting on it so this is safe even if it is not idempotent,
+		// but only if the body can be sent again. Otherwise the error is returned as it is, as it is
+		// when the region was given with --signing-region, which is never replaced behind the user's back.
+		canResend := customReq.contentBody == nil || isRetryable
+		if region := malformedAuthorizationRegion(errResponse, c.signingRegion()); region != "" && !regionRetried && c.SigningRegion == "" && canResend {
+			regionRetried = true
+			c.SigningRegion = region
+			globalLearnedRegion.set(region)
+			maxRetries++
+			wait = 0
 			continue // Retry.
 		}
-		// Verify if http status code is retryable.
-		if isHTTPStatusRetryable(resp.StatusCode) {
+
+		// Verify if the error response code or http status code is retryable.
+		if isS3CodeRetryable(errResponse.Code) || isHTTPStatusRetryable(resp.StatusCode) {
+			// A server that is shedding load may say when to come back.
+			if retryAfter, ok := retryAfterWait(resp.Header, retryCap); ok {
+				wait = retryAfter
+			}
 			continue // Retry.
 		}
 
-		// Save the body back again.
-		errBodySeeker.Seek(0, 0) // Seek back to starting point.
-		resp.Body = ioutil.NopCloser(errBodySeeker)
-
 		// For all other cases break out of the retry loop.
 		break
 	}
 	return resp, err
 }
 
+// execRequestOnce - Executes an HTTP request a single time, without retrying any error.
+// Used for uploads so large that sending them more than once must be avoided.
+func (c ServerConfig) execRequestOnce(method string, customReq Request) (*http.Response, error) {
+	globalRateLimiter.wait()
+	if method == "PUT" && customReq.contentLength > 0 {
+		if err := globalUploadBudget.reserve(customReq.contentLength); err != nil {
+			return nil, err
+		}
+	}
+	req, err := c.newRequest(method, customReq)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	globalManifest.record(method, customReq, res)
+	return res, nil
+}
+
 // newRequest - create an HTTP request out of a customRequest.
 func (c ServerConfig) newRequest(method string, customReq Request) (req *http.Request, err error) {
 	// Construct a new target URL.
-	targetURL, err := makeTargetURL(c.Endpoint, customReq.bucketName, customReq.objectName, c.Region, customReq.queryValues)
+	targetURL, err := c.targetURL(customReq.bucketName, customReq.objectName, customReq.queryValues)
 	if err != nil {
 		return nil, err
 	}
@@ -145,19 +209,79 @@ func (c ServerConfig) newRequest(method string, customReq Request) (req *http.Re
 		req.Header.Set(k, v[0])
 	}
 
-	// Set ContentLength.
-	if customReq.contentLength > 0 {
+	// Set ContentLength, or chunked encoding which never goes together with it.
+	if customReq.chunked {
+		req.TransferEncoding = []string{"chunked"}
+		req.ContentLength = -1
+	} else if customReq.contentLength > 0 {
 		req.ContentLength = customReq.contentLength
 	}
 
+	// Add any headers given on the command line that are to be signed.
+	globalCustomHeaders.setSigned(req)
+
+	// Get the credentials to sign with, a session token is signed along with the request.
+	access, secret, token, err := c.credentials()
+	if err != nil {
+		return nil, err
+	}
+	if token != "" && customReq.presignURL {
+		query := req.URL.Query()
+		query.Set("X-Amz-Security-Token", token)
+		req.URL.RawQuery = query.Encode()
+	} else if token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+	}
+
 	// Sign the request.
 	if customReq.presignURL {
 		// Presign the request.
-		req = signv4.PreSignV4(*req, c.Access, c.Secret, c.Region, customReq.expires)
+		req = signv4.PreSignV4(*req, access, secret, c.signingRegion(), customReq.expires)
+	} else if c.isSignatureV2() {
+		// Signature v2 does not sign the payload and some servers reject requests carrying its hash.
+		req.Header.Del("X-Amz-Content-Sha256")
+		req = signv2.SignV2(*req, access, secret)
 	} else {
 		// Else use regular signature v4.
-		req = signv4.SignV4(*req, c.Access, c.Secret, c.Region)
+		req = signv4.SignV4(*req, access, secret, c.signingRegion())
+	}
+
+	// Add any headers given on the command line that are not to be signed.
+	globalCustomHeaders.setUnsigned(req)
+
+	// Only check the framing once every header, those given on the command line included, is set.
+	if err := verifyRequestFraming(req); err != nil {
+		return nil, err
+	}
+
+	// Wait for the server's go-ahead before sending a body, if asked to.
+	if c.ExpectContinue && req.ContentLength != 0 {
+		req.Header.Set("Expect", "100-continue")
+	}
+
+	// Attach the trace if one was requested.
+	if customReq.trace != nil {
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), customReq.trace))
 	}
 
 	return req, nil
 }
+
+// verifyRequestFraming - guard against sending both Content-Length and Transfer-Encoding.
+// The two are mutually exclusive (RFC 7230 section 3.3.2) and some servers reject such
+// requests outright, which would be reported as a failure of the server instead of s3verify.
+func verifyRequestFraming(req *http.Request) error {
+	if req.Header.Get("Transfer-Encoding") != "" {
+		err := fmt.Errorf("Request Framing Error: Transfer-Encoding must be selected with chunked, not set as a header")
+		return err
+	}
+	if req.Header.Get("Content-Length") != "" {
+		err := fmt.Errorf("Request Framing Error: Content-Length must be selected with contentLength, not set as a header")
+		return err
+	}
+	if len(req.TransferEncoding) > 0 && req.ContentLength >= 0 {
+		err := fmt.Errorf("Request Framing Error: Content-Length %d set on a request with Transfer-Encoding %v", req.ContentLength, req.TransferEncoding)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Test that a chunked upload goes out with chunked transfer encoding and no Content-Length.
+func TestChunkedRequestFraming(t *testing.T) {
+	var transferEncoding []string
+	var contentLength int64
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		transferEncoding = r.TransferEncoding
+		contentLength = r.ContentLength
+		body, _ = ioutil.ReadAll(r.Body)
+		writeS3Headers(w)
+	}))
+	defer server.Close()
+
+	config := newTestConfig(server.URL)
+	req, err := newPutObjectChunkedReq("s3verify-test", "s3verify/chunked", []byte("s3verify"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	drainAndClose(res)
+	if strings.Join(transferEncoding, ",") != "chunked" {
+		t.Errorf("Expected Transfer-Encoding [chunked], got %v", transferEncoding)
+	}
+	if contentLength != -1 {
+		t.Errorf("Expected no Content-Length, got %d", contentLength)
+	}
+	if string(body) != "s3verify" {
+		t.Errorf("Expected the body to arrive intact, got %q", body)
+	}
+}
+
+// Test that a request carrying both Content-Length and Transfer-Encoding is refused before it is sent,
+// also when the offending header is given on the command line.
+func TestRequestFramingRejected(t *testing.T) {
+	testCases := []struct {
+		name     string
+		chunked  bool
+		signed   []string
+		unsigned []string
+	}{
+		{"Chunked with --header Content-Length", true, nil, []string{"Content-Length: 8"}},
+		{"Chunked with --signed-header Content-Length", true, []string{"Content-Length: 8"}, nil},
+		{"Sized with --header Transfer-Encoding", false, nil, []string{"Transfer-Encoding: chunked"}},
+	}
+	for _, testCase := range testCases {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			writeS3Headers(w)
+		}))
+		config := newTestConfig(server.URL)
+		headers, err := newExtraHeaders(testCase.signed, testCase.unsigned)
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.name, err)
+		}
+		globalCustomHeaders = headers
+		req, err := newPutObjectReq("s3verify-test", "s3verify/framing", []byte("s3verify"))
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.name, err)
+		}
+		req.chunked = testCase.chunked
+		_, err = config.execRequest("PUT", req)
+		server.Close()
+		globalCustomHeaders = nil
+		if err == nil || !strings.HasPrefix(err.Error(), "Request Framing Error: ") {
+			t.Errorf("%s: Expected a Request Framing Error, got %v", testCase.name, err)
+		}
+		if requests != 0 {
+			t.Errorf("%s: Expected nothing to be sent, the server got %d requests", testCase.name, requests)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// callResetHook - ask a user provided endpoint to reset server state before a run.
+//
+// The contract is intentionally minimal: s3verify sends an empty POST request to
+// hookURL and expects any 2xx status back. Any other status or a network error
+// aborts the run, since tests would otherwise run against unknown state.
+func callResetHook(hookURL string) error {
+	client := &http.Client{
+		// Resetting state may take a while on larger deployments.
+		Timeout: 60 * time.Second,
+	}
+	req, err := http.NewRequest("POST", hookURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", appUserAgent)
+	res, err := client.Do(req)
+	if err != nil {
+		err = fmt.Errorf("Reset hook %s could not be reached: %v", hookURL, err)
+		return err
+	}
+	defer drainAndClose(res)
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		err := fmt.Errorf("Reset hook %s failed: wanted a 2xx status, got %s", hookURL, res.Status)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test that the reset hook receives an empty POST and that anything but a 2xx answer aborts with a clear error.
+func TestCallResetHook(t *testing.T) {
+	testCases := []struct {
+		status int
+		err    string // The error expected, %s is replaced by the hook URL. Empty if the hook succeeds.
+	}{
+		{http.StatusOK, ""},
+		{http.StatusNoContent, ""},
+		{http.StatusInternalServerError, "Reset hook %s failed: wanted a 2xx status, got 500 Internal Server Error"},
+		{http.StatusNotFound, "Reset hook %s failed: wanted a 2xx status, got 404 Not Found"},
+	}
+	for i, testCase := range testCases {
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if r.Method != "POST" || r.ContentLength > 0 {
+				t.Errorf("Test %d: Expected an empty POST, got %s with %d bytes", i+1, r.Method, r.ContentLength)
+			}
+			w.WriteHeader(testCase.status)
+		}))
+		hookURL := server.URL + "/reset"
+		err := callResetHook(hookURL)
+		server.Close()
+		if calls != 1 {
+			t.Errorf("Test %d: Expected the hook to be called once, got %d", i+1, calls)
+		}
+		if testCase.err == "" && err != nil {
+			t.Errorf("Test %d: Expected no error, got %q", i+1, err)
+		}
+		if expected := fmt.Sprintf(testCase.err, hookURL); testCase.err != "" && (err == nil || err.Error() != expected) {
+			t.Errorf("Test %d: Expected error %q, got %v", i+1, expected, err)
+		}
+	}
+}
+
+// Test that an unreachable reset hook aborts the run.
+func TestCallResetHookUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	hookURL := server.URL + "/reset"
+	server.Close()
+	if err := callResetHook(hookURL); err == nil {
+		t.Fatal("Expected an error for an unreachable hook")
+	}
+}
@@ -0,0 +1,105 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+)
+
+// A response whose first byte arrives this late relative to its last byte is reported as buffered:
+// the server most likely assembled the whole object before it started to respond.
+const bufferedResponseRatio = 0.9
+
+// responseTiming - records time to first byte and time to last byte for a single response.
+type responseTiming struct {
+	start     time.Time
+	firstByte time.Time
+	lastByte  time.Time
+}
+
+// newResponseTiming - create a new responseTiming and the trace that fills it in.
+// The trace must be set on the Request before it is executed.
+func newResponseTiming() (*responseTiming, *httptrace.ClientTrace) {
+	timing := &responseTiming{}
+	trace := &httptrace.ClientTrace{
+		// Called for every attempt, so retries restart the clock.
+		GetConn: func(string) {
+			timing.start = time.Now().UTC()
+		},
+		GotFirstResponseByte: func() {
+			timing.firstByte = time.Now().UTC()
+		},
+	}
+	return timing, trace
+}
+
+// timedBody - wraps a response body to record when its last byte was read.
+type timedBody struct {
+	io.ReadCloser
+	timing *responseTiming
+}
+
+// Read - read from the body and record the time once it is exhausted.
+func (t timedBody) Read(p []byte) (int, error) {
+	n, err := t.ReadCloser.Read(p)
+	if err == io.EOF && t.timing.lastByte.IsZero() {
+		t.timing.lastByte = time.Now().UTC()
+	}
+	return n, err
+}
+
+// track - wrap the body of res so that reading it to EOF records the time to last byte.
+func (t *responseTiming) track(res *http.Response) {
+	res.Body = timedBody{
+		ReadCloser: res.Body,
+		timing:     t,
+	}
+}
+
+// TTFB - time from the start of the request until the first response byte arrived.
+func (t *responseTiming) TTFB() time.Duration {
+	return t.firstByte.Sub(t.start)
+}
+
+// TTLB - time from the start of the request until the last body byte was read.
+func (t *responseTiming) TTLB() time.Duration {
+	return t.lastByte.Sub(t.start)
+}
+
+// isBuffered - report whether the server appears to have buffered the entire body before responding.
+func (t *responseTiming) isBuffered() bool {
+	if t.TTLB() <= 0 {
+		return false
+	}
+	return float64(t.TTFB()) >= bufferedResponseRatio*float64(t.TTLB())
+}
+
+// String - a one line summary of the timing.
+func (t *responseTiming) String() string {
+	if t.firstByte.IsZero() || t.lastByte.IsZero() {
+		return "Response timing incomplete: body was not read to the end"
+	}
+	mode := "streamed"
+	if t.isBuffered() {
+		mode = "buffered"
+	}
+	return fmt.Sprintf("TTFB %v, TTLB %v (%s)", t.TTFB(), t.TTLB(), mode)
+}
@@ -21,12 +21,13 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
 
-// MaxRetry is the maximum number of retries before stopping.
-var MaxRetry = 5
+// retryCap is the longest wait between two attempts, including waits asked for with Retry-After.
+const retryCap = time.Second * 30
 
 // MaxJitter will randomize over the full exponential backoff time
 const MaxJitter = 1.0
@@ -41,29 +42,6 @@ var TCPretry = []string{"i/o timeout", "net/http: TLS handshake timeout", "conne
 // until the maximum retry attempts are reached.
 func newRetryTimer(maxRetry int, unit time.Duration, cap time.Duration, jitter float64, rand *rand.Rand) <-chan int {
 	attemptCh := make(chan int)
-
-	// computes the exponential backoff duration according to
-	// https://www.awsarchitectureblog.com/2015/03/backoff.html
-	exponentialBackoffWait := func(attempt int) time.Duration {
-		// normalize jitter to the range [0, 1.0]
-		if jitter < NoJitter {
-			jitter = NoJitter
-		}
-		if jitter > MaxJitter {
-			jitter = MaxJitter
-		}
-
-		//sleep = random_between(0, min(cap, base * 2 ** attempt))
-		sleep := unit * time.Duration(1<<uint(attempt))
-		if sleep > cap {
-			sleep = cap
-		}
-		if jitter != NoJitter {
-			sleep -= time.Duration(rand.Float64() * float64(sleep) * jitter)
-		}
-		return sleep
-	}
-
 	go func() {
 		defer close(attemptCh)
 		for i := 0; i < maxRetry; i++ {
@@ -71,12 +49,69 @@ func newRetryTimer(maxRetry int, unit time.Duration, cap time.Duration, jitter f
 			// Attempts start from 1.
 			case attemptCh <- i + 1:
 			}
-			time.Sleep(exponentialBackoffWait(i))
+			time.Sleep(exponentialBackoffWait(i, unit, cap, jitter, rand))
 		}
 	}()
 	return attemptCh
 }
 
+// exponentialBackoffWait computes the exponential backoff duration according to
+// https://www.awsarchitectureblog.com/2015/03/backoff.html
+func exponentialBackoffWait(attempt int, unit time.Duration, cap time.Duration, jitter float64, rand *rand.Rand) time.Duration {
+	// normalize jitter to the range [0, 1.0]
+	if jitter < NoJitter {
+		jitter = NoJitter
+	}
+	if jitter > MaxJitter {
+		jitter = MaxJitter
+	}
+
+	//sleep = random_between(0, min(cap, base * 2 ** attempt))
+	sleep := cap
+	// Large attempts would overflow, they are well past the cap anyway.
+	if attempt < 32 && unit*time.Duration(1<<uint(attempt)) < cap {
+		sleep = unit * time.Duration(1<<uint(attempt))
+	}
+	if jitter != NoJitter {
+		sleep -= time.Duration(rand.Float64() * float64(sleep) * jitter)
+	}
+	return sleep
+}
+
+// retryAfterWait - the wait asked for by a Retry-After header, in seconds or as an HTTP date, capped at cap.
+func retryAfterWait(header http.Header, cap time.Duration) (time.Duration, bool) {
+	retryAfter := header.Get("Retry-After")
+	if retryAfter == "" {
+		return 0, false
+	}
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(retryAfter); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(retryAfter); err == nil {
+		wait = date.Sub(time.Now())
+	} else {
+		return 0, false
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	if wait > cap {
+		wait = cap
+	}
+	return wait, true
+}
+
+// isIdempotent - report whether sending a request twice has the same effect as sending it once.
+// POST initiates and completes multipart uploads, which must not be repeated blindly. The only
+// exception is DeleteObjects, deleting the same keys again changes nothing.
+func isIdempotent(method string, customReq Request) bool {
+	if method != "POST" {
+		return true
+	}
+	_, isDelete := customReq.queryValues["delete"]
+	return isDelete
+}
+
 // isNetErrorRetryable - is network error retryable.
 func isNetErrorRetryable(err error) bool {
 	switch err.(type) {
@@ -0,0 +1,118 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// newFlakyServer - a server that answers the first failures requests with 503 SlowDown and
+// all others with 200 OK. The returned function reports how many requests were received.
+func newFlakyServer(failures int) (*httptest.Server, func() int) {
+	var mutex sync.Mutex
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		attempts++
+		attempt := attempts
+		mutex.Unlock()
+		if attempt <= failures {
+			writeS3Error(w, http.StatusServiceUnavailable, "SlowDown", "")
+			return
+		}
+		writeS3Headers(w)
+		w.WriteHeader(http.StatusOK)
+	}))
+	return server, func() int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return attempts
+	}
+}
+
+// Test that a request failing twice with a retryable error succeeds on the third attempt.
+func TestRetrySucceeds(t *testing.T) {
+	server, attempts := newFlakyServer(2)
+	defer server.Close()
+
+	config := newTestConfig(server.URL)
+	req, err := newGetObjectReq("s3verify-retry", "object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer drainAndClose(res)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, res.StatusCode)
+	}
+	if attempts() != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", attempts())
+	}
+}
+
+// Test that the last response is returned once the retries run out.
+func TestRetryGivesUp(t *testing.T) {
+	server, attempts := newFlakyServer(10)
+	defer server.Close()
+
+	config := newTestConfig(server.URL)
+	config.MaxRetries = 2
+	req, err := newGetObjectReq("s3verify-retry", "object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer drainAndClose(res)
+	if res.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status %d, got %d", http.StatusServiceUnavailable, res.StatusCode)
+	}
+	if attempts() != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", attempts())
+	}
+}
+
+// Test that a request that is not idempotent is never sent a second time.
+func TestRetryNotIdempotent(t *testing.T) {
+	server, attempts := newFlakyServer(2)
+	defer server.Close()
+
+	config := newTestConfig(server.URL)
+	req, err := newInitiateMultipartUploadReq("s3verify-retry", "object")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := config.execRequest("POST", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer drainAndClose(res)
+	if res.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status %d, got %d", http.StatusServiceUnavailable, res.StatusCode)
+	}
+	if attempts() != 1 {
+		t.Fatalf("Expected 1 attempt, got %d", attempts())
+	}
+}
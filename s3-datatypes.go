@@ -50,6 +50,27 @@ type owner struct {
 	ID          string
 }
 
+// grantee container for the entity a grant is given to.
+type grantee struct {
+	ID          string
+	DisplayName string
+	URI         string
+}
+
+// grant container for a single permission given to a grantee.
+type grant struct {
+	Grantee    grantee
+	Permission string
+}
+
+// accessControlPolicy container for GetObjectACL and GetBucketACL responses.
+type accessControlPolicy struct {
+	Owner             owner
+	AccessControlList struct {
+		Grant []grant
+	}
+}
+
 // commonPrefix container for prefix response.
 type commonPrefix struct {
 	Prefix string
@@ -105,6 +126,9 @@ type listBucketV2Result struct {
 	MaxKeys     int64
 	Name        string
 
+	// The number of keys and common prefixes returned on this page.
+	KeyCount int
+
 	// Hold the token that will be sent in the next request to fetch the next group of keys
 	NextContinuationToken string
 
@@ -203,3 +227,145 @@ type listObjectPartsResult struct {
 
 	EncodingType string
 }
+
+// versioningConfiguration container for bucket versioning configuration.
+type versioningConfiguration struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ VersioningConfiguration" json:"-"`
+	// Status is one of Enabled or Suspended. Left empty by buckets which
+	// never had versioning configured.
+	Status    string `xml:"Status,omitempty"`
+	MFADelete string `xml:"MfaDelete,omitempty"`
+}
+
+// applySSEByDefault container for the encryption applied to objects uploaded without any.
+type applySSEByDefault struct {
+	SSEAlgorithm   string
+	KMSMasterKeyID string `xml:"KMSMasterKeyID,omitempty"`
+}
+
+// sseRule container for a single default encryption rule.
+type sseRule struct {
+	ApplyServerSideEncryptionByDefault applySSEByDefault
+	BucketKeyEnabled                   bool `xml:",omitempty"`
+}
+
+// sseConfiguration container for bucket default encryption configuration.
+type sseConfiguration struct {
+	XMLName xml.Name  `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ServerSideEncryptionConfiguration" json:"-"`
+	Rules   []sseRule `xml:"Rule"`
+}
+
+// corsConfiguration container for bucket CORS configuration.
+type corsConfiguration struct {
+	XMLName   xml.Name   `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CORSConfiguration" json:"-"`
+	CORSRules []corsRule `xml:"CORSRule"`
+}
+
+// corsRule container for the cross-origin requests a single CORS rule allows.
+type corsRule struct {
+	AllowedOrigins []string `xml:"AllowedOrigin"`
+	AllowedMethods []string `xml:"AllowedMethod"`
+	AllowedHeaders []string `xml:"AllowedHeader,omitempty"`
+	MaxAgeSeconds  int      `xml:",omitempty"`
+}
+
+// objectRetention container for the retention of an object version under object lock.
+type objectRetention struct {
+	XMLName         xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ Retention" json:"-"`
+	Mode            string
+	RetainUntilDate time.Time
+}
+
+// objectLockConfiguration container for the object lock configuration of a bucket.
+type objectLockConfiguration struct {
+	XMLName           xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ObjectLockConfiguration" json:"-"`
+	ObjectLockEnabled string
+}
+
+// objectLegalHold container for the legal hold status of an object version.
+type objectLegalHold struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ LegalHold" json:"-"`
+	Status  string
+}
+
+// indexDocument container for the suffix served for requests on a directory.
+type indexDocument struct {
+	Suffix string
+}
+
+// websiteConfiguration container for bucket website configuration.
+type websiteConfiguration struct {
+	XMLName       xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ WebsiteConfiguration" json:"-"`
+	IndexDocument indexDocument
+}
+
+// objectVersion container for a single object version or delete marker.
+type objectVersion struct {
+	Key          string
+	VersionID    string `xml:"VersionId"`
+	IsLatest     bool
+	LastModified time.Time
+	ETag         string
+	Size         int64
+}
+
+// listVersionsResult container for ListObjectVersions response.
+type listVersionsResult struct {
+	Name                string
+	Prefix              string
+	KeyMarker           string
+	VersionIDMarker     string `xml:"VersionIdMarker"`
+	NextKeyMarker       string
+	NextVersionIDMarker string `xml:"NextVersionIdMarker"`
+	MaxKeys             int64
+	IsTruncated         bool
+	Versions            []objectVersion `xml:"Version"`
+	DeleteMarkers       []objectVersion `xml:"DeleteMarker"`
+}
+
+// deleteObject container for a single key of a DeleteObjects request.
+type deleteObject struct {
+	Key       string
+	VersionID string `xml:"VersionId,omitempty"`
+}
+
+// deleteObjects container for DeleteObjects request.
+type deleteObjects struct {
+	XMLName xml.Name       `xml:"Delete"`
+	Quiet   bool           `xml:"Quiet"`
+	Objects []deleteObject `xml:"Object"`
+}
+
+// deletedObject container for a key successfully removed by DeleteObjects.
+type deletedObject struct {
+	Key                   string
+	VersionID             string `xml:"VersionId"`
+	DeleteMarker          bool
+	DeleteMarkerVersionID string `xml:"DeleteMarkerVersionId"`
+}
+
+// deleteError container for a key DeleteObjects failed to remove.
+type deleteError struct {
+	Key       string
+	VersionID string `xml:"VersionId"`
+	Code      string
+	Message   string
+}
+
+// deleteObjectsResult container for DeleteObjects response.
+type deleteObjectsResult struct {
+	Deleted []deletedObject `xml:"Deleted"`
+	Errors  []deleteError   `xml:"Error"`
+}
+
+// objectTag container for a single tag of an object.
+type objectTag struct {
+	Key   string
+	Value string
+}
+
+// tagging container for the GetObjectTagging response and PutObjectTagging request.
+type tagging struct {
+	XMLName xml.Name    `xml:"Tagging"`
+	TagSet  []objectTag `xml:"TagSet>Tag"`
+}
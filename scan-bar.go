@@ -30,6 +30,8 @@ var cursorCh = cursorAnimate()
 // Set up a constant width to follow.
 const (
 	messageWidth = 50
+	// Width assumed when the terminal size is unknown.
+	defaultTermWidth = 80
 )
 
 /******************************** Scan Bar ************************************/
@@ -53,7 +55,8 @@ func scanBarFactory() scanBarFunc {
 	prevLineSize := 0
 	termWidth, e := pb.GetTerminalWidth()
 	if e != nil {
-		console.Fatalln("Unable to get terminal size. Please use --quiet option.")
+		// Not a terminal, e.g. output is piped, assume the usual width.
+		termWidth = defaultTermWidth
 	}
 
 	return func(message string) {
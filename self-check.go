@@ -0,0 +1,117 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// selfCheckStep - a single named step of the self-check.
+type selfCheckStep struct {
+	Name  string
+	Check func(ServerConfig) error
+}
+
+// Name of the throwaway bucket and object used by the self-check.
+var (
+	selfCheckBucket = "s3verify-selfcheck"
+	selfCheckObject = &ObjectInfo{
+		Key:  "s3verify/selfcheck",
+		Body: []byte("s3verify self-check"),
+	}
+)
+
+// selfCheckSteps - the minimal round trip run by --selfcheck, in order.
+var selfCheckSteps = []selfCheckStep{
+	selfCheckStep{Name: "Credentials", Check: selfCheckCredentials},
+	selfCheckStep{Name: "Create Bucket", Check: func(config ServerConfig) error {
+		return putBucket(config, selfCheckBucket)
+	}},
+	selfCheckStep{Name: "Put Object", Check: func(config ServerConfig) error {
+		_, err := putObject(config, selfCheckBucket, selfCheckObject)
+		return err
+	}},
+	selfCheckStep{Name: "Get Object", Check: selfCheckGetObject},
+	selfCheckStep{Name: "Remove Object", Check: func(config ServerConfig) error {
+		return removeObject(config, selfCheckBucket, selfCheckObject.Key)
+	}},
+	selfCheckStep{Name: "Remove Bucket", Check: func(config ServerConfig) error {
+		return removeBucket(config, selfCheckBucket)
+	}},
+}
+
+// selfCheckCredentials - verify the credentials are accepted by listing all buckets.
+func selfCheckCredentials(config ServerConfig) error {
+	req, err := newListBucketsReq()
+	if err != nil {
+		return err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	if res.StatusCode != http.StatusOK {
+		errResponse := ErrorResponse{}
+		if err := xmlDecoder(res.Body, &errResponse); err == nil && errResponse.Code != "" {
+			err := fmt.Errorf("Credentials Rejected: %v: %v", errResponse.Code, errResponse.Message)
+			return err
+		}
+	}
+	return verifyStatusListBuckets(res.StatusCode, http.StatusOK)
+}
+
+// selfCheckGetObject - verify the self-check object can be read back unchanged.
+func selfCheckGetObject(config ServerConfig) error {
+	req, err := newGetObjectReq(selfCheckBucket, selfCheckObject.Key, nil)
+	if err != nil {
+		return err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	return getObjectVerify(res, selfCheckObject.Body, http.StatusOK, nil)
+}
+
+// mainSelfCheck - validate the configuration with a minimal round trip instead of running the suite.
+// Returns false if any step failed, later steps are skipped once one fails.
+func mainSelfCheck(config ServerConfig) bool {
+	selfCheckBucket = "s3verify-" + globalSuffix + "-selfcheck"
+	globalTotalNumTest = len(selfCheckSteps)
+	passed := false
+	// A failed step skips the removal steps, do not leave the bucket and object behind.
+	defer func() {
+		if !passed {
+			removeBucketWithContents(config, selfCheckBucket)
+		}
+	}()
+	for i, step := range selfCheckSteps {
+		message := fmt.Sprintf("[%02d/%d] Self-Check (%s):", i+1, globalTotalNumTest, step.Name)
+		// Spin scanBar
+		scanBar(message)
+		if err := step.Check(config); err != nil {
+			printMessage(message, err)
+			return false
+		}
+		printMessage(message, nil)
+	}
+	passed = true
+	return true
+}
@@ -0,0 +1,75 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Test that a self-check failing after the bucket was created still removes the bucket.
+func TestSelfCheckCleanup(t *testing.T) {
+	var mutex sync.Mutex
+	bucketRemoved := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		isBucket := strings.Count(strings.Trim(r.URL.Path, "/"), "/") == 0
+		query := r.URL.Query()
+		_, versions := query["versions"]
+		_, uploads := query["uploads"]
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/":
+			writeS3Headers(w)
+			w.Write([]byte(`<ListAllMyBucketsResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></ListAllMyBucketsResult>`))
+		case r.Method == "PUT" && isBucket:
+			writeS3Headers(w)
+			w.Header().Set("Location", strings.TrimSuffix(r.URL.Path, "/"))
+		case r.Method == "PUT":
+			// The object never makes it, failing the Put Object step.
+			writeS3Error(w, http.StatusForbidden, "AccessDenied", "")
+		case r.Method == "GET" && versions:
+			writeS3Error(w, http.StatusNotImplemented, "NotImplemented", "")
+		case r.Method == "GET" && uploads:
+			writeS3Headers(w)
+			w.Write([]byte(`<ListMultipartUploadsResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></ListMultipartUploadsResult>`))
+		case r.Method == "GET" && isBucket:
+			writeS3Headers(w)
+			w.Write([]byte(`<ListBucketResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><IsTruncated>false</IsTruncated></ListBucketResult>`))
+		case r.Method == "DELETE" && isBucket:
+			bucketRemoved = true
+			writeS3Headers(w)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeS3Error(w, http.StatusBadRequest, "InvalidRequest", "")
+		}
+	}))
+	defer server.Close()
+
+	config := newTestConfig(server.URL)
+	if mainSelfCheck(config) {
+		t.Fatal("Expected the self-check to fail")
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if !bucketRemoved {
+		t.Error("Expected the self-check bucket to be removed after the failure")
+	}
+}
@@ -17,7 +17,7 @@
 package main
 
 import (
-	"net"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -33,29 +33,217 @@ type ServerConfig struct {
 	Endpoint string
 	Region   string
 	Client   *http.Client
+
+	// EndpointResolver - if set, chooses the endpoint of every request instead of Endpoint.
+	EndpointResolver EndpointResolver
+
+	// SigningRegion - if set, the region requests are signed for instead of Region.
+	// Region is still the location buckets are created in and the endpoint is chosen by.
+	SigningRegion string
+
+	// SignatureVersion - signatureV2 to sign requests with AWS Signature Version 2 for servers
+	// that do not support Version 4. Presigned URLs are always Version 4.
+	SignatureVersion string
+
+	// OutputFormat - how test results are reported: outputHuman, outputJSON or outputJUnit.
+	OutputFormat string
+
+	// MaxRetries - how often a request failing with a connection error or a retryable status
+	// is sent again, 0 disables retries. POST requests other than DeleteObjects are never retried.
+	MaxRetries int
+
+	// RetryDelay - the wait before the first retry, doubled for every further retry up to 30s.
+	// A Retry-After header sent by the server takes precedence.
+	RetryDelay time.Duration
+
+	// Workers - the number of concurrent uploads tests that upload many objects may use.
+	// Zero or less means defaultWorkers.
+	Workers int
+
+	// ObjectCount - the number of objects PutObject uploads for the listing tests.
+	// Zero or less means defaultObjectCount. Over 1000 makes listings cross a page boundary.
+	ObjectCount int
+
+	// ObjectSize - the size in bytes of each object PutObject uploads.
+	// Zero or less means defaultObjectSize.
+	ObjectSize int
+
+	// InsecureSkipVerify - accept any certificate the server presents. Takes precedence over CABundle.
+	InsecureSkipVerify bool
+
+	// CABundle - path of a PEM file with the CA certificates to verify the server with instead
+	// of the system ones.
+	CABundle string
+
+	// Socks5 - host:port of a SOCKS5 proxy all connections are tunneled through, none if empty.
+	Socks5 string
+
+	// ThrottleBandwidth - the bytes per second all connections together may transfer in each
+	// direction, to simulate a slow link. Zero or less means no limit.
+	ThrottleBandwidth int64
+
+	// ExpectContinue - send Expect: 100-continue with every request that has a body, so a request
+	// the server rejects is answered before the body is sent. Off by default as some gateways
+	// mishandle it.
+	ExpectContinue bool
+
+	// RequestTimeout - how long a request may take, from sending it until its response body is
+	// read, before it is canceled. Zero or less means defaultRequestTimeout.
+	RequestTimeout time.Duration
+
+	// DialTimeout - how long opening a connection may take. Zero or less means defaultDialTimeout.
+	DialTimeout time.Duration
+
+	// MaxIdleConnsPerHost - the number of idle connections kept open to be reused by later requests.
+	// Zero or less means defaultMaxIdleConnsPerHost.
+	MaxIdleConnsPerHost int
+
+	// DryRun - build and sign every request and print it instead of sending it. Every request is
+	// answered with a synthetic success.
+	DryRun bool
+
+	// CredentialProcess - if set, runs a command for the credentials to sign requests with
+	// instead of using Access and Secret.
+	CredentialProcess *credentialProcess
+}
+
+// The signature versions requests can be signed with.
+const (
+	signatureV2 = "v2"
+	signatureV4 = "v4"
+)
+
+// isSignatureV2 - report whether requests are signed with Signature Version 2.
+func (c ServerConfig) isSignatureV2() bool {
+	return c.SignatureVersion == signatureV2
+}
+
+// defaultWorkers - the number of concurrent uploads used when Workers is not set.
+const defaultWorkers = 8
+
+// workers - the number of concurrent uploads to use.
+func (c ServerConfig) workers() int {
+	if c.Workers < 1 {
+		return defaultWorkers
+	}
+	return c.Workers
+}
+
+// The number and size of the objects uploaded for the listing tests when not configured.
+const (
+	defaultObjectCount = 101
+	defaultObjectSize  = 60
+)
+
+// minObjectCount - the fewest objects the listing tests need, they start a listing after the 31st key.
+const minObjectCount = 32
+
+// objectCount - the number of objects to upload for the listing tests.
+func (c ServerConfig) objectCount() int {
+	if c.ObjectCount < 1 {
+		return defaultObjectCount
+	}
+	return c.ObjectCount
+}
+
+// objectSize - the size of each object uploaded for the listing tests.
+func (c ServerConfig) objectSize() int {
+	if c.ObjectSize < 1 {
+		return defaultObjectSize
+	}
+	return c.ObjectSize
+}
+
+// signingRegion - the region in the credential scope of every signature.
+func (c ServerConfig) signingRegion() string {
+	if c.SigningRegion != "" {
+		return c.SigningRegion
+	}
+	if region := globalLearnedRegion.get(); region != "" {
+		return region
+	}
+	return c.Region
+}
+
+// newTransport - the HTTP transport connecting to the server as configured. Anything else connecting
+// to the server on its own uses its DialContext and TLSClientConfig so it goes the same way.
+func (c ServerConfig) newTransport() (*http.Transport, error) {
+	// Route connections through a SOCKS5 proxy if one was given.
+	dialContext, err := newDialContext(c.Socks5, c.dialTimeout())
+	if err != nil {
+		return nil, err
+	}
+	// Slow down every connection if a slow link is to be simulated.
+	dialContext = newThrottledDialContext(dialContext, c.ThrottleBandwidth)
+	// Trust the certificates of internal gateways if asked to.
+	tlsConfig, err := newTLSConfig(c.InsecureSkipVerify, c.CABundle)
+	if err != nil {
+		return nil, err
+	}
+	// Keep connections open so the many sequential requests reuse them rather than reconnecting.
+	// Responses are never decompressed behind the tests' back, a content coding the server applies
+	// is left for them to see.
+	return &http.Transport{
+		DialContext:           dialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ExpectContinueTimeout: expectContinueTimeout,
+		MaxIdleConns:          c.maxIdleConnsPerHost(),
+		MaxIdleConnsPerHost:   c.maxIdleConnsPerHost(),
+		IdleConnTimeout:       90 * time.Second,
+		DisableCompression:    true,
+	}, nil
 }
 
 // newServerConfig - new server config.
-func newServerConfig(ctx *cli.Context) *ServerConfig {
+func newServerConfig(ctx *cli.Context) (*ServerConfig, error) {
 	// Set config fields from either flags or env. variables.
 	serverCfg := &ServerConfig{
-		Access:   ctx.String("access"),
-		Secret:   ctx.String("secret"),
-		Endpoint: ctx.String("url"),
-		Region:   ctx.String("region"),
-		Client: &http.Client{
-			Transport: &http.Transport{
-				Dial: (&net.Dialer{
-					Timeout: 5 * time.Second,
-				}).Dial,
-				TLSHandshakeTimeout: 5 * time.Second,
-			},
-		},
-	}
-	if ctx.Bool("verbose") || ctx.GlobalBool("verbose") {
-
+		Access:              ctx.String("access"),
+		Secret:              ctx.String("secret"),
+		Endpoint:            ctx.String("url"),
+		Region:              ctx.String("region"),
+		SigningRegion:       ctx.GlobalString("signing-region"),
+		SignatureVersion:    ctx.GlobalString("signature-version"),
+		OutputFormat:        ctx.GlobalString("format"),
+		MaxRetries:          ctx.GlobalInt("max-retries"),
+		RetryDelay:          ctx.GlobalDuration("retry-delay"),
+		Workers:             ctx.GlobalInt("workers"),
+		ObjectCount:         ctx.GlobalInt("object-count"),
+		ObjectSize:          ctx.GlobalInt("object-size"),
+		InsecureSkipVerify:  ctx.GlobalBool("insecure"),
+		CABundle:            ctx.GlobalString("ca-bundle"),
+		Socks5:              ctx.GlobalString("socks5"),
+		ThrottleBandwidth:   ctx.GlobalInt64("throttle-bandwidth"),
+		ExpectContinue:      ctx.GlobalBool("expect-continue"),
+		CredentialProcess:   newCredentialProcess(ctx.GlobalString("credential-process")),
+		RequestTimeout:      ctx.GlobalDuration("request-timeout"),
+		DialTimeout:         ctx.GlobalDuration("dial-timeout"),
+		MaxIdleConnsPerHost: ctx.GlobalInt("max-idle-conns"),
+		DryRun:              ctx.GlobalBool("dry-run"),
+	}
+	transport, err := serverCfg.newTransport()
+	if err != nil {
+		return nil, err
+	}
+	serverCfg.Client = &http.Client{
+		Transport: transport,
+	}
+	if serverCfg.objectCount() < minObjectCount {
+		err := fmt.Errorf("Invalid Object Count: wanted at least %d, got %d", minObjectCount, serverCfg.objectCount())
+		return nil, err
+	}
+	if serverCfg.SignatureVersion != signatureV2 && serverCfg.SignatureVersion != signatureV4 {
+		err := fmt.Errorf("Invalid Signature Version: wanted %v or %v, got %q", signatureV2, signatureV4, serverCfg.SignatureVersion)
+		return nil, err
+	}
+	// Print requests instead of sending them if asked to.
+	if serverCfg.DryRun {
+		serverCfg.Client.Transport = dryRunTransport{}
+	}
+	if globalVerbosity >= verbosityDebug {
 		// Set up new tracer.
-		serverCfg.Client.Transport = httptracer.GetNewTraceTransport(newTraceV4(), http.DefaultTransport)
+		serverCfg.Client.Transport = httptracer.GetNewTraceTransport(newTraceV4(), serverCfg.Client.Transport)
 	}
-	return serverCfg
+	return serverCfg, nil
 }
@@ -0,0 +1,102 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// credentialScopeRegion - the region in the credential scope <access>/<date>/<region>/s3/aws4_request.
+func credentialScopeRegion(credential string) string {
+	scope := strings.Split(credential, "/")
+	if len(scope) != 5 {
+		return ""
+	}
+	return scope[2]
+}
+
+// signedRegion - the region a signed or presigned request was signed for.
+func signedRegion(req *http.Request) string {
+	if credential := req.URL.Query().Get("X-Amz-Credential"); credential != "" {
+		return credentialScopeRegion(credential)
+	}
+	const field = "Credential="
+	auth := req.Header.Get("Authorization")
+	start := strings.Index(auth, field)
+	if start < 0 {
+		return ""
+	}
+	credential := auth[start+len(field):]
+	if end := strings.Index(credential, ","); end >= 0 {
+		credential = credential[:end]
+	}
+	return credentialScopeRegion(credential)
+}
+
+// mainSigningRegion - verify a SigningRegion override, and only the override, is used in the
+// credential scope of both signed and presigned requests.
+func mainSigningRegion(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Signing Region:", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	if config.isSignatureV2() {
+		printMessage(message, nil)
+		printDetail("Skipped: Signature Version 2 has no credential scope to carry a region")
+		return true
+	}
+	bucketName := s3verifyBuckets[0].Name
+	// The requests are only inspected so any region will do, pick one no server is in.
+	config.SigningRegion = "s3verify-signing-region"
+	req, err := newHeadBucketReq(bucketName)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	presignedReq := req
+	presignedReq.presignURL = true
+	presignedReq.expires = 60
+	for _, r := range []Request{req, presignedReq} {
+		// Spin scanBar
+		scanBar(message)
+		httpReq, err := config.newRequest("HEAD", r)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		if region := signedRegion(httpReq); region != config.SigningRegion {
+			err := fmt.Errorf("Unexpected Credential Scope Sent: wanted region %v, got %q (presigned %v)", config.SigningRegion, region, r.presignURL)
+			printMessage(message, err)
+			return false
+		}
+		// Where the request is sent is still decided by Region.
+		expectedURL, err := makeTargetURL(config.Endpoint, bucketName, "", config.Region, nil)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		if httpReq.URL.Host != expectedURL.Host {
+			err := fmt.Errorf("Unexpected Request Host: wanted %v for region %v, got %v", expectedURL.Host, config.Region, httpReq.URL.Host)
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
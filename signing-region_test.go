@@ -0,0 +1,97 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test that the region in the credential scope of signed and presigned requests is the --signing-region
+// override if there is one, otherwise a region learned from the server, otherwise --region.
+func TestSigningRegion(t *testing.T) {
+	testCases := []struct {
+		signingRegion string
+		learnedRegion string
+		region        string
+		expected      string
+	}{
+		{"", "", "us-east-1", "us-east-1"},
+		{"", "eu-west-1", "us-east-1", "eu-west-1"},
+		{"s3verify-signing-region", "", "us-east-1", "s3verify-signing-region"},
+		{"s3verify-signing-region", "eu-west-1", "us-east-1", "s3verify-signing-region"},
+	}
+	for i, testCase := range testCases {
+		config := newTestConfig("http://127.0.0.1")
+		config.SigningRegion = testCase.signingRegion
+		config.Region = testCase.region
+		globalLearnedRegion.set(testCase.learnedRegion)
+		if region := config.signingRegion(); region != testCase.expected {
+			t.Errorf("Test %d: Expected signing region %q, got %q", i+1, testCase.expected, region)
+		}
+		req, err := newHeadBucketReq("s3verify-test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		presignedReq := req
+		presignedReq.presignURL = true
+		presignedReq.expires = 60
+		for _, r := range []Request{req, presignedReq} {
+			httpReq, err := config.newRequest("HEAD", r)
+			if err != nil {
+				t.Fatalf("Test %d: %v", i+1, err)
+			}
+			if region := signedRegion(httpReq); region != testCase.expected {
+				t.Errorf("Test %d: Expected the credential scope of region %q (presigned %v), got %q", i+1, testCase.expected, r.presignURL, region)
+			}
+		}
+	}
+}
+
+// Test that a --signing-region the server rejects is reported rather than silently replaced by the
+// region the server names.
+func TestSigningRegionNotReplaced(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		writeS3Error(w, http.StatusBadRequest, "AuthorizationHeaderMalformed", "<Region>eu-west-1</Region>")
+	}))
+	defer server.Close()
+
+	config := newTestConfig(server.URL)
+	config.SigningRegion = "s3verify-signing-region"
+	req, err := newHeadBucketReq("s3verify-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Sent as a GET, the response to a HEAD has no body to name the region in.
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer drainAndClose(res)
+	if res.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected the AuthorizationHeaderMalformed response, got status %d", res.StatusCode)
+	}
+	if requests != 1 {
+		t.Errorf("Expected a single request, got %d", requests)
+	}
+	if region := globalLearnedRegion.get(); region != "" {
+		t.Errorf("Expected no region to be learned, got %q", region)
+	}
+}
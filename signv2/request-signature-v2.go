@@ -0,0 +1,157 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package signv2
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Signature and API related constants.
+const (
+	signV2Algorithm = "AWS"
+)
+
+// resourceList - the sub-resources that are part of the canonicalized resource, in sorted order.
+var resourceList = []string{
+	"acl",
+	"cors",
+	"delete",
+	"encryption",
+	"lifecycle",
+	"location",
+	"logging",
+	"notification",
+	"partNumber",
+	"policy",
+	"requestPayment",
+	"response-cache-control",
+	"response-content-disposition",
+	"response-content-encoding",
+	"response-content-language",
+	"response-content-type",
+	"response-expires",
+	"tagging",
+	"torrent",
+	"uploadId",
+	"uploads",
+	"versionId",
+	"versioning",
+	"versions",
+	"website",
+}
+
+// getCanonicalizedAmzHeaders - the x-amz- headers of the request lowercased, sorted and
+// with the values of repeated headers joined by commas, one per line.
+func getCanonicalizedAmzHeaders(req http.Request) string {
+	var keys []string
+	vals := make(map[string][]string)
+	for k, vv := range req.Header {
+		lk := strings.ToLower(k)
+		if !strings.HasPrefix(lk, "x-amz-") {
+			continue
+		}
+		if _, ok := vals[lk]; !ok {
+			keys = append(keys, lk)
+		}
+		for _, v := range vv {
+			vals[lk] = append(vals[lk], strings.TrimSpace(v))
+		}
+	}
+	sort.Strings(keys)
+	var buf []string
+	for _, k := range keys {
+		buf = append(buf, k+":"+strings.Join(vals[k], ",")+"\n")
+	}
+	return strings.Join(buf, "")
+}
+
+// getCanonicalizedResource - the escaped path of the request followed by its sub-resources.
+// Only path style requests are sent so the bucket is always part of the path.
+func getCanonicalizedResource(req http.Request) string {
+	resource := req.URL.EscapedPath()
+	query := req.URL.Query()
+	var subResources []string
+	for _, name := range resourceList {
+		values, ok := query[name]
+		if !ok {
+			continue
+		}
+		if len(values) == 0 || values[0] == "" {
+			subResources = append(subResources, name)
+			continue
+		}
+		subResources = append(subResources, name+"="+values[0])
+	}
+	if len(subResources) > 0 {
+		resource += "?" + strings.Join(subResources, "&")
+	}
+	return resource
+}
+
+// getStringToSignV2 - the string to sign for the request.
+//
+//	StringToSign = HTTP-Verb + "\n" +
+//		Content-MD5 + "\n" +
+//		Content-Type + "\n" +
+//		Date + "\n" +
+//		CanonicalizedAmzHeaders +
+//		CanonicalizedResource;
+func getStringToSignV2(req http.Request) string {
+	return strings.Join([]string{
+		req.Method,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		req.Header.Get("Date"),
+		getCanonicalizedAmzHeaders(req) + getCanonicalizedResource(req),
+	}, "\n")
+}
+
+// getSignatureV2 - the base64 encoded HMAC-SHA1 of stringToSign.
+func getSignatureV2(secretAccessKey, stringToSign string) string {
+	hm := hmac.New(sha1.New, []byte(secretAccessKey))
+	hm.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(hm.Sum(nil))
+}
+
+// SignV2 sign the request before Do(), in accordance with
+// http://docs.aws.amazon.com/AmazonS3/latest/dev/RESTAuthentication.html.
+// A Date header already set on the request is kept, which allows signing known requests.
+func SignV2(req http.Request, accessKeyID, secretAccessKey string) *http.Request {
+	// Signature calculation is not needed for anonymous credentials.
+	if accessKeyID == "" || secretAccessKey == "" {
+		return &req
+	}
+
+	// Set the date if not already set.
+	if req.Header.Get("Date") == "" {
+		req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	}
+
+	// Calculate signature.
+	signature := getSignatureV2(secretAccessKey, getStringToSignV2(req))
+
+	// Set authorization header.
+	req.Header.Set("Authorization", signV2Algorithm+" "+accessKeyID+":"+signature)
+
+	return &req
+}
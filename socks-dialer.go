@@ -0,0 +1,53 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// dialContextFunc - the signature of http.Transport.DialContext.
+type dialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// newDialContext - create the dial function used by the HTTP transport. When socksAddr is set all
+// connections are tunneled through the SOCKS5 proxy listening there, e.g. `ssh -D` to a bastion host.
+//
+// Only the TCP connection is proxied: the proxy is asked to connect to the endpoint's own host and
+// port, so TLS server name verification and SigV4 signing still use the real endpoint host.
+func newDialContext(socksAddr string, timeout time.Duration) (dialContextFunc, error) {
+	direct := &net.Dialer{
+		Timeout: timeout,
+	}
+	if socksAddr == "" {
+		return direct.DialContext, nil
+	}
+	dialer, err := proxy.SOCKS5("tcp", socksAddr, nil, direct)
+	if err != nil {
+		return nil, err
+	}
+	if contextDialer, ok := dialer.(proxy.ContextDialer); ok {
+		return contextDialer.DialContext, nil
+	}
+	// Older proxy dialers do not support contexts.
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return dialer.Dial(network, addr)
+	}, nil
+}
@@ -0,0 +1,152 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// socksServer - a minimal SOCKS5 proxy without authentication that records every CONNECT it is asked for.
+type socksServer struct {
+	listener net.Listener
+	connects chan string
+}
+
+// newSOCKSServer - start a SOCKS5 proxy on a local port.
+func newSOCKSServer(t *testing.T) *socksServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &socksServer{listener: listener, connects: make(chan string, 10)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+// serve - answer the greeting, connect to the requested address and relay until either side closes.
+func (s *socksServer) serve(conn net.Conn) {
+	defer conn.Close()
+	// Greeting: version, number of methods, methods. Only "no authentication" is offered back.
+	greeting := make([]byte, 2)
+	if _, err := io.ReadFull(conn, greeting); err != nil {
+		return
+	}
+	if _, err := io.ReadFull(conn, make([]byte, greeting[1])); err != nil {
+		return
+	}
+	if _, err := conn.Write([]byte{5, 0}); err != nil {
+		return
+	}
+	// Request: version, command, reserved, address type, address, port.
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil || request[1] != 1 {
+		return
+	}
+	var host string
+	switch request[3] {
+	case 1:
+		ip := make([]byte, net.IPv4len)
+		if _, err := io.ReadFull(conn, ip); err != nil {
+			return
+		}
+		host = net.IP(ip).String()
+	case 3:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return
+		}
+		name := make([]byte, length[0])
+		if _, err := io.ReadFull(conn, name); err != nil {
+			return
+		}
+		host = string(name)
+	default:
+		return
+	}
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port))))
+	target, err := net.Dial("tcp", addr)
+	if err != nil {
+		conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer target.Close()
+	s.connects <- addr
+	if _, err := conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}); err != nil {
+		return
+	}
+	go io.Copy(target, conn)
+	io.Copy(conn, target)
+}
+
+// Test that the transport built for --socks5 sends its connections through the proxy, asking it for the endpoint itself.
+func TestTransportSOCKS5(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("s3verify"))
+	}))
+	defer server.Close()
+	proxy := newSOCKSServer(t)
+	defer proxy.listener.Close()
+
+	config := newTestConfig(server.URL)
+	config.Socks5 = proxy.listener.Addr().String()
+	transport, err := config.newTransport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer transport.CloseIdleConnections()
+	client := &http.Client{Transport: transport}
+	res, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "s3verify" {
+		t.Errorf("Expected the response to come back through the proxy, got %q", body)
+	}
+	select {
+	case addr := <-proxy.connects:
+		if expected := strings.TrimPrefix(server.URL, "http://"); addr != expected {
+			t.Errorf("Expected the proxy to connect to %s, got %s", expected, addr)
+		}
+	default:
+		t.Error("Expected the connection to go through the proxy")
+	}
+}
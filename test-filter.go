@@ -0,0 +1,74 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// testFilter - selects the tests to run by matching their names against --run and --skip.
+type testFilter struct {
+	run  *regexp.Regexp // Only tests matching this are run, all tests if nil.
+	skip *regexp.Regexp // Tests matching this are not run, none are skipped if nil.
+}
+
+// newTestFilter - create a filter from the --run and --skip patterns, nil if both are empty.
+func newTestFilter(run, skip string) (*testFilter, error) {
+	if run == "" && skip == "" {
+		return nil, nil
+	}
+	filter := &testFilter{}
+	var err error
+	if run != "" {
+		if filter.run, err = regexp.Compile(run); err != nil {
+			return nil, err
+		}
+	}
+	if skip != "" {
+		if filter.skip, err = regexp.Compile(skip); err != nil {
+			return nil, err
+		}
+	}
+	return filter, nil
+}
+
+// testName - the name of a test as matched by the filter, its function name without the main prefix,
+// e.g. PutObjectPrepared for mainPutObjectPrepared.
+func testName(test APItest) string {
+	name := runtime.FuncForPC(reflect.ValueOf(test.Test).Pointer()).Name()
+	name = name[strings.LastIndex(name, ".")+1:]
+	return strings.TrimPrefix(name, "main")
+}
+
+// selects - report whether test is to be run. Critical tests always are, they create the buckets and
+// objects the other tests use. Safe to call on a nil filter, which selects every test.
+func (f *testFilter) selects(test APItest) bool {
+	if f == nil || test.Critical {
+		return true
+	}
+	name := testName(test)
+	if f.run != nil && !f.run.MatchString(name) {
+		return false
+	}
+	if f.skip != nil && f.skip.MatchString(name) {
+		return false
+	}
+	return true
+}
@@ -32,24 +32,31 @@ package main
 // Tests are sorted into the following lists:
 // preparedTests    -- tests that will use materials set up by the --prepare flag.
 // unpreparedTests  -- tests that will be self-sufficient and create their own testing environment.
+// sharedTests      -- tests that are part of both lists above, run after their setup and before their removals.
+// readOnlyTests    -- tests that only read objects that already exist, used by the --read-only flag.
 
-// Tests - holds all tests that must be run differently based on usage of the -- flag.
-var preparedTests = []APItest{
+// preparedSetupTests - the tests using materials set up by the --prepare flag, run before sharedTests.
+var preparedSetupTests = []APItest{
 	// Tests for PutBucket API.
 	APItest{
 		Test:     mainPutBucket,
+		Area:     areaBucket,
 		Extended: false, // PutBucket is not an extended API.
 		Critical: false, // Because -- has been used this bucket is not necessary for future tests.
+		Mutating: true,  // Creates buckets.
 	},
 	APItest{
 		Test:     mainPutBucketInvalid,
+		Area:     areaBucket,
 		Extended: false, // PutBucket is not an extended API.
 		Critical: false, // This test is not used for future tests.
+		Mutating: true,  // Attempts to create buckets.
 	},
 
 	// Tests for GetBucketPolicy API.
 	APItest{
 		Test:     mainGetBucketPolicy,
+		Area:     areaBucket,
 		Extended: false, // GetBucketPolicy is not an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
@@ -57,18 +64,23 @@ var preparedTests = []APItest{
 	// Tests for PutObject API.
 	APItest{
 		Test:     mainPutObjectPrepared,
+		Area:     areaObject,
 		Extended: false, // PutObject is not an extended API.
 		Critical: false, // Because -- has been used this object is not necessary for future tests.
+		Mutating: true,  // Uploads objects.
 	},
 	APItest{
 		Test:     mainPresignedPutObject,
+		Area:     areaObject,
 		Extended: false, // PutObject presigned is not an extended API.
 		Critical: false, // This object is not needed for future tests.
+		Mutating: true,  // Uploads objects.
 	},
 
 	// Tests for HeadBucket API.
 	APItest{
 		Test:     mainHeadBucket,
+		Area:     areaBucket,
 		Extended: false, // HeadBucket is not an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
@@ -76,26 +88,31 @@ var preparedTests = []APItest{
 	// Tests for HeadObject API.
 	APItest{
 		Test:     mainHeadObject,
+		Area:     areaObject,
 		Extended: false, // HeadObject is not an extended API.
 		Critical: true,  // This test affects future tests and must pass.
 	},
 	APItest{
 		Test:     mainHeadObjectIfModifiedSince,
+		Area:     areaObject,
 		Extended: true,  // HeadObject with if-modified-since header is an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
 	APItest{
 		Test:     mainHeadObjectIfUnModifiedSince,
+		Area:     areaObject,
 		Extended: true,  // HeadObject with if-unmodified-since header is an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
 	APItest{
 		Test:     mainHeadObjectIfMatch,
+		Area:     areaObject,
 		Extended: true,  // HeadObject with if-match header is an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
 	APItest{
 		Test:     mainHeadObjectIfNoneMatch,
+		Area:     areaObject,
 		Extended: true,  // HeadObject with if-none-match header is an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
@@ -103,6 +120,7 @@ var preparedTests = []APItest{
 	// Tests for ListBuckets API.
 	APItest{
 		Test:     mainListBuckets,
+		Area:     areaBucket,
 		Extended: false, // ListBuckets is not an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
@@ -110,149 +128,210 @@ var preparedTests = []APItest{
 	// Tests for ListObjects API.
 	APItest{
 		Test:     mainListObjectsV1Prepared,
+		Area:     areaListing,
 		Extended: false, // ListObjects is not an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
 	APItest{
 		Test:     mainListObjectsV2Prepared,
+		Area:     areaListing,
 		Extended: false, // ListObjects is not an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
+	APItest{
+		Test:     mainListObjectsV2StartAfterPrepared,
+		Area:     areaListing,
+		Extended: true,  // ListObjects V2 with start-after is an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainListObjectsPaginationPrepared,
+		Area:     areaListing,
+		Extended: false, // ListObjects is not an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainListObjectsV2PaginationPrepared,
+		Area:     areaListing,
+		Extended: false, // ListObjects V2 is not an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
 
 	// Tests for Multipart API.
 	APItest{
 		Test:     mainInitiateMultipartUpload,
+		Area:     areaMultipart,
 		Extended: false, // Initiate Multipart test must be run even without extended flags being set.
 		Critical: true,  // Initiate Multipart test must pass before other tests can be run.
+		Mutating: true,  // Starts multipart uploads.
 	},
 	APItest{
 		Test:     mainUploadPart,
+		Area:     areaMultipart,
 		Extended: false, // Upload Part test must be run even without extended flag being set.
 		Critical: true,  // Upload Part test must pass before other tests can be run.
+		Mutating: true,  // Uploads parts.
 	},
 	APItest{
 		Test:     mainListParts,
+		Area:     areaMultipart,
 		Extended: false, // List Part test must be run even without extended flag being set.
 		Critical: false, // List Part test can fail without affecting other tests.
 	},
 	APItest{
 		Test:     mainListMultipartUploads,
+		Area:     areaMultipart,
 		Extended: false, // List Multipart Uploads test must be run without extended flag being set.
 		Critical: false, // List Multipart Uploads test can fail without affecting other tests.
 	},
 	APItest{
 		Test:     mainCompleteMultipartUpload,
+		Area:     areaMultipart,
 		Extended: false, // Complete Multipart test must be run even without extended flag being set.
 		Critical: true,  // Complete Multipart test can fail without affecting other tests.
+		Mutating: true,  // Completes multipart uploads.
 	},
 	APItest{
 		Test:     mainAbortMultipartUpload,
+		Area:     areaMultipart,
 		Extended: false, // Abort Multipart test must be run even without extended flag being set.
 		Critical: false, // Abort Multipart test can fail without affecting other tests.
+		Mutating: true,  // Aborts multipart uploads.
 	},
 
 	// Tests for CopyObject API.
 	APItest{
 		Test:     mainCopyObject,
+		Area:     areaObject,
 		Extended: false, // CopyObject is not an extended API.
 		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Copies objects.
 	},
 	APItest{
 		Test:     mainCopyObjectIfModifiedSince,
+		Area:     areaObject,
 		Extended: true,  // CopyObject with if-modified-since header is an extended API.
 		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Copies objects.
 	},
 	APItest{
 		Test:     mainCopyObjectIfUnModifiedSince,
+		Area:     areaObject,
 		Extended: true,  // CopyObject with if-unmodified-since header is an extended API.
 		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Copies objects.
 	},
 	APItest{
 		Test:     mainCopyObjectIfMatch,
+		Area:     areaObject,
 		Extended: true,  // CopyObject with if-match header is an extended API.
 		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Copies objects.
 	},
 	APItest{
 		Test:     mainCopyObjectIfNoneMatch,
+		Area:     areaObject,
 		Extended: true,  // CopyObject with if-none-match header is an extended API.
 		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Copies objects.
 	},
 
 	// Tests for GetObject API.
 	APItest{
 		Test:     mainGetObject,
+		Area:     areaObject,
 		Extended: false, // GetObject is not an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
 	APItest{
 		Test:     mainGetObjectPresigned,
+		Area:     areaObject,
 		Extended: false, // GetObject Presigned is not an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
 
 	APItest{
 		Test:     mainGetObjectIfModifiedSince,
+		Area:     areaObject,
 		Extended: true,  // GetObject with if-modified-since header is an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
 	APItest{
 		Test:     mainGetObjectIfUnModifiedSince,
+		Area:     areaObject,
 		Extended: true,  // GetObject with if-unmodified-since header is an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
 	APItest{
 		Test:     mainGetObjectIfMatch,
+		Area:     areaObject,
 		Extended: true,  // GetObject with if-match header is an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
 	APItest{
 		Test:     mainGetObjectIfNoneMatch,
+		Area:     areaObject,
 		Extended: true,  // GetObject with if-none-match header is an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
 	APItest{
 		Test:     mainGetObjectRange,
+		Area:     areaObject,
 		Extended: true,  // GetObject with range header is an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
+}
 
+// preparedRemoveTests - the tests removing the materials set up by the --prepare flag, run after sharedTests.
+var preparedRemoveTests = []APItest{
 	// Test for RemoveObject API.
 	APItest{
 		Test:     mainRemoveObjectExists,
+		Area:     areaObject,
 		Extended: false, // RemoveObject is not an extended API.
 		Critical: true,  // This test does affect future tests.
+		Mutating: true,  // Removes objects.
 	},
 
 	// Tests for RemoveBucket API.
 	APItest{
 		Test:     mainRemoveBucketExists,
+		Area:     areaBucket,
 		Extended: false, // RemoveBucket is not an extended API.
 		Critical: true,  // Removing this bucket is necessary for a good test.
+		Mutating: true,  // Removes buckets.
 	},
 	APItest{
 		Test:     mainRemoveBucketDNE,
+		Area:     areaBucket,
 		Extended: false, // RemoveBucket is not an extended API.
 		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Attempts to remove a bucket.
 	},
 }
 
-// Tests - holds all tests that must be run differently based on usage of the -- flag.
-var unpreparedTests = []APItest{
+// unpreparedSetupTests - the tests creating their own testing environment, run before sharedTests.
+var unpreparedSetupTests = []APItest{
 	// Tests for PutBucket API.
 	APItest{
 		Test:     mainPutBucket,
+		Area:     areaBucket,
 		Extended: false, // PutBucket is not an extended API.
 		Critical: true,  // This test does affect future tests.
+		Mutating: true,  // Creates buckets.
 	},
 	APItest{
 		Test:     mainPutBucketInvalid,
+		Area:     areaBucket,
 		Extended: false, // PutBucket is not an extended API.
 		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Attempts to create buckets.
 	},
 
 	// Tests for GetBucketPolicy API.
 	APItest{
 		Test:     mainGetBucketPolicy,
+		Area:     areaBucket,
 		Extended: false, // GetBucketPolicy is not an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
@@ -260,18 +339,23 @@ var unpreparedTests = []APItest{
 	// Tests for PutObject API.
 	APItest{
 		Test:     mainPutObjectUnPrepared,
+		Area:     areaObject,
 		Extended: false, // PutObject is not an extended API.
 		Critical: true,  // These objects are necessary for future tests.
+		Mutating: true,  // Uploads objects.
 	},
 	APItest{
 		Test:     mainPresignedPutObject,
+		Area:     areaObject,
 		Extended: false, // PutObject presigned is not an extended API.
 		Critical: true,  // This object is necessary for future tests.
+		Mutating: true,  // Uploads objects.
 	},
 
 	// Tests for HeadBucket API.
 	APItest{
 		Test:     mainHeadBucket,
+		Area:     areaBucket,
 		Extended: false, // HeadBucket is not an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
@@ -279,26 +363,31 @@ var unpreparedTests = []APItest{
 	// Tests for HeadObject API.
 	APItest{
 		Test:     mainHeadObject,
+		Area:     areaObject,
 		Extended: false, // HeadObject is not an extended API.
 		Critical: true,  // This test affects future tests and must pass.
 	},
 	APItest{
 		Test:     mainHeadObjectIfModifiedSince,
+		Area:     areaObject,
 		Extended: true,  // HeadObject with if-modified-since header is an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
 	APItest{
 		Test:     mainHeadObjectIfUnModifiedSince,
+		Area:     areaObject,
 		Extended: true,  // HeadObject with if-unmodified-since header is an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
 	APItest{
 		Test:     mainHeadObjectIfMatch,
+		Area:     areaObject,
 		Extended: true,  // HeadObject with if-match header is an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
 	APItest{
 		Test:     mainHeadObjectIfNoneMatch,
+		Area:     areaObject,
 		Extended: true,  // HeadObject with if-none-match header is an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
@@ -306,6 +395,7 @@ var unpreparedTests = []APItest{
 	// Tests for ListBuckets API.
 	APItest{
 		Test:     mainListBuckets,
+		Area:     areaBucket,
 		Extended: false, // ListBuckets is not an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
@@ -313,122 +403,870 @@ var unpreparedTests = []APItest{
 	// Tests for ListObjects API.
 	APItest{
 		Test:     mainListObjectsV1UnPrepared,
+		Area:     areaListing,
 		Extended: false, // ListObjects is not an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
 	APItest{
 		Test:     mainListObjectsV2UnPrepared,
+		Area:     areaListing,
 		Extended: false, // ListObjects is not an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
+	APItest{
+		Test:     mainListObjectsV2StartAfterUnPrepared,
+		Area:     areaListing,
+		Extended: true,  // ListObjects V2 with start-after is an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainListObjectsPaginationUnPrepared,
+		Area:     areaListing,
+		Extended: false, // ListObjects is not an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainListObjectsV2PaginationUnPrepared,
+		Area:     areaListing,
+		Extended: false, // ListObjects V2 is not an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
 
 	// Tests for Multipart API.
 	APItest{
 		Test:     mainInitiateMultipartUpload,
+		Area:     areaMultipart,
 		Extended: false, // Initiate Multipart test must be run even without extended flags being set.
 		Critical: true,  // Initiate Multipart test must pass before other tests can be run.
+		Mutating: true,  // Starts multipart uploads.
 	},
 	APItest{
 		Test:     mainUploadPart,
+		Area:     areaMultipart,
 		Extended: false, // Upload Part test must be run even without extended flag being set.
 		Critical: true,  // Upload Part test must pass before other tests can be run.
+		Mutating: true,  // Uploads parts.
 	},
 	APItest{
 		Test:     mainListParts,
+		Area:     areaMultipart,
 		Extended: false, // List Part test must be run even without extended flag being set.
 		Critical: false, // List Part test can fail without affecting other tests.
 	},
 	APItest{
 		Test:     mainListMultipartUploads,
+		Area:     areaMultipart,
 		Extended: false, // List Multipart Uploads test must be run without extended flag being set.
 		Critical: false, // List Multipart Uploads test can fail without affecting other tests.
 	},
 	APItest{
 		Test:     mainCompleteMultipartUpload,
+		Area:     areaMultipart,
 		Extended: false, // Complete Multipart test must be run even without extended flag being set.
 		Critical: true,  // Complete Multipart test can fail without affecting other tests.
+		Mutating: true,  // Completes multipart uploads.
 	},
 	APItest{
 		Test:     mainAbortMultipartUpload,
+		Area:     areaMultipart,
 		Extended: false, // Abort Multipart test must be run even without extended flag being set.
 		Critical: false, // Abort Multipart test can fail without affecting other tests.
+		Mutating: true,  // Aborts multipart uploads.
 	},
 
 	// Tests for CopyObject API.
 	APItest{
 		Test:     mainCopyObject,
+		Area:     areaObject,
 		Extended: false, // CopyObject is not an extended API.
 		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Copies objects.
 	},
 	APItest{
 		Test:     mainCopyObjectIfModifiedSince,
+		Area:     areaObject,
 		Extended: true,  // CopyObject with if-modified-since header is an extended API.
 		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Copies objects.
 	},
 	APItest{
 		Test:     mainCopyObjectIfUnModifiedSince,
+		Area:     areaObject,
 		Extended: true,  // CopyObject with if-unmodified-since header is an extended API.
 		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Copies objects.
 	},
 	APItest{
 		Test:     mainCopyObjectIfMatch,
+		Area:     areaObject,
 		Extended: true,  // CopyObject with if-match header is an extended API.
 		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Copies objects.
 	},
 	APItest{
 		Test:     mainCopyObjectIfNoneMatch,
+		Area:     areaObject,
 		Extended: true,  // CopyObject with if-none-match header is an extended API.
 		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Copies objects.
 	},
 
 	// Tests for GetObject API.
 	APItest{
 		Test:     mainGetObject,
+		Area:     areaObject,
 		Extended: false, // GetObject is not an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
 	APItest{
 		Test:     mainGetObjectPresigned,
+		Area:     areaObject,
 		Extended: false, // GetObject Presigned is not an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
 	APItest{
 		Test:     mainGetObjectIfModifiedSince,
+		Area:     areaObject,
 		Extended: true,  // GetObject with if-modified-since header is an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
 	APItest{
 		Test:     mainGetObjectIfUnModifiedSince,
+		Area:     areaObject,
 		Extended: true,  // GetObject with if-unmodified-since header is an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
 	APItest{
 		Test:     mainGetObjectIfMatch,
+		Area:     areaObject,
 		Extended: true,  // GetObject with if-match header is an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
 	APItest{
 		Test:     mainGetObjectIfNoneMatch,
+		Area:     areaObject,
 		Extended: true,  // GetObject with if-none-match header is an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
 	APItest{
 		Test:     mainGetObjectRange,
+		Area:     areaObject,
 		Extended: true,  // GetObject with range header is an extended API.
 		Critical: false, // This test does not affect future tests.
 	},
+}
 
+// unpreparedRemoveTests - the tests removing the environment created by unpreparedSetupTests, run after sharedTests.
+var unpreparedRemoveTests = []APItest{
 	// Test for RemoveObject API.
 	APItest{
 		Test:     mainRemoveObjectExists,
+		Area:     areaObject,
 		Extended: false, // Remove Object test must be run.
 		Critical: true,  // Remove Object test must pass for future tests.
+		Mutating: true,  // Removes objects.
 	},
 
 	// Tests for RemoveBucket API.
 	APItest{
 		Test:     mainRemoveBucketExists,
+		Area:     areaBucket,
 		Extended: false, // RemoveBucket is not an extended API.
 		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Removes buckets.
+	},
+}
+
+// sharedTests - the tests that set up what they need themselves and are run the same way with or
+// without the --prepare flag. They are registered once and run before the remove tests of either list.
+var sharedTests = []APItest{
+	// Tests for PutBucket API.
+	APItest{
+		Test:     mainPutBucketMatrix,
+		Area:     areaBucket,
+		Extended: true,  // Bucket configurations are extended APIs.
+		Critical: false, // Tests needing a configured bucket fail on their own if it is missing.
+		Mutating: true,  // Creates buckets.
+	},
+
+	// Tests for GetBucketPolicy API.
+	APItest{
+		Test:     mainGetBucketVersioningUnset,
+		Area:     areaBucket,
+		Extended: true,  // GetBucketVersioning is an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainBucketCors,
+		Area:     areaBucket,
+		Extended: true,  // Bucket CORS is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads an object and sets the bucket's CORS configuration.
+	},
+	APItest{
+		Test:     mainConfigContentType,
+		Area:     areaBucket,
+		Extended: true,  // Content-Type enforcement is an extended check.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Sets bucket configurations and uploads an object.
+	},
+
+	// Tests for PutObject API.
+	APItest{
+		Test:     mainPutObjectStream,
+		Area:     areaObject,
+		Extended: true,  // PutObject with a large streamed body is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads objects.
+	},
+	APItest{
+		Test:     mainPutObjectReaderStream,
+		Area:     areaObject,
+		Extended: true,  // PutObject with a body read once is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads objects.
+	},
+	APItest{
+		Test:     mainPutObjectExpectContinue,
+		Area:     areaObject,
+		Extended: true,  // Expect: 100-continue is an extended feature.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainThrottleBandwidth,
+		Area:     areaOther,
+		Extended: true,  // Bandwidth throttling is not part of the S3 API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainPutObjectNoSuchBucket,
+		Area:     areaObject,
+		Extended: false, // PutObject is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Sends a PutObject request.
+	},
+	APItest{
+		Test:     mainPutObjectWebsiteRedirect,
+		Area:     areaObject,
+		Extended: true,  // Website redirects are an extended feature.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Creates a bucket and uploads objects.
+	},
+	APItest{
+		Test:     mainPutObjectSlashKeys,
+		Area:     areaObject,
+		Extended: false, // PutObject is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainPutObjectWhitespaceKeys,
+		Area:     areaObject,
+		Extended: false, // PutObject is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainPutObjectEncodedMetadata,
+		Area:     areaObject,
+		Extended: true,  // Non-ASCII metadata is an extended feature.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainPutObjectMetadata,
+		Area:     areaObject,
+		Extended: false, // PutObject is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes an object.
+	},
+	APItest{
+		Test:     mainPutObjectEmptyMetadata,
+		Area:     areaObject,
+		Extended: false, // PutObject is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes an object.
+	},
+	APItest{
+		Test:     mainPutObjectClientETag,
+		Area:     areaObject,
+		Extended: false, // PutObject is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes an object.
+	},
+	APItest{
+		Test:     mainPutObjectSSEC,
+		Area:     areaObject,
+		Extended: true,  // SSE-C is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes an object.
+	},
+	APItest{
+		Test:     mainPutObjectSSES3,
+		Area:     areaObject,
+		Extended: true,  // Server-side encryption is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainSSEKMSObject,
+		Area:     areaObject,
+		Extended: true,  // Server-side encryption is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainPutObjectEncryptedETag,
+		Area:     areaObject,
+		Extended: true,  // Server-side encryption is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainPutObjectBucketKey,
+		Area:     areaObject,
+		Extended: true,  // Server-side encryption is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads objects and creates a bucket.
+	},
+	APItest{
+		Test:     mainPutObjectStorageClass,
+		Area:     areaObject,
+		Extended: true,  // Storage classes are an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainPutObjectContentRange,
+		Area:     areaObject,
+		Extended: true,  // Content-Range on PUT is an extended check.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainPutObjectNoLength,
+		Area:     areaObject,
+		Extended: true,  // Length enforcement is an extended check.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Attempts to upload objects.
+	},
+	APItest{
+		Test:     mainPutObjectOverwrite,
+		Area:     areaObject,
+		Extended: false, // PutObject is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainPutObjectOverwriteSmaller,
+		Area:     areaObject,
+		Extended: false, // PutObject is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainHeaderCasing,
+		Area:     areaOther,
+		Extended: false, // Header casing is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Strict:   true,  // Header casing is checked with --strict only.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainOwnerConsistency,
+		Area:     areaListing,
+		Extended: true,  // ACLs are an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+
+	// Tests for HeadBucket API.
+	APItest{
+		Test:     mainHeadBucketForeign,
+		Area:     areaBucket,
+		Extended: true,  // Cross account access is an extended check.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainCustomHeaders,
+		Area:     areaOther,
+		Extended: false, // Custom headers must be checked whenever they are given.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainEndpointResolver,
+		Area:     areaOther,
+		Extended: true,  // Custom endpoint resolution is an extended check.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainSigningRegion,
+		Area:     areaOther,
+		Extended: false, // Signing must be checked even without extended flag being set.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainSignatureV2,
+		Area:     areaOther,
+		Extended: false, // Signing must be checked even without extended flag being set.
+		Critical: false, // This test does not affect future tests.
+	},
+
+	// Tests for ListObjects API.
+	APItest{
+		Test:     mainListObjectsEmptyBucket,
+		Area:     areaListing,
+		Extended: false, // ListObjects is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Creates and removes a bucket.
+	},
+	APItest{
+		Test:     mainListObjectsAfterDelete,
+		Area:     areaListing,
+		Extended: false, // ListObjects is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainListObjectsPrefixKey,
+		Area:     areaListing,
+		Extended: false, // ListObjects is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainGetBucketRoot,
+		Area:     areaListing,
+		Extended: false, // ListObjects is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes an object.
+	},
+
+	// Tests for Multipart API.
+	APItest{
+		Test:     mainListMultipart,
+		Area:     areaMultipart,
+		Extended: false, // List Multipart Uploads and List Parts are not extended APIs.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Starts and aborts two uploads.
+	},
+	APItest{
+		Test:     mainAbortMultipartUploadReclaimed,
+		Area:     areaMultipart,
+		Extended: true,  // Staged part storage checks are an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads parts and aborts multipart uploads.
+	},
+	APItest{
+		Test:     mainMultipartInProgressVisibility,
+		Area:     areaMultipart,
+		Extended: false, // Multipart visibility must be checked even without extended flag being set.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainMultipartSinglePart,
+		Area:     areaMultipart,
+		Extended: false, // Multipart is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainMultipartReadAfterComplete,
+		Area:     areaMultipart,
+		Extended: false, // Multipart is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainCompleteMultipartUploadInvalid,
+		Area:     areaMultipart,
+		Extended: false, // Multipart is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Starts and aborts multipart uploads.
+	},
+	APItest{
+		Test:     mainMalformedXML,
+		Area:     areaOther,
+		Extended: true,  // Malformed request bodies are an extended check.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Sends writes to the bucket that must be rejected.
+	},
+	APItest{
+		Test:     mainUploadPartInvalid,
+		Area:     areaMultipart,
+		Extended: false, // Multipart is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Starts and aborts multipart uploads.
+	},
+	APItest{
+		Test:     mainUploadPartTooLarge,
+		Area:     areaMultipart,
+		Extended: true,  // Uploading a part over the size limit is an extended test.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Starts and aborts a multipart upload.
+	},
+	APItest{
+		Test:     mainMultipartInitMetadata,
+		Area:     areaMultipart,
+		Extended: false, // Multipart is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainMultipartRangeBoundary,
+		Area:     areaMultipart,
+		Extended: false, // Multipart is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+
+	// Tests for CopyObject API.
+	APItest{
+		Test:     mainCopyObjectMetadataDirective,
+		Area:     areaObject,
+		Extended: false, // CopyObject is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and copies objects.
 	},
+	APItest{
+		Test:     mainCopyObjectVersion,
+		Area:     areaObject,
+		Extended: true,  // CopyObject with a source versionId is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Creates a bucket and copies objects.
+		Setup:    setupCopyObjectVersion,
+		Teardown: teardownCopyObjectVersion,
+	},
+	APItest{
+		Test:     mainCopyObjectEncodedSource,
+		Area:     areaObject,
+		Extended: false, // CopyObject is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Copies objects.
+	},
+	APItest{
+		Test:     mainCopyObjectStorageClass,
+		Area:     areaObject,
+		Extended: true,  // CopyObject with a storage class is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and copies objects.
+	},
+	APItest{
+		Test:     mainCopyObjectSelf,
+		Area:     areaObject,
+		Extended: true,  // Copying an object onto itself is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads, copies and removes an object.
+	},
+
+	// Tests for GetObject API.
+	APItest{
+		Test:     mainGetObjectNoSuchKey,
+		Area:     areaObject,
+		Extended: false, // GetObject is not an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainMethodNotAllowed,
+		Area:     areaOther,
+		Extended: false, // Error responses are not an extended check.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainGetObjectIntegrity,
+		Area:     areaObject,
+		Extended: false, // GetObject is not an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainConnectionReuse,
+		Area:     areaOther,
+		Extended: true,  // Connection reuse is not part of the S3 API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainCredentialProcess,
+		Area:     areaOther,
+		Extended: true,  // Credential processes are not part of the S3 API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainGetObjectPresignedTampered,
+		Area:     areaObject,
+		Extended: false, // GetObject Presigned is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainGetObjectConditionalCombined,
+		Area:     areaObject,
+		Extended: true,  // GetObject with combined conditional headers is an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainConditionalGetObject,
+		Area:     areaObject,
+		Extended: true,  // GetObject with conditional headers is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes an object.
+	},
+	APItest{
+		Test:     mainGetObjectRangeForms,
+		Area:     areaObject,
+		Extended: true,  // GetObject with range header is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes an object.
+	},
+	APItest{
+		Test:     mainGetObjectMultiRange,
+		Area:     areaObject,
+		Extended: true,  // GetObject with range header is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes an object.
+	},
+	APItest{
+		Test:     mainGetObjectVersionRange,
+		Area:     areaObject,
+		Extended: true,  // Versioning is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes object versions.
+	},
+	APItest{
+		Test:     mainObjectTaggingVersions,
+		Area:     areaObject,
+		Extended: true,  // Object tagging is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes object versions.
+	},
+	APItest{
+		Test:     mainObjectTagging,
+		Area:     areaObject,
+		Extended: true,  // Object tagging is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainHeadObjectTaggingCount,
+		Area:     areaObject,
+		Extended: true,  // Object tagging is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainGetObjectIdentity,
+		Area:     areaObject,
+		Extended: false, // GetObject is not an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainGetObjectEncoding,
+		Area:     areaObject,
+		Extended: false, // GetObject is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes an object.
+	},
+	APItest{
+		Test:     mainGetObjectEmpty,
+		Area:     areaObject,
+		Extended: false, // GetObject is not an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainGetObjectAttributes,
+		Area:     areaObject,
+		Extended: true,  // GetObjectAttributes is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainDeleteObjects,
+		Area:     areaObject,
+		Extended: true,  // DeleteObjects is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainDeleteObjectsDuplicateKeys,
+		Area:     areaObject,
+		Extended: true,  // DeleteObjects is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes an object.
+	},
+	APItest{
+		Test:     mainEmptyBucket,
+		Area:     areaObject,
+		Extended: true,  // Emptying a bucket with DeleteObjects is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Removes objects.
+	},
+	APItest{
+		Test:     mainDeleteObjectsEmpty,
+		Area:     areaObject,
+		Extended: true,  // DeleteObjects is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Sends a DeleteObjects request.
+	},
+
+	// Test for RemoveObject API.
+	APItest{
+		Test:     mainRemoveObjectVersioned,
+		Area:     areaObject,
+		Extended: true,  // Versioning is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainVersioning,
+		Area:     areaBucket,
+		Extended: true,  // Versioning is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+	APItest{
+		Test:     mainObjectLock,
+		Area:     areaObject,
+		Extended: true,  // Object lock is an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads, locks and removes an object version.
+	},
+	APItest{
+		Test:     mainRemoveObjectIfMatch,
+		Area:     areaObject,
+		Extended: true,  // Conditional deletes are an extended API.
+		Critical: false, // This test does not affect future tests.
+		Mutating: true,  // Uploads and removes objects.
+	},
+}
+
+// Tests - holds all tests that must be run differently based on usage of the -- flag.
+var preparedTests = joinTests(preparedSetupTests, sharedTests, preparedRemoveTests)
+
+// Tests - holds all tests that must be run differently based on usage of the -- flag.
+var unpreparedTests = joinTests(unpreparedSetupTests, sharedTests, unpreparedRemoveTests)
+
+// Tests - holds the tests that are safe to run against a production bucket. None of them may be Mutating.
+var readOnlyTests = []APItest{
+	// Tests for HeadBucket API.
+	APItest{
+		Test:     mainHeadBucket,
+		Area:     areaBucket,
+		Extended: false, // HeadBucket is not an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainSignatureV2,
+		Area:     areaOther,
+		Extended: false, // Signing must be checked even without extended flag being set.
+		Critical: false, // This test does not affect future tests.
+	},
+
+	// Tests for HeadObject API.
+	APItest{
+		Test:     mainHeadObject,
+		Area:     areaObject,
+		Extended: false, // HeadObject is not an extended API.
+		Critical: true,  // This test affects future tests and must pass.
+	},
+	APItest{
+		Test:     mainHeadObjectIfModifiedSince,
+		Area:     areaObject,
+		Extended: true,  // HeadObject with if-modified-since header is an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainHeadObjectIfUnModifiedSince,
+		Area:     areaObject,
+		Extended: true,  // HeadObject with if-unmodified-since header is an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainHeadObjectIfMatch,
+		Area:     areaObject,
+		Extended: true,  // HeadObject with if-match header is an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainHeadObjectIfNoneMatch,
+		Area:     areaObject,
+		Extended: true,  // HeadObject with if-none-match header is an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+
+	// Tests for ListObjects API.
+	APItest{
+		Test:     mainListObjectsReadOnly,
+		Area:     areaListing,
+		Extended: false, // ListObjects is not an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+
+	// Tests for GetObject API.
+	APItest{
+		Test:     mainGetObject,
+		Area:     areaObject,
+		Extended: false, // GetObject is not an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainGetObjectPresigned,
+		Area:     areaObject,
+		Extended: false, // GetObject Presigned is not an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainGetObjectIfModifiedSince,
+		Area:     areaObject,
+		Extended: true,  // GetObject with if-modified-since header is an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainGetObjectIfUnModifiedSince,
+		Area:     areaObject,
+		Extended: true,  // GetObject with if-unmodified-since header is an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainGetObjectIfMatch,
+		Area:     areaObject,
+		Extended: true,  // GetObject with if-match header is an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainGetObjectIfNoneMatch,
+		Area:     areaObject,
+		Extended: true,  // GetObject with if-none-match header is an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainGetObjectConditionalCombined,
+		Area:     areaObject,
+		Extended: true,  // GetObject with combined conditional headers is an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainGetObjectRange,
+		Area:     areaObject,
+		Extended: true,  // GetObject with range header is an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+	APItest{
+		Test:     mainGetObjectIdentity,
+		Area:     areaObject,
+		Extended: false, // GetObject is not an extended API.
+		Critical: false, // This test does not affect future tests.
+	},
+}
+
+// joinTests - the tests of every list in lists, in order.
+func joinTests(lists ...[]APItest) []APItest {
+	tests := []APItest{}
+	for _, list := range lists {
+		tests = append(tests, list...)
+	}
+	return tests
 }
@@ -0,0 +1,207 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// A throttled read or write moves at most this fraction of a second's worth of bytes at once,
+// so the bandwidth stays even instead of arriving in bursts.
+const throttleSlices = 10
+
+// Bounds of the throttle test: the smallest and largest object uploaded to measure the bandwidth,
+// how far below the configured duration the measured one may fall and how far above it may rise,
+// the server's own latency included.
+const (
+	minThrottleObjectSize = 64 * 1024
+	maxThrottleObjectSize = 64 * 1024 * 1024
+	throttleTolerance     = 0.8
+	throttleSlowdown      = 2.0
+)
+
+// bandwidthLimiter - spaces bytes evenly so no more than a given number pass per second.
+type bandwidthLimiter struct {
+	mutex       sync.Mutex
+	bytesPerSec int64
+	next        time.Time // The time the bytes reserved so far have passed.
+}
+
+// wait - block until n more bytes may pass.
+func (l *bandwidthLimiter) wait(n int) {
+	l.mutex.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	l.next = l.next.Add(time.Duration(n) * time.Second / time.Duration(l.bytesPerSec))
+	sleep := l.next.Sub(now)
+	l.mutex.Unlock()
+	time.Sleep(sleep)
+}
+
+// chunk - the most bytes a single read or write may move.
+func (l *bandwidthLimiter) chunk() int {
+	if size := l.bytesPerSec / throttleSlices; size > 0 {
+		return int(size)
+	}
+	return 1
+}
+
+// throttledConn - a connection whose reads and writes are limited to the bandwidth of a slow link.
+type throttledConn struct {
+	net.Conn
+	up   *bandwidthLimiter
+	down *bandwidthLimiter
+}
+
+// Read - read no faster than the downstream bandwidth.
+func (c throttledConn) Read(p []byte) (int, error) {
+	if len(p) > c.down.chunk() {
+		p = p[:c.down.chunk()]
+	}
+	n, err := c.Conn.Read(p)
+	c.down.wait(n)
+	return n, err
+}
+
+// Write - write no faster than the upstream bandwidth.
+func (c throttledConn) Write(p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		size := len(p) - written
+		if size > c.up.chunk() {
+			size = c.up.chunk()
+		}
+		c.up.wait(size)
+		n, err := c.Conn.Write(p[written : written+size])
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// newThrottledDialContext - wrap dial so that all connections together transfer at most bytesPerSec in
+// each direction, like a single slow link. dial is returned as is if bytesPerSec is 0.
+func newThrottledDialContext(dial dialContextFunc, bytesPerSec int64) dialContextFunc {
+	if bytesPerSec <= 0 {
+		return dial
+	}
+	up := &bandwidthLimiter{bytesPerSec: bytesPerSec}
+	down := &bandwidthLimiter{bytesPerSec: bytesPerSec}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return throttledConn{Conn: conn, up: up, down: down}, nil
+	}
+}
+
+// mainThrottleBandwidth - Test that --throttle-bandwidth holds uploads and downloads to the given bandwidth.
+// The object takes about two seconds at that bandwidth, within the size bounds.
+func mainThrottleBandwidth(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Throttle Bandwidth:", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	if config.ThrottleBandwidth <= 0 {
+		printMessage(message, nil)
+		printDetail("Skipped: --throttle-bandwidth was not given")
+		return true
+	}
+	size := 2 * config.ThrottleBandwidth
+	if size < minThrottleObjectSize {
+		size = minThrottleObjectSize
+	}
+	if size > maxThrottleObjectSize {
+		size = maxThrottleObjectSize
+	}
+	expected := float64(size) / float64(config.ThrottleBandwidth) * float64(time.Second)
+	minDuration := time.Duration(throttleTolerance * expected)
+	maxDuration := time.Duration(throttleSlowdown * expected)
+	bucketName := s3verifyBuckets[0].Name
+	object := &ObjectInfo{
+		Key:  "s3verify/throttle/object",
+		Body: generatedBody(time.Now().UnixNano(), int(size)),
+	}
+	// Spin scanBar
+	scanBar(message)
+	start := time.Now()
+	if _, err := putObject(config, bucketName, object); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	upload := time.Since(start)
+	// Spin scanBar
+	scanBar(message)
+	req, err := newGetObjectReq(bucketName, object.Key, nil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	start = time.Now()
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if err := verifyStatusGetObject(res.StatusCode, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	download := time.Since(start)
+	if err := verifyBodyEqual(object.Body, body); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	for direction, took := range map[string]time.Duration{"Upload": upload, "Download": download} {
+		if took < minDuration {
+			err := fmt.Errorf("Unexpected %s Duration: %d bytes at %d bytes/s must take at least %v, took %v", direction, size, config.ThrottleBandwidth, minDuration, took)
+			printMessage(message, err)
+			return false
+		}
+		if took > maxDuration {
+			err := fmt.Errorf("Unexpected %s Duration: %d bytes at %d bytes/s must take at most %v, took %v", direction, size, config.ThrottleBandwidth, maxDuration, took)
+			printMessage(message, err)
+			return false
+		}
+	}
+	if err := removeObject(config, bucketName, object.Key); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	printDetail(fmt.Sprintf("Upload %v, download %v", upload, download))
+	return true
+}
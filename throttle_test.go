@@ -0,0 +1,65 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Test that the throttled dialer holds a download to the given bandwidth, neither faster nor much slower.
+func TestThrottledDialContext(t *testing.T) {
+	const bytesPerSec = 256 * 1024
+	data := bytes.Repeat([]byte("s3verify"), bytesPerSec/16)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	transport := &http.Transport{
+		DialContext: newThrottledDialContext((&net.Dialer{}).DialContext, bytesPerSec),
+	}
+	defer transport.CloseIdleConnections()
+	client := &http.Client{Transport: transport}
+
+	expected := float64(len(data)) / bytesPerSec * float64(time.Second)
+	start := time.Now()
+	res, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	took := time.Since(start)
+	if !bytes.Equal(body, data) {
+		t.Fatalf("Expected the body to pass through unchanged")
+	}
+	if minDuration := time.Duration(throttleTolerance * expected); took < minDuration {
+		t.Errorf("Expected %d bytes at %d bytes/s to take at least %v, took %v", len(data), bytesPerSec, minDuration, took)
+	}
+	if maxDuration := time.Duration(throttleSlowdown * expected); took > maxDuration {
+		t.Errorf("Expected %d bytes at %d bytes/s to take at most %v, took %v", len(data), bytesPerSec, maxDuration, took)
+	}
+}
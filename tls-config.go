@@ -0,0 +1,52 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/minio/mc/pkg/console"
+)
+
+// newTLSConfig - create the TLS configuration of the HTTP transport. With caBundle the server
+// certificate must be issued by one of the PEM certificates in that file instead of a system CA,
+// for gateways with self-signed or internal certificates. insecureSkipVerify disables verification
+// altogether and takes precedence over caBundle.
+func newTLSConfig(insecureSkipVerify bool, caBundle string) (*tls.Config, error) {
+	if insecureSkipVerify {
+		if caBundle != "" {
+			console.Errorln("Warning: --insecure disables certificate verification, --ca-bundle is ignored.")
+		}
+		return &tls.Config{InsecureSkipVerify: true}, nil
+	}
+	if caBundle == "" {
+		return nil, nil
+	}
+	pemCerts, err := ioutil.ReadFile(caBundle)
+	if err != nil {
+		return nil, err
+	}
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(pemCerts) {
+		err := fmt.Errorf("Invalid CA Bundle: no PEM certificates found in %s", caBundle)
+		return nil, err
+	}
+	return &tls.Config{RootCAs: rootCAs}, nil
+}
@@ -0,0 +1,109 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newCABundle - write the certificate of server to a CA bundle in dir and return its path.
+func newCABundle(t *testing.T, dir string, server *httptest.Server) string {
+	caBundle := filepath.Join(dir, "ca-bundle.pem")
+	pemCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := ioutil.WriteFile(caBundle, pemCert, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return caBundle
+}
+
+// Test that a server with a certificate from a private CA is trusted only when the CA bundle is
+// configured, or certificate verification is turned off, and the connection fails otherwise.
+func TestTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeS3Headers(w)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "s3verify-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	caBundle := newCABundle(t, dir, server)
+
+	testCases := []struct {
+		insecureSkipVerify bool
+		caBundle           string
+		shouldPass         bool
+	}{
+		// The private CA is trusted.
+		{false, caBundle, true},
+		// The system roots do not know the private CA, the connection fails closed.
+		{false, "", false},
+		// Certificate verification is off.
+		{true, "", true},
+		// Certificate verification is off, the CA bundle is ignored.
+		{true, caBundle, true},
+	}
+	for i, testCase := range testCases {
+		tlsConfig, err := newTLSConfig(testCase.insecureSkipVerify, testCase.caBundle)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		client := &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: tlsConfig,
+			},
+		}
+		res, err := client.Get(server.URL)
+		if err == nil {
+			drainAndClose(res)
+		}
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Expected the connection to succeed, got %v", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected the connection to fail certificate verification", i+1)
+		}
+	}
+}
+
+// Test that a CA bundle that cannot be read or has no certificates in it is an error.
+func TestTLSConfigInvalidBundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "s3verify-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	empty := filepath.Join(dir, "empty.pem")
+	if err := ioutil.WriteFile(empty, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, caBundle := range []string{filepath.Join(dir, "missing.pem"), empty} {
+		if _, err := newTLSConfig(false, caBundle); err == nil {
+			t.Errorf("Test %d: Expected an error for CA bundle %s", i+1, caBundle)
+		}
+	}
+}
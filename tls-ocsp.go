@@ -0,0 +1,113 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// ocspStatusNames - human readable names for the OCSP certificate statuses.
+var ocspStatusNames = map[int]string{
+	ocsp.Good:    "good",
+	ocsp.Revoked: "revoked",
+	ocsp.Unknown: "unknown",
+}
+
+// checkOCSPStaple - perform a TLS handshake with the endpoint of config and report whether the server stapled
+// an OCSP response to its certificate and if so whether that response is currently valid.
+//
+// The connection is opened and verified by the same transport settings as every request, so it
+// goes through the same proxy and trusts the same certificates.
+//
+// A missing staple is not an error, it is only reported. Errors are returned when the handshake
+// fails or when a staple is present but cannot be parsed.
+func checkOCSPStaple(config ServerConfig) (string, error) {
+	endpointURL, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return "", err
+	}
+	if endpointURL.Scheme != "https" {
+		return fmt.Sprintf("OCSP: %s does not use TLS, skipping stapling check.", config.Endpoint), nil
+	}
+	transport, err := config.newTransport()
+	if err != nil {
+		return "", err
+	}
+	host := endpointURL.Host
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "443")
+	}
+	var state tls.ConnectionState
+	tlsConfig := &tls.Config{}
+	if transport.TLSClientConfig != nil {
+		tlsConfig = transport.TLSClientConfig.Clone()
+	}
+	tlsConfig.ServerName = endpointURL.Hostname()
+	// Capture the connection state once the certificate chain has been verified.
+	tlsConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+		state = cs
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rawConn, err := transport.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return "", err
+	}
+	conn := tls.Client(rawConn, tlsConfig)
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	if err := conn.Handshake(); err != nil {
+		return "", err
+	}
+
+	if len(state.OCSPResponse) == 0 {
+		return fmt.Sprintf("OCSP: %s did not staple an OCSP response.", endpointURL.Host), nil
+	}
+	// The issuer is needed to check the signature on the stapled response.
+	var issuer *x509.Certificate
+	if len(state.VerifiedChains) > 0 && len(state.VerifiedChains[0]) > 1 {
+		issuer = state.VerifiedChains[0][1]
+	} else if len(state.PeerCertificates) > 1 {
+		issuer = state.PeerCertificates[1]
+	}
+	response, err := ocsp.ParseResponse(state.OCSPResponse, issuer)
+	if err != nil {
+		err = fmt.Errorf("OCSP: %s stapled an invalid OCSP response: %v", endpointURL.Host, err)
+		return "", err
+	}
+	status, ok := ocspStatusNames[response.Status]
+	if !ok {
+		status = fmt.Sprintf("unrecognized (%d)", response.Status)
+	}
+	now := time.Now().UTC()
+	if !response.NextUpdate.IsZero() && now.After(response.NextUpdate) {
+		return fmt.Sprintf("OCSP: %s stapled a response with status %s that expired at %s.",
+			endpointURL.Host, status, response.NextUpdate.Format(time.RFC1123)), nil
+	}
+	return fmt.Sprintf("OCSP: %s stapled a response with status %s, produced at %s and valid until %s.",
+		endpointURL.Host, status, response.ProducedAt.Format(time.RFC1123), response.NextUpdate.Format(time.RFC1123)), nil
+}
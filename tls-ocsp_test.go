@@ -0,0 +1,65 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// Test that the OCSP check trusts the certificates configured for every request, it must not fail
+// against a server with a private CA that the tests themselves can reach.
+func TestCheckOCSPStaple(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "s3verify-ocsp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	caBundle := newCABundle(t, dir, server)
+
+	testCases := []struct {
+		insecureSkipVerify bool
+		caBundle           string
+		shouldPass         bool
+	}{
+		{false, caBundle, true},
+		{false, "", false},
+		{true, "", true},
+	}
+	for i, testCase := range testCases {
+		config := newTestConfig(server.URL)
+		config.InsecureSkipVerify = testCase.insecureSkipVerify
+		config.CABundle = testCase.caBundle
+		report, err := checkOCSPStaple(config)
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: Expected the handshake to succeed, got %v", i+1, err)
+		}
+		if testCase.shouldPass && !strings.Contains(report, "did not staple an OCSP response") {
+			t.Errorf("Test %d: Expected no staple to be reported, got %q", i+1, report)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: Expected the handshake to fail certificate verification", i+1)
+		}
+	}
+}
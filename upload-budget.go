@@ -0,0 +1,68 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// uploadBudget - tracks the number of bytes uploaded against an optional limit.
+type uploadBudget struct {
+	mutex    sync.Mutex
+	limit    int64 // A limit of 0 means uploads are not limited.
+	used     int64
+	exceeded bool // Set once a request was refused for exceeding the limit.
+}
+
+// newUploadBudget - create a new budget allowing limit bytes to be uploaded.
+func newUploadBudget(limit int64) *uploadBudget {
+	return &uploadBudget{
+		limit: limit,
+	}
+}
+
+// reserve - account for size bytes about to be uploaded. An error is returned
+// and nothing is accounted for if doing so would exceed the limit.
+func (b *uploadBudget) reserve(size int64) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.limit > 0 && b.used+size > b.limit {
+		b.exceeded = true
+		err := fmt.Errorf("Upload Budget Exceeded: uploading %d more bytes would exceed --max-upload-bytes (%d of %d bytes used)", size, b.used, b.limit)
+		return err
+	}
+	b.used += size
+	return nil
+}
+
+// isExceeded - report whether any upload was refused.
+func (b *uploadBudget) isExceeded() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.exceeded
+}
+
+// String - summary of the budget used.
+func (b *uploadBudget) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.limit == 0 {
+		return fmt.Sprintf("Uploaded %d bytes (no budget set)", b.used)
+	}
+	return fmt.Sprintf("Uploaded %d of %d bytes budgeted", b.used, b.limit)
+}
@@ -0,0 +1,131 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// invalidPartNumbers - part numbers outside of the 1 to 10000 S3 allows, including one that overflows 32 bits.
+var invalidPartNumbers = []string{"0", "-1", "10001", "4294967297"}
+
+// uploadPartInvalidCodes - the error codes servers use to reject a part number.
+var uploadPartInvalidCodes = []string{"InvalidArgument", "InvalidPartNumber"}
+
+// uploadPartInvalidVerify - verify an upload with an invalid part number was rejected.
+func uploadPartInvalidVerify(res *http.Response) error {
+	if err := verifyStatusUploadPart(res.StatusCode, http.StatusBadRequest); err != nil {
+		return err
+	}
+	if err := verifyStandardHeaders(res.Header); err != nil {
+		return err
+	}
+	errResponse := ErrorResponse{}
+	if err := xmlDecoder(res.Body, &errResponse); err != nil {
+		return err
+	}
+	for _, code := range uploadPartInvalidCodes {
+		if errResponse.Code == code {
+			return nil
+		}
+	}
+	err := fmt.Errorf("Unexpected Error Response: wanted one of %v, got %v", strings.Join(uploadPartInvalidCodes, ", "), errResponse.Code)
+	return err
+}
+
+// listUploadParts - list the parts uploaded so far.
+func listUploadParts(config ServerConfig, bucketName, objectName, uploadID string) ([]objectPart, error) {
+	req, err := newListPartsReq(bucketName, objectName, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(res)
+	if err := verifyStatusListParts(res.StatusCode, http.StatusOK); err != nil {
+		return nil, err
+	}
+	result := listObjectPartsResult{}
+	if err := xmlDecoder(res.Body, &result); err != nil {
+		return nil, err
+	}
+	return result.ObjectParts, nil
+}
+
+// mainUploadPartInvalid - upload parts with out of range part numbers and verify each is rejected
+// with 400 and that none of them was stored as part of the upload.
+func mainUploadPartInvalid(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Multipart (Invalid Part Number):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	// All multipart operations take place in the s3verify created buckets.
+	bucketName := s3verifyBuckets[0].Name
+	objectName := "s3verify/multipart/invalid-part-number"
+	partData := []byte("s3verify invalid part number")
+	uploadID, err := initiateMultipartUpload(config, bucketName, objectName)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	for _, partNumber := range invalidPartNumbers {
+		// Spin scanBar
+		scanBar(message)
+		req, err := newUploadPartReq(bucketName, objectName, uploadID, 1, partData)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		// Set the part number as a string so even values an int can not hold are sent as is.
+		req.queryValues.Set("partNumber", partNumber)
+		res, err := config.execRequest("PUT", req)
+		if err != nil {
+			printMessage(message, err)
+			return false
+		}
+		err = uploadPartInvalidVerify(res)
+		drainAndClose(res)
+		if err != nil {
+			err = fmt.Errorf("partNumber=%s: %v", partNumber, err)
+			printMessage(message, err)
+			return false
+		}
+	}
+	// Spin scanBar
+	scanBar(message)
+	// A rejected part must not have been stored under some other part number either.
+	parts, err := listUploadParts(config, bucketName, objectName, uploadID)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if len(parts) != 0 {
+		err := fmt.Errorf("Unexpected Parts Listed: wanted none after only invalid part numbers, got %d starting with part %d", len(parts), parts[0].PartNumber)
+		printMessage(message, err)
+		return false
+	}
+	if err := abortMultipartUpload(config, bucketName, objectName, uploadID); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
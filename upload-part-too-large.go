@@ -0,0 +1,93 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// defaultMaxPartSize - the largest part S3 accepts, 5GiB.
+const defaultMaxPartSize = 5 * 1024 * 1024 * 1024
+
+// uploadPartTooLargeVerify - verify a part over the size limit was rejected with EntityTooLarge.
+func uploadPartTooLargeVerify(res *http.Response) error {
+	if err := verifyStatusUploadPart(res.StatusCode, http.StatusBadRequest); err != nil {
+		return err
+	}
+	if err := verifyStandardHeaders(res.Header); err != nil {
+		return err
+	}
+	errResponse := ErrorResponse{}
+	if err := xmlDecoder(res.Body, &errResponse); err != nil {
+		return err
+	}
+	if errResponse.Code != "EntityTooLarge" {
+		err := fmt.Errorf("Unexpected Error Response: wanted EntityTooLarge, got %v", errResponse.Code)
+		return err
+	}
+	return nil
+}
+
+// mainUploadPartTooLarge - upload a part one byte over the part size limit and verify it is rejected
+// with 400 EntityTooLarge. The limit is 5GiB unless lowered with --max-part-size.
+func mainUploadPartTooLarge(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Multipart (Part Too Large):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	// All multipart operations take place in the s3verify created buckets.
+	bucketName := s3verifyBuckets[0].Name
+	objectName := "s3verify/multipart/part-too-large"
+	uploadID, err := initiateMultipartUpload(config, bucketName, objectName)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// The part is generated as it is sent so it is never held in memory.
+	body := newGeneratedReader(time.Now().UnixNano(), globalMaxPartSize+1)
+	req, err := newUploadPartStreamReq(bucketName, objectName, uploadID, 1, body)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// A connection reset is not retried so that the part is only ever uploaded once.
+	res, err := config.execRequestOnce("PUT", req)
+	if err != nil {
+		err = fmt.Errorf("No Response Received: the server closed the connection instead of answering with EntityTooLarge: %v", err)
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if err := uploadPartTooLargeVerify(res); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := abortMultipartUpload(config, bucketName, objectName, uploadID); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -18,8 +18,8 @@ package main
 
 import (
 	"bytes"
+	"crypto/md5"
 	crand "crypto/rand"
-	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -46,6 +46,12 @@ var complMultipartUploads = []*completeMultipartUpload{
 
 // newUploadPartReq - Create a new HTTP request for an upload part request.
 func newUploadPartReq(bucketName, objectName, uploadID string, partNumber int, partData []byte) (Request, error) {
+	return newUploadPartStreamReq(bucketName, objectName, uploadID, partNumber, bytes.NewReader(partData))
+}
+
+// newUploadPartStreamReq - Create a new HTTP request for an upload part request from a seekable body.
+// The body is hashed in a first pass and then rewound to be sent, so it is never buffered.
+func newUploadPartStreamReq(bucketName, objectName, uploadID string, partNumber int, body io.ReadSeeker) (Request, error) {
 	// Create a new request for uploading a part.
 	var uploadPartReq = Request{
 		customHeader: http.Header{},
@@ -62,30 +68,28 @@ func newUploadPartReq(bucketName, objectName, uploadID string, partNumber int, p
 	uploadPartReq.queryValues = urlValues
 
 	// Compute md5sum, sha256Sum and contentlength.
-	reader := bytes.NewReader(partData)
-	md5Sum, sha256Sum, contentLength, err := computeHash(reader)
+	md5Sum, sha256Sum, contentLength, err := computeHashWithStrategy(body, globalHashStrategy)
 	if err != nil {
 		return Request{}, err
 	}
 
 	// Set the Header values and Body of request.
-	uploadPartReq.contentBody = ioutil.NopCloser(reader)
+	uploadPartReq.contentBody = body
 	uploadPartReq.contentLength = contentLength
-	uploadPartReq.customHeader.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
-	uploadPartReq.customHeader.Set("Content-MD5", base64.StdEncoding.EncodeToString(md5Sum))
+	setBodyHashHeaders(uploadPartReq.customHeader, md5Sum, sha256Sum)
 
 	return uploadPartReq, nil
 }
 
 // uploadPartVerify - verify that the response returned matches what is expected.
-func uploadPartVerify(res *http.Response, expectedStatusCode int) error {
+func uploadPartVerify(res *http.Response, expectedStatusCode int, partData []byte) error {
 	if err := verifyBodyUploadPart(res.Body); err != nil {
 		return err
 	}
 	if err := verifyStatusUploadPart(res.StatusCode, expectedStatusCode); err != nil {
 		return err
 	}
-	if err := verifyHeaderUploadPart(res.Header); err != nil {
+	if err := verifyHeaderUploadPart(res.Header, partData); err != nil {
 		return err
 	}
 	return nil
@@ -114,13 +118,37 @@ func verifyStatusUploadPart(respStatusCode, expectedStatusCode int) error {
 }
 
 // verifyHeaderUploadPart - verify that the header returned matches what is expected.
-func verifyHeaderUploadPart(header http.Header) error {
+func verifyHeaderUploadPart(header http.Header, partData []byte) error {
 	if err := verifyStandardHeaders(header); err != nil {
 		return err
 	}
+	// The ETag of a part is the hex encoded MD5 of the part's bytes.
+	partMD5 := md5.Sum(partData)
+	expectedETag := hex.EncodeToString(partMD5[:])
+	if eTag := strings.Trim(header.Get("ETag"), "\""); eTag != expectedETag {
+		err := fmt.Errorf("Unexpected Part ETag Received: wanted %v, got %v", expectedETag, eTag)
+		return err
+	}
 	return nil
 }
 
+// uploadPart - upload a single part, verify the response and return the unquoted ETag of the part.
+func uploadPart(config ServerConfig, bucketName, objectName, uploadID string, partNumber int, partData []byte) (string, error) {
+	req, err := newUploadPartReq(bucketName, objectName, uploadID, partNumber, partData)
+	if err != nil {
+		return "", err
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		return "", err
+	}
+	defer drainAndClose(res)
+	if err := uploadPartVerify(res, http.StatusOK, partData); err != nil {
+		return "", err
+	}
+	return strings.Trim(res.Header.Get("ETag"), "\""), nil
+}
+
 // mainUploadPart - upload part test.
 func mainUploadPart(config ServerConfig, curTest int) bool {
 	message := fmt.Sprintf("[%02d/%d] Multipart (Upload-Part):", curTest, globalTotalNumTest)
@@ -128,49 +156,61 @@ func mainUploadPart(config ServerConfig, curTest int) bool {
 	scanBar(message)
 	// All multipart objects created by s3verify will be stored in s3verify buckets.
 	bucketName := s3verifyBuckets[0].Name
-	// TODO: upload more than one part for at least one object.
-	for i, object := range multipartObjects { // Upload 1 5MB or smaller part per object.
-		// Spin scanBar
-		scanBar(message)
-		part := objectPart{}
-		// Create some random data at most 5MB to upload via multipart operations.
-		objectData := make([]byte, rand.Intn(1<<20)+4*1024*1024)
-		part.PartNumber = 1
-		part.Size = int64(len(objectData))
-		_, err := io.ReadFull(crand.Reader, objectData)
-		if err != nil {
-			printMessage(message, err)
-			return false
-		}
-		// Create a new multipart upload part request.
-		req, err := newUploadPartReq(bucketName, object.Key, object.UploadID, 1, objectData)
-		if err != nil {
-			printMessage(message, err)
-			return false
-		}
-		// Execute the request.
-		res, err := config.execRequest("PUT", req)
-		if err != nil {
-			printMessage(message, err)
-			return false
+	for i, object := range multipartObjects {
+		// The object to be completed is uploaded as a full 5MB part and a short last part,
+		// the object to be aborted as a single part of at most 5MB.
+		partSizes := []int{rand.Intn(1<<20) + 4*1024*1024}
+		if i == 0 {
+			partSizes = []int{5 * 1024 * 1024, rand.Intn(1<<20) + 1}
 		}
-		defer closeResponse(res)
-		// Verify the response.
-		if err := uploadPartVerify(res, http.StatusOK); err != nil {
-			printMessage(message, err)
-			return false
+		object.Body = []byte{}
+		for partIndex, partSize := range partSizes {
+			// Spin scanBar
+			scanBar(message)
+			part := objectPart{}
+			// Create some random data to upload via multipart operations.
+			objectData := make([]byte, partSize)
+			part.PartNumber = partIndex + 1
+			part.Size = int64(len(objectData))
+			_, err := io.ReadFull(crand.Reader, objectData)
+			if err != nil {
+				printMessage(message, err)
+				return false
+			}
+			// Create a new multipart upload part request.
+			req, err := newUploadPartReq(bucketName, object.Key, object.UploadID, part.PartNumber, objectData)
+			if err != nil {
+				printMessage(message, err)
+				return false
+			}
+			// Execute the request.
+			res, err := config.execRequest("PUT", req)
+			if err != nil {
+				printMessage(message, err)
+				return false
+			}
+			// Verify the response.
+			err = uploadPartVerify(res, http.StatusOK, objectData)
+			drainAndClose(res)
+			if err != nil {
+				printMessage(message, err)
+				return false
+			}
+			object.Body = append(object.Body, objectData...)
+			// Update the ETag of the part.
+			part.ETag = strings.TrimPrefix(res.Header.Get("ETag"), "\"")
+			part.ETag = strings.TrimSuffix(part.ETag, "\"")
+			// Store the parts of the object to be completed to be listed in the list-parts test.
+			if i == 0 {
+				objectParts = append(objectParts, part)
+			}
+			// Test cleared store the uploaded parts to be completed/aborted.
+			var complPart completePart
+			complPart.ETag = part.ETag
+			complPart.PartNumber = part.PartNumber
+			// Save the completed part into the complMultiPartUpload struct.
+			complMultipartUploads[i].Parts = append(complMultipartUploads[i].Parts, complPart)
 		}
-		// Update the ETag of the part.
-		part.ETag = strings.TrimPrefix(res.Header.Get("ETag"), "\"")
-		part.ETag = strings.TrimSuffix(part.ETag, "\"")
-		// Store the parts to be listed in the list-multipart-uploads test.
-		objectParts = append(objectParts, part)
-		// Test cleared store the uploaded parts to be completed/aborted.
-		var complPart completePart
-		complPart.ETag = part.ETag
-		complPart.PartNumber = part.PartNumber
-		// Save the completed part into the complMultiPartUpload struct.
-		complMultipartUploads[i].Parts = append(complMultipartUploads[i].Parts, complPart)
 	}
 	// Spin scanBar
 	scanBar(message)
@@ -19,6 +19,8 @@ package main
 import (
 	"crypto/md5"
 	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/xml"
 	"fmt"
 	"hash"
@@ -28,10 +30,9 @@ import (
 	"net/http"
 	"net/url"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
-
-	"github.com/minio/mc/pkg/console"
 )
 
 const (
@@ -61,24 +62,23 @@ var successStatus = []int{
 
 // printMessage - Print test pass/fail messages with errors.
 func printMessage(message string, err error) {
-	// Erase the old progress line.
-	console.Eraseline()
-	if err != nil {
-		message += strings.Repeat(" ", messageWidth-len([]rune(message))) + "[FAIL]\n" + err.Error()
-		console.Println(message)
-	} else {
-		message += strings.Repeat(" ", messageWidth-len([]rune(message))) + "[OK]"
-		console.Println(message)
-	}
+	globalReporter.result(message, err)
+}
+
+// printDetail - Print extra information about the test result printed last.
+func printDetail(detail string) {
+	globalReporter.detail(detail)
 }
 
 // verifyHostReachable - Execute a simple get request against the provided endpoint to make sure its reachable.
-func verifyHostReachable(endpoint, region string) error {
+func verifyHostReachable(endpoint, region string, transport http.RoundTripper) error {
 	targetURL, err := makeTargetURL(endpoint, "", "", region, nil)
 	if err != nil {
 		return err
 	}
 	client := &http.Client{
+		// Use the same transport as the tests so any proxy is used as well.
+		Transport: transport,
 		// Only give server 3 seconds to complete the request.
 		Timeout: 3000 * time.Millisecond,
 	}
@@ -98,12 +98,16 @@ func xmlDecoder(body io.Reader, v interface{}) error {
 	return d.Decode(v)
 }
 
-// closeResponse close non nil response with any response Body.
+// Bodies with more than this left unread are closed without draining them: a new
+// connection is cheaper than reading a large object that is no longer wanted.
+const maxDrainBytes = 1024 * 1024
+
+// drainAndClose close non nil response with any response Body.
 // convenient wrapper to drain any remaining data on response body.
 //
 // Subsequently this allows golang http RoundTripper
 // to re-use the same connection for future requests. (Connection pooling).
-func closeResponse(res *http.Response) {
+func drainAndClose(res *http.Response) {
 	// Callers should close resp.Body when done reading from it.
 	// If resp.Body is not closed, the Client's underlying RoundTripper
 	// (typically Transport) may not be able to re-use a persistent TCP
@@ -113,7 +117,7 @@ func closeResponse(res *http.Response) {
 		// Without this closing connection would disallow re-using
 		// the same connection for future uses.
 		// - http://stackoverflow.com/a/17961593/4465767
-		io.Copy(ioutil.Discard, res.Body)
+		io.CopyN(ioutil.Discard, res.Body, maxDrainBytes)
 		res.Body.Close()
 	}
 }
@@ -156,6 +160,12 @@ func makeTargetURL(endpoint, bucketName, objectName, region string, queryValues
 	if isAmazonEndpoint(targetURL) { // Change host to reflect the region.
 		targetURL.Host = getS3Endpoint(region)
 	}
+	setTargetPath(targetURL, bucketName, objectName, queryValues)
+	return targetURL, nil
+}
+
+// setTargetPath - set the path and query of a URL for a request on the bucket and object.
+func setTargetPath(targetURL *url.URL, bucketName, objectName string, queryValues url.Values) {
 	targetURL.Path = "/"
 	if bucketName != "" {
 		targetURL.Path = "/" + bucketName + "/" + objectName // Use path style requests only.
@@ -163,16 +173,28 @@ func makeTargetURL(endpoint, bucketName, objectName, region string, queryValues
 	if len(queryValues) > 0 { // If there are query values include them.
 		targetURL.RawQuery = queryValues.Encode()
 	}
-	return targetURL, nil
+}
+
+// encodeCopySource - percent-encode a source bucket and object for the x-amz-copy-source header.
+// Each path segment is encoded on its own so the '/' separators stay literal.
+func encodeCopySource(bucketName, objectName string) string {
+	segments := strings.Split(bucketName+"/"+objectName, "/")
+	for i, segment := range segments {
+		// url.QueryEscape encodes spaces as '+' which servers read back as a literal '+'.
+		segments[i] = strings.Replace(url.QueryEscape(segment), "+", "%20", -1)
+	}
+	return strings.Join(segments, "/")
 }
 
 // Verify the date field of an HTTP response is formatted with HTTP time format.
 func verifyDate(respDateStr string) error {
-	_, err := time.Parse(http.TimeFormat, respDateStr)
+	date, err := time.Parse(http.TimeFormat, respDateStr)
 	if err != nil {
-		err = fmt.Errorf("Invalid time format recieved, expected http.TimeFormat")
+		err = fmt.Errorf("Invalid Date Received: wanted an RFC 1123 time in GMT such as %q, got %q", http.TimeFormat, respDateStr)
 		return err
 	}
+	// A Date far from the local clock is only warned about once the run is over.
+	globalDateSkew.observe(date, respDateStr)
 	return nil
 }
 
@@ -180,20 +202,74 @@ func verifyDate(respDateStr string) error {
 func verifyStandardHeaders(header http.Header) error {
 	// Check the date header.
 	respDateStr := header.Get("Date")
+	if respDateStr == "" {
+		err := fmt.Errorf("Missing Date Header: every response must carry an RFC 1123 Date")
+		return err
+	}
 	if err := verifyDate(respDateStr); err != nil {
 		return err
 	}
+	// Every response must be traceable on the server.
+	if header.Get("x-amz-request-id") == "" {
+		err := fmt.Errorf("Missing x-amz-request-id Header: every response must carry the id of its request")
+		return err
+	}
+	// A Content-Length, if given, must be a byte count.
+	if contentLength := header.Get("Content-Length"); contentLength != "" {
+		if size, err := strconv.ParseInt(contentLength, 10, 64); err != nil || size < 0 {
+			err := fmt.Errorf("Invalid Content-Length Received: wanted a non-negative integer, got %q", contentLength)
+			return err
+		}
+	}
 	return nil
 }
 
+// hashStrategy - selects which digests are calculated over a request body.
+type hashStrategy int
+
+const (
+	hashMD5AndSHA256 hashStrategy = iota // Calculate both MD5 and SHA256, the default.
+	hashSHA256Only                       // Skip MD5, used when Content-MD5 is disabled.
+	hashMD5Only                          // Skip SHA256, used when signing with UNSIGNED-PAYLOAD.
+)
+
+// unsignedPayload - the X-Amz-Content-Sha256 value used when the body is not hashed.
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// newHashStrategy - pick the hashing strategy from whether MD5 and SHA256 are wanted.
+func newHashStrategy(disableMD5, unsigned bool) (hashStrategy, error) {
+	switch {
+	case disableMD5 && unsigned:
+		// Disabling both would leave the body without any integrity check.
+		err := fmt.Errorf("Invalid Hash Options: --disable-md5 and --unsigned-payload cannot be used together")
+		return hashMD5AndSHA256, err
+	case disableMD5:
+		return hashSHA256Only, nil
+	case unsigned:
+		return hashMD5Only, nil
+	}
+	return hashMD5AndSHA256, nil
+}
+
 // Generate MD5 and SHA256 for an input readseeker.
 func computeHash(reader io.ReadSeeker) (md5Sum, sha256Sum []byte, contentLength int64, err error) {
-	// MD5 and SHA256 hasher.
+	return computeHashWithStrategy(reader, hashMD5AndSHA256)
+}
+
+// computeHashWithStrategy - Generate the digests selected by strategy for an input readseeker
+// in a single pass. Digests that are skipped are returned as nil.
+func computeHashWithStrategy(reader io.ReadSeeker, strategy hashStrategy) (md5Sum, sha256Sum []byte, contentLength int64, err error) {
 	var hashMD5, hashSHA256 hash.Hash
-	// MD5 and SHA256 hasher.
-	hashMD5 = md5.New()
-	hashSHA256 = sha256.New()
-	hashWriter := io.MultiWriter(hashMD5, hashSHA256)
+	var hashWriters []io.Writer
+	if strategy != hashSHA256Only {
+		hashMD5 = md5.New()
+		hashWriters = append(hashWriters, hashMD5)
+	}
+	if strategy != hashMD5Only {
+		hashSHA256 = sha256.New()
+		hashWriters = append(hashWriters, hashSHA256)
+	}
+	hashWriter := io.MultiWriter(hashWriters...)
 
 	// If no buffer is provided, no need to allocate just use io.Copy
 	contentLength, err = io.Copy(hashWriter, reader)
@@ -205,8 +281,34 @@ func computeHash(reader io.ReadSeeker) (md5Sum, sha256Sum []byte, contentLength
 		return nil, nil, 0, err
 	}
 	// Finalize md5sum and sha256sum.
-	md5Sum = hashMD5.Sum(nil)
-	sha256Sum = hashSHA256.Sum(nil)
+	if hashMD5 != nil {
+		md5Sum = hashMD5.Sum(nil)
+	}
+	if hashSHA256 != nil {
+		sha256Sum = hashSHA256.Sum(nil)
+	}
 
 	return md5Sum, sha256Sum, contentLength, nil
 }
+
+// setBodyHashHeaders - set Content-MD5 and X-Amz-Content-Sha256 from the digests calculated.
+func setBodyHashHeaders(header http.Header, md5Sum, sha256Sum []byte) {
+	if md5Sum != nil {
+		header.Set("Content-MD5", base64.StdEncoding.EncodeToString(md5Sum))
+	}
+	if sha256Sum != nil {
+		header.Set("X-Amz-Content-Sha256", hex.EncodeToString(sha256Sum))
+	} else {
+		header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	}
+}
+
+// multipartETag - calculate the composite ETag S3 assigns to a completed multipart object:
+// the MD5 of the concatenated binary MD5s of each part followed by "-" and the number of parts.
+func multipartETag(partMD5s [][]byte) string {
+	hasher := md5.New()
+	for _, partMD5 := range partMD5s {
+		hasher.Write(partMD5)
+	}
+	return fmt.Sprintf("%s-%d", hex.EncodeToString(hasher.Sum(nil)), len(partMD5s))
+}
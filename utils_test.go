@@ -0,0 +1,197 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Test the Date, x-amz-request-id and Content-Length checks every response goes through.
+func TestVerifyStandardHeaders(t *testing.T) {
+	date := time.Now().UTC().Format(http.TimeFormat)
+	testCases := []struct {
+		header http.Header
+		err    string // The exact error expected, empty if the headers are valid.
+	}{
+		// Well formed headers.
+		{
+			http.Header{"Date": {date}, "X-Amz-Request-Id": {"4442587FB7D0A2F9"}, "Content-Length": {"11"}},
+			"",
+		},
+		// Content-Length is optional.
+		{
+			http.Header{"Date": {date}, "X-Amz-Request-Id": {"4442587FB7D0A2F9"}},
+			"",
+		},
+		// Missing Date.
+		{
+			http.Header{"X-Amz-Request-Id": {"4442587FB7D0A2F9"}},
+			"Missing Date Header: every response must carry an RFC 1123 Date",
+		},
+		// An ISO 8601 Date, as used in x-amz-date, is not an HTTP date.
+		{
+			http.Header{"Date": {"20161002T120000Z"}, "X-Amz-Request-Id": {"4442587FB7D0A2F9"}},
+			`Invalid Date Received: wanted an RFC 1123 time in GMT such as "Mon, 02 Jan 2006 15:04:05 GMT", got "20161002T120000Z"`,
+		},
+		// Missing x-amz-request-id.
+		{
+			http.Header{"Date": {date}},
+			"Missing x-amz-request-id Header: every response must carry the id of its request",
+		},
+		// Negative Content-Length.
+		{
+			http.Header{"Date": {date}, "X-Amz-Request-Id": {"4442587FB7D0A2F9"}, "Content-Length": {"-1"}},
+			`Invalid Content-Length Received: wanted a non-negative integer, got "-1"`,
+		},
+		// Content-Length that is no number.
+		{
+			http.Header{"Date": {date}, "X-Amz-Request-Id": {"4442587FB7D0A2F9"}, "Content-Length": {"11 bytes"}},
+			`Invalid Content-Length Received: wanted a non-negative integer, got "11 bytes"`,
+		},
+	}
+	for i, testCase := range testCases {
+		err := verifyStandardHeaders(testCase.header)
+		if testCase.err == "" && err != nil {
+			t.Errorf("Test %d: Expected no error, got %q", i+1, err)
+		}
+		if testCase.err != "" && (err == nil || err.Error() != testCase.err) {
+			t.Errorf("Test %d: Expected error %q, got %v", i+1, testCase.err, err)
+		}
+	}
+}
+
+// Test the hashing strategy picked for each combination of --disable-md5 and --unsigned-payload.
+func TestNewHashStrategy(t *testing.T) {
+	testCases := []struct {
+		disableMD5 bool
+		unsigned   bool
+		strategy   hashStrategy
+		err        string // The exact error expected, empty if the combination is valid.
+	}{
+		{false, false, hashMD5AndSHA256, ""},
+		{true, false, hashSHA256Only, ""},
+		{false, true, hashMD5Only, ""},
+		// Both would leave uploads without any integrity check.
+		{true, true, hashMD5AndSHA256, "Invalid Hash Options: --disable-md5 and --unsigned-payload cannot be used together"},
+	}
+	for i, testCase := range testCases {
+		strategy, err := newHashStrategy(testCase.disableMD5, testCase.unsigned)
+		if testCase.err == "" && err != nil {
+			t.Errorf("Test %d: Expected no error, got %q", i+1, err)
+		}
+		if testCase.err != "" && (err == nil || err.Error() != testCase.err) {
+			t.Errorf("Test %d: Expected error %q, got %v", i+1, testCase.err, err)
+		}
+		if testCase.err == "" && strategy != testCase.strategy {
+			t.Errorf("Test %d: Expected strategy %v, got %v", i+1, testCase.strategy, strategy)
+		}
+	}
+}
+
+// Test that x-amz-copy-source values escape every path segment and keep the '/' separators literal.
+func TestEncodeCopySource(t *testing.T) {
+	testCases := []struct {
+		bucketName string
+		objectName string
+		encoded    string
+	}{
+		{"s3verify", "object", "s3verify/object"},
+		// Spaces must not become '+', a literal '+' must be escaped.
+		{"s3verify", "a b+c", "s3verify/a%20b%2Bc"},
+		// Nested keys keep their separators.
+		{"s3verify", "a/b c+d", "s3verify/a/b%20c%2Bd"},
+		{"s3verify", "dir/sub dir/%?&=", "s3verify/dir/sub%20dir/%25%3F%26%3D"},
+		{"s3verify", "日本/語", "s3verify/%E6%97%A5%E6%9C%AC/%E8%AA%9E"},
+	}
+	for i, testCase := range testCases {
+		if encoded := encodeCopySource(testCase.bucketName, testCase.objectName); encoded != testCase.encoded {
+			t.Errorf("Test %d: Expected %q, got %q", i+1, testCase.encoded, encoded)
+		}
+	}
+}
+
+// Test that the digests calculated in a single pass equal those calculated separately, that the ones
+// a strategy skips are nil and that the reader is rewound for the body to be sent.
+func TestComputeHashWithStrategy(t *testing.T) {
+	data := generatedBody(1, 1024*1024+7)
+	md5Sum := md5.Sum(data)
+	sha256Sum := sha256.Sum256(data)
+	testCases := []struct {
+		strategy  hashStrategy
+		md5Sum    []byte
+		sha256Sum []byte
+	}{
+		{hashMD5AndSHA256, md5Sum[:], sha256Sum[:]},
+		{hashSHA256Only, nil, sha256Sum[:]},
+		{hashMD5Only, md5Sum[:], nil},
+	}
+	for i, testCase := range testCases {
+		reader := bytes.NewReader(data)
+		gotMD5, gotSHA256, contentLength, err := computeHashWithStrategy(reader, testCase.strategy)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if !bytes.Equal(gotMD5, testCase.md5Sum) {
+			t.Errorf("Test %d: Expected MD5 %x, got %x", i+1, testCase.md5Sum, gotMD5)
+		}
+		if !bytes.Equal(gotSHA256, testCase.sha256Sum) {
+			t.Errorf("Test %d: Expected SHA256 %x, got %x", i+1, testCase.sha256Sum, gotSHA256)
+		}
+		if contentLength != int64(len(data)) {
+			t.Errorf("Test %d: Expected a length of %d, got %d", i+1, len(data), contentLength)
+		}
+		if sent, _ := ioutil.ReadAll(reader); !bytes.Equal(sent, data) {
+			t.Errorf("Test %d: Expected the reader to be rewound to the start of the body", i+1)
+		}
+	}
+}
+
+// Benchmark hashing a 16MiB body with each strategy, and with both digests in separate passes.
+func BenchmarkComputeHashWithStrategy(b *testing.B) {
+	data := generatedBody(1, 16*1024*1024)
+	for _, strategy := range []struct {
+		name     string
+		strategy hashStrategy
+	}{
+		{"MD5AndSHA256", hashMD5AndSHA256},
+		{"SHA256Only", hashSHA256Only},
+		{"MD5Only", hashMD5Only},
+	} {
+		b.Run(strategy.name, func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				if _, _, _, err := computeHashWithStrategy(bytes.NewReader(data), strategy.strategy); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+	// Both digests calculated one after the other, for comparison with the single pass.
+	b.Run("TwoPasses", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			md5.Sum(data)
+			sha256.Sum256(data)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/minio/cli"
+)
+
+// The levels of --verbosity, every level prints what the ones below it do.
+const (
+	verbosityQuiet   = iota // Only the summary once all tests have run.
+	verbosityNormal         // A line for every test.
+	verbosityVerbose        // A line for every request sent.
+	verbosityDebug          // The full request and response of every request.
+)
+
+// verbosityFromContext - the --verbosity asked for. --verbose on its own is the same as debug.
+func verbosityFromContext(ctx *cli.Context) (int, error) {
+	if !ctx.GlobalIsSet("verbosity") && (ctx.Bool("verbose") || ctx.GlobalBool("verbose")) {
+		return verbosityDebug, nil
+	}
+	verbosity := ctx.GlobalInt("verbosity")
+	if verbosity < verbosityQuiet || verbosity > verbosityDebug {
+		err := fmt.Errorf("Invalid Verbosity: wanted %d to %d, got %d", verbosityQuiet, verbosityDebug, verbosity)
+		return 0, err
+	}
+	return verbosity, nil
+}
+
+// redactedQueryParams - the query parameters of presigned V4 and V2 requests that carry credentials.
+var redactedQueryParams = []string{"X-Amz-Signature", "X-Amz-Credential", "X-Amz-Security-Token", "Signature", "AWSAccessKeyId"}
+
+// redactURL - u with the values of redactedQueryParams replaced, so logs can not be used to sign requests.
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	redacted := false
+	for _, name := range redactedQueryParams {
+		if _, ok := query[name]; ok {
+			query.Set(name, "REDACTED")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return u.String()
+	}
+	redactedURL := *u
+	redactedURL.RawQuery = query.Encode()
+	return redactedURL.String()
+}
+
+// logRequest - print the method, URL and outcome of a request that took elapsed, from verbose up.
+// Credentials in the query of presigned requests are redacted.
+func logRequest(req *http.Request, res *http.Response, err error, elapsed time.Duration) {
+	if globalVerbosity < verbosityVerbose {
+		return
+	}
+	outcome := ""
+	if urlErr, ok := err.(*url.Error); ok {
+		// The error repeats the unredacted URL.
+		outcome = urlErr.Err.Error()
+	} else if err != nil {
+		outcome = err.Error()
+	} else {
+		outcome = res.Status
+	}
+	globalReporter.info(fmt.Sprintf("\t%s %s %s %v", req.Method, redactURL(req.URL), outcome, elapsed-elapsed%time.Millisecond))
+}
@@ -0,0 +1,97 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// StatusMismatchError - a response carried a different status code than expected.
+type StatusMismatchError struct {
+	Expected int
+	Got      int
+}
+
+// Error - Returns the mismatch as a string.
+func (e StatusMismatchError) Error() string {
+	return fmt.Sprintf("Unexpected Response Status Code: wanted %v, got %v", e.Expected, e.Got)
+}
+
+// HeaderMismatchError - a response header had a different value than expected.
+type HeaderMismatchError struct {
+	Header   string
+	Expected string
+	Got      string
+	Detail   string // Optional explanation of why Expected was wanted.
+}
+
+// Error - Returns the mismatch as a string.
+func (e HeaderMismatchError) Error() string {
+	msg := fmt.Sprintf("Unexpected %s Received: wanted %v, got %v", e.Header, e.Expected, e.Got)
+	if e.Detail != "" {
+		msg += " (" + e.Detail + ")"
+	}
+	return msg
+}
+
+// BodyMismatchError - a response body differed from the expected body.
+// Expected and Got are hex dumps of both bodies around the first difference.
+type BodyMismatchError struct {
+	Offset         int64 // Offset of the first differing byte.
+	ExpectedLength int64
+	GotLength      int64
+	Expected       string
+	Got            string
+}
+
+// Error - Returns the mismatch as a string.
+func (e BodyMismatchError) Error() string {
+	return fmt.Sprintf("Unexpected Body Received: first difference at byte %d, wanted %d bytes, got %d\n\texpected %s\n\treceived %s",
+		e.Offset, e.ExpectedLength, e.GotLength, e.Expected, e.Got)
+}
+
+// ChecksumMismatchError - a checksum returned by the server, such as an ETag, did not match the data sent.
+type ChecksumMismatchError struct {
+	Checksum string // Name of the checksum, e.g. ETag.
+	Expected string
+	Got      string
+}
+
+// Error - Returns the mismatch as a string.
+func (e ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("Unexpected %s Received: wanted %s, got %s", e.Checksum, e.Expected, e.Got)
+}
+
+// errorCategory - the kind of mismatch err is, e.g. status, or "" if it is none of the typed mismatches.
+func errorCategory(err error) string {
+	var statusErr StatusMismatchError
+	var headerErr HeaderMismatchError
+	var bodyErr BodyMismatchError
+	var checksumErr ChecksumMismatchError
+	switch {
+	case errors.As(err, &statusErr):
+		return "status"
+	case errors.As(err, &headerErr):
+		return "header"
+	case errors.As(err, &bodyErr):
+		return "body"
+	case errors.As(err, &checksumErr):
+		return "checksum"
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// Test that the verify functions return the typed mismatch errors, and that the category recorded
+// for a failed test follows from the type.
+func TestMismatchErrors(t *testing.T) {
+	object := &ObjectInfo{Key: "s3verify/object", Body: []byte("s3verify")}
+	testCases := []struct {
+		name     string
+		err      error
+		category string
+		isType   func(error) bool
+	}{
+		{
+			name:     "StatusMismatchError",
+			err:      verifyStatusPutObject(http.StatusForbidden, http.StatusOK),
+			category: "status",
+			isType: func(err error) bool {
+				var target StatusMismatchError
+				return errors.As(err, &target) && target.Expected == http.StatusOK && target.Got == http.StatusForbidden
+			},
+		},
+		{
+			name:     "HeaderMismatchError",
+			err:      verifyObjectETag(&ObjectInfo{Encryption: encryptionSSEKMS}, ""),
+			category: "header",
+			isType: func(err error) bool {
+				var target HeaderMismatchError
+				return errors.As(err, &target) && target.Header == "ETag"
+			},
+		},
+		{
+			name:     "BodyMismatchError",
+			err:      verifyBodyEqual([]byte("s3verify"), []byte("s3verity")),
+			category: "body",
+			isType: func(err error) bool {
+				var target BodyMismatchError
+				return errors.As(err, &target) && target.Offset == 6
+			},
+		},
+		{
+			name:     "ChecksumMismatchError",
+			err:      verifyObjectETag(object, `"d41d8cd98f00b204e9800998ecf8427e"`),
+			category: "checksum",
+			isType: func(err error) bool {
+				var target ChecksumMismatchError
+				return errors.As(err, &target) && target.Got == "d41d8cd98f00b204e9800998ecf8427e"
+			},
+		},
+		{
+			// Wrapping keeps the type.
+			name:     "Wrapped StatusMismatchError",
+			err:      fmt.Errorf("PutObject: %w", verifyStatusPutObject(http.StatusNotFound, http.StatusOK)),
+			category: "status",
+			isType: func(err error) bool {
+				var target StatusMismatchError
+				return errors.As(err, &target)
+			},
+		},
+		{
+			name:     "Untyped error",
+			err:      fmt.Errorf("Unexpected Key Received"),
+			category: "",
+			isType:   func(err error) bool { return true },
+		},
+	}
+	for _, testCase := range testCases {
+		if testCase.err == nil {
+			t.Fatalf("%s: Expected an error", testCase.name)
+		}
+		if !testCase.isType(testCase.err) {
+			t.Errorf("%s: Expected the typed error with its fields, got %#v", testCase.name, testCase.err)
+		}
+		recorder := &testRecorder{}
+		recorder.start(areaObject)
+		record := recorder.record("[01/01] PutObject:", testCase.err)
+		if record.Category != testCase.category {
+			t.Errorf("%s: Expected category %q, got %q", testCase.name, testCase.category, record.Category)
+		}
+		suite := newJUnitSuite(recorder.records)
+		if failure := suite.Cases[0].Failure; failure == nil || failure.Type != testCase.category {
+			t.Errorf("%s: Expected a JUnit failure of type %q, got %+v", testCase.name, testCase.category, failure)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// getVersionVerify - GET versionID of objectName, the current version if versionID is empty, and verify
+// it holds expectedBody and reports expectedVersionID.
+func getVersionVerify(config ServerConfig, bucketName, objectName, versionID string, expectedBody []byte, expectedVersionID string) error {
+	req, err := newGetObjectReq(bucketName, objectName, nil)
+	if versionID != "" {
+		req, err = newGetObjectVersionReq(bucketName, objectName, versionID)
+	}
+	if err != nil {
+		return err
+	}
+	res, err := config.execRequest("GET", req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	if err := getObjectVerify(res, expectedBody, http.StatusOK, nil); err != nil {
+		return err
+	}
+	if received := res.Header.Get("x-amz-version-id"); received != expectedVersionID {
+		err := HeaderMismatchError{Header: "x-amz-version-id", Expected: expectedVersionID, Got: received}
+		return err
+	}
+	return nil
+}
+
+// mainVersioning - Test the lifecycle of an object in a versioned bucket: overwriting keeps the older
+// version readable by id, and a DELETE hides the object behind a delete marker without removing any version.
+func mainVersioning(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] Versioning:", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	// The versioned bucket is only created if the server implements versioning.
+	bucket, err := findBucket(capVersioned)
+	if err != nil {
+		printMessage(message, nil)
+		printDetail("Skipped: server does not support versioning")
+		return true
+	}
+	bucketName := bucket.Name
+	seed := time.Now().UnixNano()
+	older := &ObjectInfo{
+		Key:  "s3verify/versioning/lifecycle",
+		Body: generatedBody(seed, 1024),
+	}
+	newer := &ObjectInfo{
+		Key:  older.Key,
+		Body: generatedBody(seed+1, 1024),
+	}
+	for _, version := range []*ObjectInfo{older, newer} {
+		if err := putVersionedObject(config, bucketName, version); err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	if older.VersionID == newer.VersionID {
+		err := fmt.Errorf("Unexpected x-amz-version-id Received: both uploads got version id %v", newer.VersionID)
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// A plain GET returns the latest version, a GET by id the older one.
+	if err := getVersionVerify(config, bucketName, newer.Key, "", newer.Body, newer.VersionID); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := getVersionVerify(config, bucketName, older.Key, older.VersionID, older.Body, older.VersionID); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// A DELETE without a version id only inserts a delete marker.
+	markerVersionID, err := removeObjectVersion(config, bucketName, newer.Key, "", true)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	getReq, err := newGetObjectReq(bucketName, newer.Key, nil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	getRes, err := config.execRequest("GET", getReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(getRes)
+	if err := getObjectDeleteMarkerVerify(getRes, markerVersionID); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := getVersionVerify(config, bucketName, older.Key, older.VersionID, older.Body, older.VersionID); err != nil {
+		err = fmt.Errorf("After the delete marker: %v", err)
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// Remove every version, the marker last.
+	for _, version := range []*ObjectInfo{older, newer} {
+		if _, err := removeObjectVersion(config, bucketName, version.Key, version.VersionID, false); err != nil {
+			printMessage(message, err)
+			return false
+		}
+	}
+	if _, err := removeObjectVersion(config, bucketName, newer.Key, markerVersionID, true); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}
@@ -0,0 +1,193 @@
+/*
+ * Minio S3Verify Library for Amazon S3 Compatible Cloud Storage (C) 2016 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// The header that makes the website endpoint redirect requests for an object.
+const websiteRedirectHeader = "X-Amz-Website-Redirect-Location"
+
+// verifyHeaderWebsiteRedirect - verify the redirect location is returned as it was set.
+func verifyHeaderWebsiteRedirect(header http.Header, expectedLocation string) error {
+	if location := header.Get(websiteRedirectHeader); location != expectedLocation {
+		err := fmt.Errorf("Unexpected %s Received: wanted %v, got %v", websiteRedirectHeader, expectedLocation, location)
+		return err
+	}
+	return nil
+}
+
+// websiteEndpoint - the website endpoint serving bucketName, only known for AWS.
+func websiteEndpoint(config ServerConfig, bucketName string) (string, bool) {
+	endpointURL, err := url.Parse(config.Endpoint)
+	if err != nil || !isAmazonEndpoint(endpointURL) {
+		return "", false
+	}
+	return "http://" + bucketName + ".s3-website-" + config.Region + ".amazonaws.com", true
+}
+
+// verifyWebsiteRedirect - configure bucketName as a website and verify an anonymous request for objectName
+// through the website endpoint is redirected. Servers without a known website endpoint, and buckets
+// that cannot be read anonymously, are skipped since the redirect cannot be observed.
+func verifyWebsiteRedirect(config ServerConfig, bucketName, objectName, expectedLocation string) error {
+	endpoint, ok := websiteEndpoint(config, bucketName)
+	if !ok {
+		return nil
+	}
+	websiteConfig := websiteConfiguration{
+		IndexDocument: indexDocument{
+			Suffix: "index.html",
+		},
+	}
+	req, err := newPutBucketWebsiteReq(bucketName, websiteConfig)
+	if err != nil {
+		return err
+	}
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(res)
+	if err := putBucketWebsiteVerify(res, http.StatusOK); err != nil {
+		return err
+	}
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		// The redirect itself is what is being verified.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	websiteRes, err := client.Get(endpoint + "/" + objectName)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(websiteRes)
+	if websiteRes.StatusCode == http.StatusForbidden {
+		// Public access to the bucket is blocked.
+		return nil
+	}
+	if websiteRes.StatusCode != http.StatusMovedPermanently {
+		err := fmt.Errorf("Unexpected Website Status Received: wanted %v, got %v", http.StatusMovedPermanently, websiteRes.StatusCode)
+		return err
+	}
+	if location := websiteRes.Header.Get("Location"); location != expectedLocation {
+		err := fmt.Errorf("Unexpected Website Redirect Received: wanted %v, got %v", expectedLocation, location)
+		return err
+	}
+	return nil
+}
+
+// mainPutObjectWebsiteRedirect - verify x-amz-website-redirect-location is stored with the object and honored
+// by the website endpoint.
+func mainPutObjectWebsiteRedirect(config ServerConfig, curTest int) bool {
+	message := fmt.Sprintf("[%02d/%d] PutObject (Website Redirect):", curTest, globalTotalNumTest)
+	// Spin scanBar
+	scanBar(message)
+	// Configuring a website must not affect the s3verify buckets so use a new one.
+	bucketName := "s3verify-" + globalSuffix + "-website"
+	if err := putBucket(config, bucketName); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	object := &ObjectInfo{
+		Key:  "s3verify/website/redirect",
+		Body: []byte("s3verify website redirect"),
+	}
+	redirectLocation := "/s3verify/website/target"
+	// Spin scanBar
+	scanBar(message)
+	req, err := newPutObjectReq(bucketName, object.Key, object.Body)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	req.customHeader.Set(websiteRedirectHeader, redirectLocation)
+	res, err := config.execRequest("PUT", req)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(res)
+	if err := putObjectVerify(res, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// The redirect location is returned as part of the object's metadata.
+	headReq, err := newHeadObjectReq(bucketName, object.Key)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	headRes, err := config.execRequest("HEAD", headReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(headRes)
+	if err := headObjectVerify(headRes, http.StatusOK); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyHeaderWebsiteRedirect(headRes.Header, redirectLocation); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	// The REST endpoint must still serve the object itself rather than redirect.
+	getReq, err := newGetObjectReq(bucketName, object.Key, nil)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	getRes, err := config.execRequest("GET", getReq)
+	if err != nil {
+		printMessage(message, err)
+		return false
+	}
+	defer drainAndClose(getRes)
+	if err := getObjectVerify(getRes, object.Body, http.StatusOK, nil); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	if err := verifyHeaderWebsiteRedirect(getRes.Header, redirectLocation); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := verifyWebsiteRedirect(config, bucketName, object.Key, redirectLocation); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Spin scanBar
+	scanBar(message)
+	if err := removeBucketWithContents(config, bucketName); err != nil {
+		printMessage(message, err)
+		return false
+	}
+	// Test passed.
+	printMessage(message, nil)
+	return true
+}